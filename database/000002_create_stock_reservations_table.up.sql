@@ -0,0 +1,14 @@
+CREATE TABLE stock_reservations (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    order_reference VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    -- active, committed, released ou expired
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_stock_reservations_active_product ON stock_reservations (product_id) WHERE status = 'active';
+CREATE INDEX idx_stock_reservations_active_expires_at ON stock_reservations (expires_at) WHERE status = 'active';
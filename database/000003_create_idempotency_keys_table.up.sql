@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    -- SHA-256 do método, caminho e corpo do primeiro pedido feito com a chave.
+    request_hash CHAR(64) NOT NULL,
+    -- Nulos enquanto o primeiro pedido ainda está a ser processado.
+    status_code INT,
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys (created_at);
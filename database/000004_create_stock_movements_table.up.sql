@@ -0,0 +1,25 @@
+CREATE TABLE stock_movements (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    delta INT NOT NULL,
+    -- Stock em mão do produto depois de aplicado o movimento.
+    balance INT NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    reference VARCHAR(255),
+    actor_id VARCHAR(255),
+    correlation_id VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_stock_movements_product_created_at ON stock_movements (product_id, created_at DESC, id DESC);
+
+-- Os movimentos são imutáveis: só podem ser inseridos.
+CREATE FUNCTION prevent_stock_movement_update() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements rows are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movements_immutable
+    BEFORE UPDATE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION prevent_stock_movement_update();
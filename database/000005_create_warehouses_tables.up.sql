@@ -0,0 +1,24 @@
+CREATE TABLE warehouses (
+    id UUID PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    -- Menor valor = maior prioridade na estratégia de alocação por prioridade.
+    priority INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Stock por local. products.stock continua a ser o total em mão; a diferença
+-- entre esse total e a soma dos locais é stock ainda não atribuído a um armazém.
+CREATE TABLE warehouse_stock (
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INT NOT NULL CHECK (quantity >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (warehouse_id, product_id)
+);
+
+CREATE INDEX idx_warehouse_stock_product ON warehouse_stock (product_id);
+
+ALTER TABLE stock_movements ADD COLUMN warehouse_id UUID REFERENCES warehouses(id);
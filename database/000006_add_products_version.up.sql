@@ -0,0 +1,2 @@
+-- Incrementado a cada escrita no produto; usado como ETag para controlo de concorrência otimista.
+ALTER TABLE products ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+-- Vetor de pesquisa mantido pelo próprio Postgres sempre que o nome ou a
+-- descrição mudam. Guarda os radicais em português e em inglês; o nome pesa
+-- mais (A) do que a descrição (B) na relevância.
+ALTER TABLE products ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('portuguese', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+    setweight(to_tsvector('portuguese', COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_products_search_vector ON products USING GIN (search_vector);
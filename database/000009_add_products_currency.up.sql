@@ -0,0 +1,4 @@
+-- O preço passa a ter moeda (ISO 4217). A escala 4 comporta moedas com até 3
+-- casas decimais; a validação das casas de cada moeda é feita na aplicação.
+ALTER TABLE products ALTER COLUMN price TYPE NUMERIC(19, 4);
+ALTER TABLE products ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'BRL';
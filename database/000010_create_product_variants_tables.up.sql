@@ -0,0 +1,27 @@
+CREATE TABLE product_options (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    -- Ordem da opção na apresentação do produto.
+    position INT NOT NULL,
+    option_values TEXT[] NOT NULL,
+    PRIMARY KEY (product_id, name)
+);
+
+CREATE TABLE product_variants (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL,
+    -- Valor escolhido para cada opção do produto, ex: {"tamanho": "M", "cor": "azul"}.
+    options JSONB NOT NULL,
+    -- Sem preço próprio, a variante usa o preço do produto.
+    price NUMERIC(19, 4),
+    currency CHAR(3),
+    stock INT NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT product_variants_sku_key UNIQUE (sku),
+    CONSTRAINT product_variants_options_key UNIQUE (product_id, options),
+    CHECK ((price IS NULL) = (currency IS NULL))
+);
+
+ALTER TABLE stock_movements ADD COLUMN variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE;
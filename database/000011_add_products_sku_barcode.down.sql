@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS products_barcode_key;
+DROP INDEX IF EXISTS products_sku_key;
+ALTER TABLE products DROP COLUMN IF EXISTS barcode;
+ALTER TABLE products DROP COLUMN IF EXISTS sku;
@@ -0,0 +1,10 @@
+ALTER TABLE products ADD COLUMN sku VARCHAR(64);
+ALTER TABLE products ADD COLUMN barcode VARCHAR(14);
+
+-- Os produtos já existentes recebem o próprio ID como SKU até serem atualizados.
+UPDATE products SET sku = id::text;
+ALTER TABLE products ALTER COLUMN sku SET NOT NULL;
+
+CREATE UNIQUE INDEX products_sku_key ON products (sku);
+-- UPC-A, EAN-13 e GTIN-14 do mesmo artigo são o mesmo GTIN depois de completados com zeros à esquerda.
+CREATE UNIQUE INDEX products_barcode_key ON products (LPAD(barcode, 14, '0')) WHERE barcode IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Os produtos já existentes continuam visíveis; os novos são criados como rascunho pela aplicação.
+ALTER TABLE products ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('draft', 'active', 'archived'));
+
+CREATE INDEX idx_products_status ON products (status);
@@ -0,0 +1,4 @@
+-- Os produtos removidos ficam marcados até serem purgados, para que os pedidos antigos continuem a encontrá-los.
+ALTER TABLE products ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_products_deleted_at ON products (deleted_at) WHERE deleted_at IS NOT NULL;
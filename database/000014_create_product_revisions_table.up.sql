@@ -0,0 +1,12 @@
+CREATE TABLE product_revisions (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    -- Produto completo tal como ficou depois da escrita.
+    snapshot JSONB NOT NULL,
+    actor_id VARCHAR(255),
+    correlation_id VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT product_revisions_revision_key UNIQUE (product_id, revision)
+);
@@ -0,0 +1,38 @@
+CREATE TABLE price_history (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price NUMERIC(19, 4) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    previous_price NUMERIC(19, 4),
+    previous_currency CHAR(3),
+    reason VARCHAR(32) NOT NULL,
+    schedule_id UUID,
+    actor_id VARCHAR(255),
+    correlation_id VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_price_history_product_created_at ON price_history (product_id, created_at DESC, id DESC);
+
+-- O preço atual de cada produto existente é o ponto de partida do histórico.
+INSERT INTO price_history (id, product_id, price, currency, reason, created_at)
+SELECT gen_random_uuid(), id, price, currency, 'initial', updated_at FROM products;
+
+CREATE TABLE price_schedules (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    price NUMERIC(19, 4) NOT NULL CHECK (price > 0),
+    currency CHAR(3) NOT NULL,
+    effective_from TIMESTAMPTZ NOT NULL,
+    -- NULL torna a mudança de preço permanente.
+    effective_to TIMESTAMPTZ,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'completed', 'cancelled')),
+    previous_price NUMERIC(19, 4),
+    previous_currency CHAR(3),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE INDEX idx_price_schedules_product ON price_schedules (product_id, effective_from);
+CREATE INDEX idx_price_schedules_open ON price_schedules (status, effective_from) WHERE status IN ('pending', 'active');
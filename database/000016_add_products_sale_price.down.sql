@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_products_sale;
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS products_sale_window_check,
+    DROP COLUMN IF EXISTS sale_ends_at,
+    DROP COLUMN IF EXISTS sale_starts_at,
+    DROP COLUMN IF EXISTS sale_price;
@@ -0,0 +1,8 @@
+-- O preço promocional usa a moeda do produto.
+ALTER TABLE products
+    ADD COLUMN sale_price NUMERIC(19, 4) CHECK (sale_price > 0),
+    ADD COLUMN sale_starts_at TIMESTAMPTZ,
+    ADD COLUMN sale_ends_at TIMESTAMPTZ,
+    ADD CONSTRAINT products_sale_window_check CHECK (sale_starts_at IS NULL OR sale_ends_at IS NULL OR sale_ends_at > sale_starts_at);
+
+CREATE INDEX idx_products_sale ON products (sale_starts_at, sale_ends_at) WHERE sale_price IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS price_list_tiers;
+DROP TABLE IF EXISTS price_lists;
@@ -0,0 +1,19 @@
+CREATE TABLE price_lists (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    customer_group VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Cada escalão vale a partir de min_quantity unidades até ao escalão seguinte.
+CREATE TABLE price_list_tiers (
+    price_list_id UUID NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    min_quantity INT NOT NULL CHECK (min_quantity >= 1),
+    price NUMERIC(19, 4) NOT NULL CHECK (price > 0),
+    currency CHAR(3) NOT NULL,
+    PRIMARY KEY (price_list_id, product_id, min_quantity)
+);
+
+CREATE INDEX idx_price_list_tiers_product ON price_list_tiers (product_id);
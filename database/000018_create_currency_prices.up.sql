@@ -0,0 +1,18 @@
+-- Preços explícitos de um produto noutras moedas além da sua moeda base (products.currency).
+CREATE TABLE product_prices (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    price NUMERIC(19, 4) NOT NULL CHECK (price > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (product_id, currency)
+);
+
+-- Uma unidade de base_currency vale rate unidades de currency.
+CREATE TABLE exchange_rates (
+    base_currency CHAR(3) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (base_currency, currency),
+    CHECK (base_currency <> currency)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS bundle_components;
+ALTER TABLE products DROP COLUMN IF EXISTS type;
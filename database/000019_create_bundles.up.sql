@@ -0,0 +1,13 @@
+-- Um kit (bundle) não tem stock próprio: o stock disponível é calculado a partir dos componentes.
+ALTER TABLE products ADD COLUMN type VARCHAR(16) NOT NULL DEFAULT 'simple' CHECK (type IN ('simple', 'bundle'));
+
+-- Os componentes não podem ser purgados enquanto fizerem parte de um kit.
+CREATE TABLE bundle_components (
+    bundle_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    component_id UUID NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (bundle_id, component_id),
+    CHECK (bundle_id <> component_id)
+);
+
+CREATE INDEX idx_bundle_components_component ON bundle_components (component_id);
@@ -0,0 +1,14 @@
+-- Definições de atributos por categoria; aplicam-se também aos produtos das subcategorias.
+CREATE TABLE category_attributes (
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('text', 'number', 'boolean', 'enum')),
+    unit VARCHAR(20) NOT NULL DEFAULT '',
+    allowed_values TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category_id, name)
+);
+
+-- Valores dos atributos do produto, por nome; são validados na aplicação contra as definições.
+ALTER TABLE products ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,13 @@
+-- Galeria de imagens dos produtos. Os ficheiros ficam no armazenamento, identificados por storage_key.
+CREATE TABLE product_media (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    alt_text VARCHAR(255) NOT NULL DEFAULT '',
+    position INT NOT NULL CHECK (position >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_product_media_product ON product_media (product_id, position);
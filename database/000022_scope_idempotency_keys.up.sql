@@ -0,0 +1,6 @@
+-- As chaves passam a pertencer a quem as usou: o utilizador autenticado ou os serviços internos.
+-- As chaves existentes ficam sem dono e deixam de ser repetidas; expiram com a retenção normal.
+ALTER TABLE idempotency_keys ADD COLUMN principal VARCHAR(300) NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys ALTER COLUMN principal DROP DEFAULT;
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (principal, key);
@@ -0,0 +1,10 @@
+DROP TRIGGER stock_movements_immutable ON stock_movements;
+CREATE TRIGGER stock_movements_immutable
+    BEFORE UPDATE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION prevent_stock_movement_update();
+
+-- Os movimentos de produtos já purgados não têm para onde apontar.
+DELETE FROM stock_movements m WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.id = m.product_id);
+DELETE FROM stock_movements m WHERE m.variant_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.id = m.variant_id);
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_variant_id_fkey FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE CASCADE;
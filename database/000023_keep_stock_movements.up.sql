@@ -0,0 +1,9 @@
+-- O histórico de stock sobrevive à purga dos produtos removidos: product_id e
+-- variant_id passam a ser colunas simples, e nenhum movimento pode ser apagado.
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_product_id_fkey;
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_variant_id_fkey;
+
+DROP TRIGGER stock_movements_immutable ON stock_movements;
+CREATE TRIGGER stock_movements_immutable
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION prevent_stock_movement_update();
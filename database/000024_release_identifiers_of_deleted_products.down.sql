@@ -0,0 +1,5 @@
+DROP INDEX products_sku_key;
+DROP INDEX products_barcode_key;
+
+CREATE UNIQUE INDEX products_sku_key ON products (sku);
+CREATE UNIQUE INDEX products_barcode_key ON products (LPAD(barcode, 14, '0')) WHERE barcode IS NOT NULL;
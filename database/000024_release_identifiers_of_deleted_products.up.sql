@@ -0,0 +1,7 @@
+-- O SKU e o código de barras de um produto removido ficam livres para outros
+-- produtos; o restauro falha se entretanto tiverem sido reutilizados.
+DROP INDEX products_sku_key;
+DROP INDEX products_barcode_key;
+
+CREATE UNIQUE INDEX products_sku_key ON products (sku) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX products_barcode_key ON products (LPAD(barcode, 14, '0')) WHERE barcode IS NOT NULL AND deleted_at IS NULL;
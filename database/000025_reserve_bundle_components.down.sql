@@ -0,0 +1,2 @@
+DELETE FROM stock_reservations WHERE bundle_reservation_id IS NOT NULL;
+ALTER TABLE stock_reservations DROP COLUMN bundle_reservation_id;
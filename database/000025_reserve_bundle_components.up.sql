@@ -0,0 +1,5 @@
+-- A reserva de um kit guarda uma reserva por componente, ligada à do kit,
+-- para que as unidades reservadas deixem de contar no stock de cada componente.
+ALTER TABLE stock_reservations ADD COLUMN bundle_reservation_id UUID REFERENCES stock_reservations(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_stock_reservations_bundle_reservation ON stock_reservations (bundle_reservation_id) WHERE bundle_reservation_id IS NOT NULL;
@@ -0,0 +1,9 @@
+DROP TRIGGER price_history_immutable ON price_history;
+DROP TRIGGER product_revisions_immutable ON product_revisions;
+DROP FUNCTION prevent_history_change();
+
+-- O histórico de produtos já purgados não tem para onde apontar.
+DELETE FROM price_history h WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.id = h.product_id);
+DELETE FROM product_revisions r WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.id = r.product_id);
+ALTER TABLE price_history ADD CONSTRAINT price_history_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+ALTER TABLE product_revisions ADD CONSTRAINT product_revisions_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
@@ -0,0 +1,18 @@
+-- O histórico de preços e as revisões sobrevivem à purga dos produtos
+-- removidos, como os movimentos de stock: product_id passa a ser uma coluna
+-- simples, e nenhuma linha pode ser alterada ou apagada.
+ALTER TABLE price_history DROP CONSTRAINT price_history_product_id_fkey;
+ALTER TABLE product_revisions DROP CONSTRAINT product_revisions_product_id_fkey;
+
+CREATE FUNCTION prevent_history_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION '% rows are immutable', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER price_history_immutable
+    BEFORE UPDATE OR DELETE ON price_history
+    FOR EACH ROW EXECUTE FUNCTION prevent_history_change();
+CREATE TRIGGER product_revisions_immutable
+    BEFORE UPDATE OR DELETE ON product_revisions
+    FOR EACH ROW EXECUTE FUNCTION prevent_history_change();
@@ -0,0 +1,3 @@
+DROP INDEX idx_product_variants_sku;
+
+ALTER TABLE product_variants ADD CONSTRAINT product_variants_sku_key UNIQUE (sku);
@@ -0,0 +1,6 @@
+-- Os SKUs das variantes de um produto removido ficam livres como o do
+-- produto. A unicidade depende do estado do produto, noutra tabela, e passa a
+-- ser garantida pela aplicação (claimSKU); o índice fica só para as pesquisas.
+ALTER TABLE product_variants DROP CONSTRAINT product_variants_sku_key;
+
+CREATE INDEX idx_product_variants_sku ON product_variants (sku);
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type AttributeHandler struct {
+	service service.AttributeService
+}
+
+type SetAttributeDefinitionRequest struct {
+	Type          domain.AttributeType `json:"type"`
+	Unit          string               `json:"unit"`
+	AllowedValues []string             `json:"allowed_values"`
+	Required      bool                 `json:"required"`
+}
+
+func NewAttributeHandler(svc service.AttributeService) *AttributeHandler {
+	return &AttributeHandler{service: svc}
+}
+
+func (h *AttributeHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	definitions, err := h.service.ListDefinitions(r.Context(), categoryID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, definitions)
+}
+
+func (h *AttributeHandler) HandleSet(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req SetAttributeDefinitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	definition := &domain.AttributeDefinition{
+		CategoryID:    categoryID,
+		Name:          chi.URLParam(r, "name"),
+		Type:          req.Type,
+		Unit:          req.Unit,
+		AllowedValues: req.AllowedValues,
+		Required:      req.Required,
+	}
+	if err := h.service.SetDefinition(r.Context(), definition); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, definition)
+}
+
+func (h *AttributeHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteDefinition(r.Context(), categoryID, chi.URLParam(r, "name")); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Attribute definition deleted successfully"})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleSetAttribute_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.AttributeServiceMock)
+	handler := NewAttributeHandler(mockService)
+
+	categoryID := uuid.New()
+	requestBody := `{"type": "enum", "allowed_values": ["inox", "vidro"], "required": true}`
+	req := httptest.NewRequest(http.MethodPut, "/categories/"+categoryID.String()+"/attributes/material", bytes.NewBufferString(requestBody))
+	req = withURLParam(withURLParam(req, "id", categoryID.String()), "name", "material")
+	rr := httptest.NewRecorder()
+
+	// Mock: A definição chega ao serviço com a categoria e o nome do caminho.
+	mockService.On("SetDefinition", mock.Anything, mock.MatchedBy(func(d *domain.AttributeDefinition) bool {
+		return d.CategoryID == categoryID && d.Name == "material" && d.Type == domain.AttributeEnum && d.Required && len(d.AllowedValues) == 2
+	})).Return(nil)
+
+	// Act: Chama o handler.
+	handler.HandleSet(rr, req)
+
+	// Assert: Verifica se a definição gravada é devolvida.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response domain.AttributeDefinition
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, []string{"inox", "vidro"}, response.AllowedValues)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetAttribute_InvalidDefinition(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.AttributeServiceMock)
+	handler := NewAttributeHandler(mockService)
+
+	categoryID := uuid.New()
+	req := httptest.NewRequest(http.MethodPut, "/categories/"+categoryID.String()+"/attributes/voltage", bytes.NewBufferString(`{"type": "decimal"}`))
+	req = withURLParam(withURLParam(req, "id", categoryID.String()), "name", "voltage")
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço rejeita um tipo desconhecido.
+	mockService.On("SetDefinition", mock.Anything, mock.Anything).
+		Return(fmt.Errorf("Error when setting attribute definition: %w", domain.ErrInvalidAttributeDefinition))
+
+	// Act: Chama o handler.
+	handler.HandleSet(rr, req)
+
+	// Assert: Verifica se responde com erro de validação.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleListAttributes_CategoryNotFound(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.AttributeServiceMock)
+	handler := NewAttributeHandler(mockService)
+
+	categoryID := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/categories/"+categoryID.String()+"/attributes", nil), "id", categoryID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: A categoria não existe.
+	mockService.On("ListDefinitions", mock.Anything, categoryID).
+		Return(nil, fmt.Errorf("Error when listing attribute definitions: %w", domain.ErrCategoryNotFound))
+
+	// Act: Chama o handler.
+	handler.HandleList(rr, req)
+
+	// Assert: Verifica se responde 404.
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleDeleteAttribute_NotFound(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.AttributeServiceMock)
+	handler := NewAttributeHandler(mockService)
+
+	categoryID := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/categories/"+categoryID.String()+"/attributes/voltage", nil)
+	req = withURLParam(withURLParam(req, "id", categoryID.String()), "name", "voltage")
+	rr := httptest.NewRecorder()
+
+	// Mock: A categoria não tem o atributo.
+	mockService.On("DeleteDefinition", mock.Anything, categoryID, "voltage").
+		Return(fmt.Errorf("Error when deleting attribute definition: %w", domain.ErrAttributeNotFound))
+
+	// Act: Chama o handler.
+	handler.HandleDelete(rr, req)
+
+	// Assert: Verifica se responde 404 com o código do atributo.
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	var response ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "ATTRIBUTE_NOT_FOUND", response.Code)
+	mockService.AssertExpectations(t)
+}
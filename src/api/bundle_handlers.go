@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/service"
+)
+
+type BundleHandler struct {
+	service service.BundleService
+}
+
+type SetBundleComponentsRequest struct {
+	Components []domain.BundleComponent `json:"components"`
+}
+
+func NewBundleHandler(svc service.BundleService) *BundleHandler {
+	return &BundleHandler{service: svc}
+}
+
+func (h *BundleHandler) HandleSetComponents(w http.ResponseWriter, r *http.Request) {
+	bundleID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req SetBundleComponentsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	components, err := h.service.SetComponents(r.Context(), bundleID, req.Components)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, components)
+}
+
+// HandleListComponents é a leitura pública, que só mostra os componentes de kits ativos.
+func (h *BundleHandler) HandleListComponents(w http.ResponseWriter, r *http.Request) {
+	h.listComponents(w, r, domain.StatusActive)
+}
+
+// HandleAdminListComponents lê os componentes do kit em qualquer estado.
+func (h *BundleHandler) HandleAdminListComponents(w http.ResponseWriter, r *http.Request) {
+	h.listComponents(w, r, "")
+}
+
+func (h *BundleHandler) listComponents(w http.ResponseWriter, r *http.Request, status domain.ProductStatus) {
+	bundleID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	components, err := h.service.ListComponents(r.Context(), bundleID, status)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, components)
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleSetComponents_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.BundleServiceMock)
+	handler := NewBundleHandler(mockService)
+
+	bundleID, componentID := uuid.New(), uuid.New()
+	requestBody := fmt.Sprintf(`{"components": [{"product_id": "%s", "quantity": 2}]}`, componentID)
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/"+bundleID.String()+"/components", bytes.NewBufferString(requestBody)), "id", bundleID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço grava os componentes e devolve-os com o stock disponível.
+	components := []domain.BundleComponent{{ProductID: componentID, Quantity: 2}}
+	saved := []domain.BundleComponent{{ProductID: componentID, Quantity: 2, SKU: "SKU-COMP", Name: "Componente", AvailableStock: 7}}
+	mockService.On("SetComponents", mock.Anything, bundleID, components).Return(saved, nil)
+
+	// Act: Chama o handler.
+	handler.HandleSetComponents(rr, req)
+
+	// Assert: Verifica se os componentes gravados são devolvidos.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []domain.BundleComponent
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, saved, response)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetComponents_InvalidComponents(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.BundleServiceMock)
+	handler := NewBundleHandler(mockService)
+
+	bundleID := uuid.New()
+	requestBody := fmt.Sprintf(`{"components": [{"product_id": "%s", "quantity": 1}]}`, bundleID)
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/"+bundleID.String()+"/components", bytes.NewBufferString(requestBody)), "id", bundleID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço rejeita um kit que se contém a si próprio.
+	mockService.On("SetComponents", mock.Anything, bundleID, mock.Anything).
+		Return(nil, fmt.Errorf("Error when setting bundle components: %w", domain.ErrInvalidBundleComponents))
+
+	// Act: Chama o handler.
+	handler.HandleSetComponents(rr, req)
+
+	// Assert: Verifica se responde com erro de validação.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetComponents_ProductHasStock(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.BundleServiceMock)
+	handler := NewBundleHandler(mockService)
+
+	bundleID := uuid.New()
+	requestBody := fmt.Sprintf(`{"components": [{"product_id": "%s", "quantity": 1}]}`, uuid.New())
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/"+bundleID.String()+"/components", bytes.NewBufferString(requestBody)), "id", bundleID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O produto ainda tem stock próprio e não pode passar a kit.
+	mockService.On("SetComponents", mock.Anything, bundleID, mock.Anything).
+		Return(nil, fmt.Errorf("Error when setting bundle components: %w", domain.ErrBundleStock))
+
+	// Act: Chama o handler.
+	handler.HandleSetComponents(rr, req)
+
+	// Assert: Verifica se responde com conflito.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var response ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "BUNDLE_STOCK", response.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleListComponents_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.BundleServiceMock)
+	handler := NewBundleHandler(mockService)
+
+	bundleID := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/"+bundleID.String()+"/components", nil), "id", bundleID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O kit ativo tem dois componentes.
+	components := []domain.BundleComponent{
+		{ProductID: uuid.New(), Quantity: 1, SKU: "SKU-A", Name: "A", AvailableStock: 3},
+		{ProductID: uuid.New(), Quantity: 2, SKU: "SKU-B", Name: "B", AvailableStock: 10},
+	}
+	mockService.On("ListComponents", mock.Anything, bundleID, domain.StatusActive).Return(components, nil)
+
+	// Act: Chama o handler.
+	handler.HandleListComponents(rr, req)
+
+	// Assert: Verifica se devolve os componentes.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []domain.BundleComponent
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleListComponents_InvalidID(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.BundleServiceMock)
+	handler := NewBundleHandler(mockService)
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/abc/components", nil), "id", "abc")
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler.
+	handler.HandleListComponents(rr, req)
+
+	// Assert: Verifica se o serviço não é chamado.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "ListComponents", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleAdminListComponents_AnyStatus(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.BundleServiceMock)
+	handler := NewBundleHandler(mockService)
+
+	bundleID := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/products/"+bundleID.String()+"/components", nil), "id", bundleID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: A leitura de administração não filtra pelo estado do kit.
+	mockService.On("ListComponents", mock.Anything, bundleID, domain.ProductStatus("")).Return([]domain.BundleComponent{}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleAdminListComponents(rr, req)
+
+	// Assert: Verifica se o serviço foi chamado sem filtro.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/service"
+
+	"github.com/google/uuid"
+)
+
+type CategoryHandler struct {
+	service service.CategoryService
+}
+
+type CategoryRequest struct {
+	ParentID *uuid.UUID `json:"parent_id"`
+	Name     string     `json:"name"`
+	Slug     string     `json:"slug"`
+	Position int        `json:"position"`
+}
+
+type SetProductCategoriesRequest struct {
+	CategoryIDs []uuid.UUID `json:"category_ids"`
+}
+
+func NewCategoryHandler(svc service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{service: svc}
+}
+
+func (h *CategoryHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	category := &domain.Category{ParentID: req.ParentID, Name: req.Name, Slug: req.Slug, Position: req.Position}
+	if err := h.service.Create(r.Context(), category); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, category)
+}
+
+func (h *CategoryHandler) HandleGetTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.service.GetCategoryTree(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, tree)
+}
+
+func (h *CategoryHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	category, err := h.service.GetCategoryByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, category)
+}
+
+func (h *CategoryHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	category := &domain.Category{ID: id, ParentID: req.ParentID, Name: req.Name, Slug: req.Slug, Position: req.Position}
+	if err := h.service.Update(r.Context(), category); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, category)
+}
+
+func (h *CategoryHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Category deleted successfully"})
+}
+
+func (h *CategoryHandler) HandleSetProductCategories(w http.ResponseWriter, r *http.Request) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req SetProductCategoriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	if err := h.service.SetProductCategories(r.Context(), productID, req.CategoryIDs); err != nil {
+		handleError(w, err)
+		return
+	}
+	h.HandleAdminListProductCategories(w, r)
+}
+
+// HandleListProductCategories é a leitura pública, que só mostra produtos ativos.
+func (h *CategoryHandler) HandleListProductCategories(w http.ResponseWriter, r *http.Request) {
+	h.listProductCategories(w, r, domain.StatusActive)
+}
+
+// HandleAdminListProductCategories lê as categorias do produto em qualquer estado.
+func (h *CategoryHandler) HandleAdminListProductCategories(w http.ResponseWriter, r *http.Request) {
+	h.listProductCategories(w, r, "")
+}
+
+func (h *CategoryHandler) listProductCategories(w http.ResponseWriter, r *http.Request, status domain.ProductStatus) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	categories, err := h.service.ListProductCategories(r.Context(), productID, status)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, categories)
+}
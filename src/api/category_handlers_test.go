@@ -0,0 +1,201 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/config"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleCreateCategory_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.CategoryServiceMock)
+	handler := NewCategoryHandler(mockService)
+
+	parentID := uuid.New()
+	requestBody := `{"parent_id": "` + parentID.String() + `", "name": "Canecas", "position": 2}`
+	req := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço preenche o ID e o slug da categoria criada.
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(c *domain.Category) bool {
+		return *c.ParentID == parentID && c.Name == "Canecas" && c.Position == 2
+	})).Run(func(args mock.Arguments) {
+		category := args.Get(1).(*domain.Category)
+		category.ID = uuid.New()
+		category.Slug = "canecas"
+	}).Return(nil)
+
+	// Act: Chama o handler.
+	handler.HandleCreate(rr, req)
+
+	// Assert: Verifica se a categoria criada é devolvida com status 201.
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body domain.Category
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Equal(t, "canecas", body.Slug)
+}
+
+func TestHandleUpdateCategory_Cycle(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.CategoryServiceMock)
+	handler := NewCategoryHandler(mockService)
+
+	categoryID := uuid.New()
+	requestBody := `{"parent_id": "` + uuid.New().String() + `", "name": "Cozinha"}`
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/categories/"+categoryID.String(), bytes.NewBufferString(requestBody)), "id", categoryID.String())
+	rr := httptest.NewRecorder()
+
+	mockService.On("Update", mock.Anything, mock.Anything).Return(domain.ErrCategoryCycle)
+
+	// Act: Chama o handler.
+	handler.HandleUpdate(rr, req)
+
+	// Assert: Verifica se mover a categoria para baixo de si própria devolve 409.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "CATEGORY_CYCLE")
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleGetCategoryTree_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.CategoryServiceMock)
+	handler := NewCategoryHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories", nil)
+	rr := httptest.NewRecorder()
+
+	parent := &domain.Category{ID: uuid.New(), Name: "Casa", Slug: "casa"}
+	child := &domain.Category{ID: uuid.New(), ParentID: &parent.ID, Name: "Cozinha", Slug: "cozinha"}
+	mockService.On("GetCategoryTree", mock.Anything).Return(domain.BuildCategoryTree([]*domain.Category{parent, child}), nil)
+
+	// Act: Chama o handler.
+	handler.HandleGetTree(rr, req)
+
+	// Assert: Verifica se os filhos vêm aninhados no pai.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body []struct {
+		Slug     string `json:"slug"`
+		Children []struct {
+			Slug string `json:"slug"`
+		} `json:"children"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Len(t, body, 1)
+	assert.Equal(t, "casa", body[0].Slug)
+	assert.Len(t, body[0].Children, 1)
+	assert.Equal(t, "cozinha", body[0].Children[0].Slug)
+}
+
+func TestHandleSetProductCategories_CategoryNotFound(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.CategoryServiceMock)
+	handler := NewCategoryHandler(mockService)
+
+	productID, categoryID := uuid.New(), uuid.New()
+	requestBody := `{"category_ids": ["` + categoryID.String() + `"]}`
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/"+productID.String()+"/categories", bytes.NewBufferString(requestBody)), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	mockService.On("SetProductCategories", mock.Anything, productID, []uuid.UUID{categoryID}).Return(domain.ErrCategoryNotFound)
+
+	// Act: Chama o handler.
+	handler.HandleSetProductCategories(rr, req)
+
+	// Assert: Verifica se uma categoria inexistente devolve 404.
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleListByCategory_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço de produtos e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	categoryID := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/categories/"+categoryID.String()+"/products?limit=20", nil), "id", categoryID.String())
+	rr := httptest.NewRecorder()
+
+	mockService.On("ListProducts", mock.Anything, mock.MatchedBy(func(q domain.ProductQuery) bool {
+		return q.CategoryID != nil && *q.CategoryID == categoryID && q.Limit == 20
+	})).Return(&domain.ProductPage{Items: []*domain.Product{}}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleListByCategory(rr, req)
+
+	// Assert: Verifica se a categoria da URL é usada como filtro.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleCategoryFacets_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço de produtos e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{DefaultCurrency: "BRL"})
+
+	categoryID := uuid.New()
+	target := "/categories/" + categoryID.String() + "/facets?attr.marca=Acme&attr.cor=vermelho&attr.cor=azul&price_interval=100"
+	req := withURLParam(httptest.NewRequest(http.MethodGet, target, nil), "id", categoryID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: Espera os filtros de atributo e devolve uma página com facetas.
+	facets := &domain.ProductFacets{
+		Attributes: []domain.AttributeFacet{{Name: "marca", Values: []domain.FacetValue{{Value: "Acme", Count: 12}}}},
+		Prices:     []domain.PriceFacet{{Min: domain.Money{Minor: 0, Currency: "BRL"}, Max: domain.Money{Minor: 10000, Currency: "BRL"}, Count: 30}},
+	}
+	mockService.On("ListProducts", mock.Anything, mock.MatchedBy(func(q domain.ProductQuery) bool {
+		return q.IncludeFacets && *q.CategoryID == categoryID && q.Status == domain.StatusActive && q.PriceInterval == 100 &&
+			assert.ObjectsAreEqual([]string{"Acme"}, q.Attributes["marca"]) &&
+			assert.ObjectsAreEqual([]string{"vermelho", "azul"}, q.Attributes["cor"])
+	})).Return(&domain.ProductPage{Items: []*domain.Product{}, Facets: facets}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleCategoryFacets(rr, req)
+
+	// Assert: Verifica as contagens devolvidas com a página.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Facets domain.ProductFacets `json:"facets"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "marca", response.Facets.Attributes[0].Name)
+	assert.Equal(t, int64(12), response.Facets.Attributes[0].Values[0].Count)
+	assert.Equal(t, "100.00", response.Facets.Prices[0].Max.Amount())
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleCategoryFacets_InvalidAttributeFilter(t *testing.T) {
+	// Arrange: Cria o mock do serviço de produtos e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	categoryID := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/categories/"+categoryID.String()+"/facets?attr.Cor%20Base=azul", nil), "id", categoryID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço rejeita o nome do atributo.
+	mockService.On("ListProducts", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidFilter)
+
+	// Act: Chama o handler.
+	handler.HandleCategoryFacets(rr, req)
+
+	// Assert: Verifica se um filtro inválido devolve 400.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CurrencyHandler struct {
+	service service.CurrencyService
+}
+
+type SetExchangeRateRequest struct {
+	Rate string `json:"rate"`
+}
+
+type SetProductPriceRequest struct {
+	Price domain.Money `json:"price"`
+}
+
+func NewCurrencyHandler(svc service.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{service: svc}
+}
+
+func (h *CurrencyHandler) HandleListExchangeRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.ListExchangeRates(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rates)
+}
+
+func (h *CurrencyHandler) HandleSetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	var req SetExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	rate, err := h.service.SetExchangeRate(r.Context(), chi.URLParam(r, "base"), chi.URLParam(r, "currency"), req.Rate)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, rate)
+}
+
+func (h *CurrencyHandler) HandleDeleteExchangeRate(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteExchangeRate(r.Context(), chi.URLParam(r, "base"), chi.URLParam(r, "currency")); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Exchange rate deleted successfully"})
+}
+
+func (h *CurrencyHandler) HandleListProductPrices(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	prices, err := h.service.ListProductPrices(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, prices)
+}
+
+func (h *CurrencyHandler) HandleSetProductPrice(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req SetProductPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, domain.ErrInvalidMoney) || errors.Is(err, domain.ErrInvalidCurrency) {
+			handleError(w, err)
+			return
+		}
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	if err := h.service.SetProductPrice(r.Context(), id, req.Price); err != nil {
+		handleError(w, err)
+		return
+	}
+	h.HandleListProductPrices(w, r)
+}
+
+func (h *CurrencyHandler) HandleDeleteProductPrice(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteProductPrice(r.Context(), id, chi.URLParam(r, "currency")); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Product price deleted successfully"})
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleSetExchangeRate_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.CurrencyServiceMock)
+	handler := NewCurrencyHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPut, "/exchange-rates/BRL/USD", bytes.NewBufferString(`{"rate": "0.183"}`))
+	req = withURLParam(withURLParam(req, "base", "BRL"), "currency", "USD")
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço grava a taxa entre as moedas do caminho.
+	rate := &domain.ExchangeRate{BaseCurrency: "BRL", Currency: "USD", Rate: "0.183", UpdatedAt: time.Now().UTC()}
+	mockService.On("SetExchangeRate", mock.Anything, "BRL", "USD", "0.183").Return(rate, nil)
+
+	// Act: Chama o handler.
+	handler.HandleSetExchangeRate(rr, req)
+
+	// Assert: Verifica se a taxa gravada é devolvida.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response domain.ExchangeRate
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "0.183", response.Rate)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetExchangeRate_InvalidRate(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.CurrencyServiceMock)
+	handler := NewCurrencyHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPut, "/exchange-rates/BRL/USD", bytes.NewBufferString(`{"rate": "-1"}`))
+	req = withURLParam(withURLParam(req, "base", "BRL"), "currency", "USD")
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço rejeita uma taxa negativa.
+	mockService.On("SetExchangeRate", mock.Anything, "BRL", "USD", "-1").
+		Return(nil, fmt.Errorf("Error setting exchange rate: %w", domain.ErrInvalidExchangeRate))
+
+	// Act: Chama o handler.
+	handler.HandleSetExchangeRate(rr, req)
+
+	// Assert: Verifica se responde com erro de validação.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetProductPrice_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.CurrencyServiceMock)
+	handler := NewCurrencyHandler(mockService)
+
+	productID := uuid.New()
+	requestBody := `{"price": {"amount": "19.90", "currency": "USD"}}`
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/"+productID.String()+"/prices", bytes.NewBufferString(requestBody)), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O preço em dólares é gravado e a lista de preços explícitos devolvida.
+	price := domain.Money{Minor: 1990, Currency: "USD"}
+	mockService.On("SetProductPrice", mock.Anything, productID, price).Return(nil)
+	mockService.On("ListProductPrices", mock.Anything, productID).Return([]domain.Money{price}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleSetProductPrice(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []domain.Money
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, []domain.Money{price}, response)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleDeleteProductPrice_NotFound(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.CurrencyServiceMock)
+	handler := NewCurrencyHandler(mockService)
+
+	productID := uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/products/"+productID.String()+"/prices/EUR", nil)
+	req = withURLParam(withURLParam(req, "id", productID.String()), "currency", "EUR")
+	rr := httptest.NewRecorder()
+
+	// Mock: O produto não tem preço explícito em euros.
+	mockService.On("DeleteProductPrice", mock.Anything, productID, "EUR").
+		Return(fmt.Errorf("Error deleting product price: %w", domain.ErrProductPriceNotFound))
+
+	// Act: Chama o handler.
+	handler.HandleDeleteProductPrice(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+	"product-service/src/domain"
+	"strconv"
+	"strings"
+)
+
+// formatETag devolve a versão do produto como um ETag forte.
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersion lê a versão esperada do cabeçalho If-Match, respondendo 428
+// quando está ausente e 400 quando não é um ETag de produto.
+func ifMatchVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	if raw == "" {
+		handleError(w, domain.ErrPreconditionRequired)
+		return 0, false
+	}
+
+	raw = strings.TrimPrefix(raw, "W/")
+	version, err := strconv.ParseInt(strings.Trim(raw, `"`), 10, 64)
+	if err != nil || version <= 0 {
+		handleError(w, domain.ErrInvalidVersion)
+		return 0, false
+	}
+	return version, true
+}
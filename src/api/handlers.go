@@ -3,12 +3,15 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"product-service/src/config"
 	"product-service/src/domain"
 	"product-service/src/service"
+	"strings"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
@@ -18,18 +21,26 @@ type Handler struct {
 }
 
 type CreateProductRequest struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
+	SKU         string       `json:"sku"`
+	Barcode     string       `json:"barcode"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       domain.Money `json:"price"`
+	Stock       int          `json:"stock"`
+	// CategoryIDs são atribuídas ao produto e definem os atributos aceites.
+	CategoryIDs []uuid.UUID       `json:"category_ids"`
+	Attributes  domain.Attributes `json:"attributes"`
 }
 
 type UpdateProductRequest struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	Stock       int       `json:"stock"`
+	ID          uuid.UUID         `json:"id"`
+	SKU         string            `json:"sku"`
+	Barcode     string            `json:"barcode"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Price       domain.Money      `json:"price"`
+	Stock       int               `json:"stock"`
+	Attributes  domain.Attributes `json:"attributes"`
 }
 
 type GetProductRequest struct {
@@ -37,8 +48,35 @@ type GetProductRequest struct {
 }
 
 type ReduceStockRequest struct {
-	ID       uuid.UUID `json:"id"`
-	Quantity int       `json:"quantity"`
+	ID          uuid.UUID `json:"id"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	VariantID   uuid.UUID `json:"variant_id"`
+	Quantity    int       `json:"quantity"`
+}
+
+type ReduceStockBatchRequest struct {
+	Items []domain.StockItem `json:"items"`
+}
+
+type RestockRequest struct {
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Quantity    int       `json:"quantity"`
+	Reference   string    `json:"reference"`
+}
+
+type AdjustStockRequest struct {
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Stock       int       `json:"stock"`
+	Reason      string    `json:"reason"`
+}
+
+type SetProductStatusRequest struct {
+	Status domain.ProductStatus `json:"status"`
+}
+
+type StockLevelResponse struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Stock     int       `json:"stock"`
 }
 
 type DeleteProductResquest struct {
@@ -50,6 +88,25 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+type VersionConflictResponse struct {
+	Code           string `json:"code"`
+	Message        string `json:"message"`
+	CurrentVersion int64  `json:"current_version"`
+}
+
+type InsufficientStockResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+type StockBatchErrorResponse struct {
+	Code    string                  `json:"code"`
+	Message string                  `json:"message"`
+	Lines   []domain.StockLineError `json:"lines"`
+}
+
 func NewHandler(svc service.ProductService, cfg *config.Config) *Handler {
 	return &Handler{
 		service: svc,
@@ -57,15 +114,191 @@ func NewHandler(svc service.ProductService, cfg *config.Config) *Handler {
 	}
 }
 
-func (h *Handler) handleError(w http.ResponseWriter, err error) {
+// invalidInputErrors são os erros de validação do pedido, todos devolvidos como 400 INVALID_INPUT.
+var invalidInputErrors = []error{
+	domain.ErrParametersMissing, domain.ErrInvalidID, domain.ErrInvalidVersion, domain.ErrInvalidIdempotencyKey, domain.ErrInvalidCursor,
+	domain.ErrInvalidSortField, domain.ErrInvalidFilter, domain.ErrInvalidSearchQuery, domain.ErrInvalidSlug,
+	domain.ErrInvalidSKU, domain.ErrInvalidBarcode, domain.ErrInvalidProductStatus, domain.ErrInvalidRevision,
+	domain.ErrInvalidStock, domain.ErrInvalidQuantity, domain.ErrInvalidOrderReference, domain.ErrEmptyStockBatch,
+	domain.ErrAdjustmentReason, domain.ErrReferenceTooLong, domain.ErrSameWarehouseTransfer, domain.ErrVariantWarehouseStock,
+	domain.ErrInvalidPrice, domain.ErrInvalidMoney, domain.ErrInvalidCurrency, domain.ErrCurrencyMismatch, domain.ErrInvalidPriceSchedule,
+	domain.ErrInvalidSalePrice, domain.ErrInvalidCustomerGroup, domain.ErrInvalidPriceTier, domain.ErrInvalidExchangeRate,
+	domain.ErrInvalidVariantOptions, domain.ErrInvalidBundleComponents, domain.ErrInvalidAttributeDefinition, domain.ErrInvalidAttributes,
+	domain.ErrInvalidMedia,
+}
+
+func handleError(w http.ResponseWriter, err error) {
 	log.Printf("ERROR: %v", err)
 
 	if errors.Is(err, domain.ErrProductNotFound) {
 		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "PRODUCT_NOT_FOUND", Message: err.Error()})
 		return
 	}
-	if errors.Is(err, domain.ErrParametersMissing) || errors.Is(err, domain.ErrInvalidPrice) || errors.Is(err, domain.ErrInvalidStock) {
-		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: err.Error()})
+	var batchErr *domain.BatchStockError
+	if errors.As(err, &batchErr) {
+		WriteJSON(w, http.StatusConflict, StockBatchErrorResponse{Code: "STOCK_BATCH_REJECTED", Message: batchErr.Error(), Lines: batchErr.Lines})
+		return
+	}
+	if errors.Is(err, domain.ErrWarehouseNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "WAREHOUSE_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrCategoryNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "CATEGORY_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrVariantNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "VARIANT_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrRevisionNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "REVISION_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrPriceScheduleNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "PRICE_SCHEDULE_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrPriceListNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "PRICE_LIST_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrExchangeRateNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "EXCHANGE_RATE_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrProductPriceNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "PRODUCT_PRICE_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrAttributeNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "ATTRIBUTE_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrMediaNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "MEDIA_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrReservationNotFound) {
+		WriteJSON(w, http.StatusNotFound, ErrorResponse{Code: "RESERVATION_NOT_FOUND", Message: err.Error()})
+		return
+	}
+	for _, target := range invalidInputErrors {
+		if errors.Is(err, target) {
+			WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: err.Error()})
+			return
+		}
+	}
+	if errors.Is(err, domain.ErrPriceNotAvailable) {
+		WriteJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Code: "CURRENCY_NOT_AVAILABLE", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrUnsupportedMediaType) {
+		WriteJSON(w, http.StatusUnsupportedMediaType, ErrorResponse{Code: "UNSUPPORTED_MEDIA_TYPE", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrMediaTooLarge) {
+		WriteJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{Code: "MEDIA_TOO_LARGE", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrPreconditionRequired) {
+		WriteJSON(w, http.StatusPreconditionRequired, ErrorResponse{Code: "PRECONDITION_REQUIRED", Message: err.Error()})
+		return
+	}
+	var versionErr *domain.VersionConflictError
+	if errors.As(err, &versionErr) {
+		WriteJSON(w, http.StatusPreconditionFailed, VersionConflictResponse{Code: "VERSION_CONFLICT", Message: versionErr.Error(), CurrentVersion: versionErr.Current})
+		return
+	}
+	var stockErr *domain.InsufficientStockError
+	if errors.As(err, &stockErr) {
+		WriteJSON(w, http.StatusConflict, InsufficientStockResponse{Code: "INSUFFICIENT_STOCK", Message: stockErr.Error(), Requested: stockErr.Requested, Available: stockErr.Available})
+		return
+	}
+	if errors.Is(err, domain.ErrInsufficientStock) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "INSUFFICIENT_STOCK", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrReservationNotActive) || errors.Is(err, domain.ErrReservationExpired) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "RESERVATION_NOT_ACTIVE", Message: err.Error()})
+		return
+	}
+
+	if errors.Is(err, domain.ErrWarehouseCodeTaken) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "WAREHOUSE_CODE_TAKEN", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrWarehouseInactive) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "WAREHOUSE_INACTIVE", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrStockBelowAllocated) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "STOCK_BELOW_ALLOCATED", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrCategorySlugTaken) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "CATEGORY_SLUG_TAKEN", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrCategoryCycle) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "CATEGORY_CYCLE", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrCategoryHasChildren) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "CATEGORY_HAS_CHILDREN", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrSKUTaken) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "SKU_TAKEN", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrBarcodeTaken) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "BARCODE_TAKEN", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrVariantOptionsTaken) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "VARIANT_OPTIONS_TAKEN", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrProductHasVariants) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "PRODUCT_HAS_VARIANTS", Message: err.Error()})
+		return
+	}
+	var transitionErr *domain.StatusTransitionError
+	if errors.As(err, &transitionErr) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "INVALID_STATUS_TRANSITION", Message: transitionErr.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrProductArchived) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "PRODUCT_ARCHIVED", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrPriceScheduleOverlap) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "PRICE_SCHEDULE_OVERLAP", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrPriceScheduleClosed) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "PRICE_SCHEDULE_CLOSED", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrPriceListGroupTaken) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "PRICE_LIST_GROUP_TAKEN", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrBundleStock) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "BUNDLE_STOCK", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrProductInBundle) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "PRODUCT_IN_BUNDLE", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "IDEMPOTENCY_KEY_REUSED", Message: err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrIdempotencyInProgress) {
+		WriteJSON(w, http.StatusConflict, ErrorResponse{Code: "IDEMPOTENCY_REQUEST_IN_PROGRESS", Message: err.Error()})
 		return
 	}
 
@@ -81,13 +314,17 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 func (h *Handler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	var req CreateProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, domain.ErrInvalidMoney) || errors.Is(err, domain.ErrInvalidCurrency) {
+			handleError(w, err)
+			return
+		}
 		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
 		return
 	}
 
-	err := h.service.Create(r.Context(), req.Name, req.Description, req.Price, req.Stock)
+	err := h.service.Create(r.Context(), req.Name, req.Description, req.SKU, req.Barcode, req.Price, req.Stock, req.Attributes, req.CategoryIDs)
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
 
@@ -104,20 +341,334 @@ func (h *Handler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	product, err := h.service.GetProductByID(r.Context(), getProduct.ID)
+	// Só se convertem os preços de produtos visíveis, para não revelar os restantes.
+	if currency := r.URL.Query().Get("currency"); err == nil && currency != "" && product.Status == domain.StatusActive {
+		err = h.service.ConvertPrices(r.Context(), currency, product)
+	}
+	writePublicProduct(w, product, err)
+}
+
+// writePublicProduct responde às leituras públicas, que só mostram produtos
+// ativos: os rascunhos e os arquivados respondem como inexistentes.
+func writePublicProduct(w http.ResponseWriter, product *domain.Product, err error) {
+	if err == nil && product.Status != domain.StatusActive {
+		err = fmt.Errorf("Error when getting product: %w", domain.ErrProductNotFound)
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	w.Header().Set("ETag", formatETag(product.Version))
+	WriteJSON(w, http.StatusOK, product)
+}
+
+// HandleAdminGet devolve o produto em qualquer estado.
+func (h *Handler) HandleAdminGet(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	product, err := h.service.GetProductByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	w.Header().Set("ETag", formatETag(product.Version))
+	WriteJSON(w, http.StatusOK, product)
+}
+
+// HandleListDeleted lista os produtos removidos que ainda não foram purgados,
+// com a mesma paginação e filtros de HandleList.
+func (h *Handler) HandleListDeleted(w http.ResponseWriter, r *http.Request) {
+	query, ok := productQueryFromRequest(w, r, h.cfg.DefaultCurrency)
+	if !ok {
+		return
+	}
+	query.Deleted = true
+
+	page, err := h.service.ListProducts(r.Context(), query)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, page)
+}
+
+// HandleRestore anula a remoção de um produto e devolve-o com a nova ETag.
+func (h *Handler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	product, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	w.Header().Set("ETag", formatETag(product.Version))
+	WriteJSON(w, http.StatusOK, product)
+}
+
+// HandleSetSale define a promoção do produto e devolve-o com a nova ETag.
+func (h *Handler) HandleSetSale(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var sale domain.Sale
+	if err := json.NewDecoder(r.Body).Decode(&sale); err != nil {
+		if errors.Is(err, domain.ErrInvalidMoney) || errors.Is(err, domain.ErrInvalidCurrency) {
+			handleError(w, err)
+			return
+		}
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	product, err := h.service.SetSale(r.Context(), id, &sale)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	w.Header().Set("ETag", formatETag(product.Version))
+	WriteJSON(w, http.StatusOK, product)
+}
+
+// HandleClearSale remove a promoção do produto.
+func (h *Handler) HandleClearSale(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	product, err := h.service.SetSale(r.Context(), id, nil)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	w.Header().Set("ETag", formatETag(product.Version))
+	WriteJSON(w, http.StatusOK, product)
+}
+
+// HandleSetStatus aplica uma transição de estado ao produto e devolve-o com a nova ETag.
+func (h *Handler) HandleSetStatus(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req SetProductStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	product, err := h.service.SetStatus(r.Context(), id, req.Status)
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
+	w.Header().Set("ETag", formatETag(product.Version))
 	WriteJSON(w, http.StatusOK, product)
 }
 
+func (h *Handler) HandleGetBySKU(w http.ResponseWriter, r *http.Request) {
+	product, err := h.service.GetProductBySKU(r.Context(), chi.URLParam(r, "sku"))
+	writePublicProduct(w, product, err)
+}
+
+func (h *Handler) HandleGetByBarcode(w http.ResponseWriter, r *http.Request) {
+	product, err := h.service.GetProductByBarcode(r.Context(), chi.URLParam(r, "barcode"))
+	writePublicProduct(w, product, err)
+}
+
 func (h *Handler) HandleList(w http.ResponseWriter, r *http.Request) {
-	products, err := h.service.ListProducts(r.Context())
+	query, ok := productQueryFromRequest(w, r, h.cfg.DefaultCurrency)
+	if !ok {
+		return
+	}
+	query.Status = domain.StatusActive
+
+	page, err := h.service.ListProducts(r.Context(), query)
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
-	WriteJSON(w, http.StatusOK, products)
+	WriteJSON(w, http.StatusOK, page)
+}
+
+// HandleAdminList lista os produtos em qualquer estado, opcionalmente filtrados
+// pelo parâmetro status.
+func (h *Handler) HandleAdminList(w http.ResponseWriter, r *http.Request) {
+	query, ok := productQueryFromRequest(w, r, h.cfg.DefaultCurrency)
+	if !ok {
+		return
+	}
+	if raw := r.URL.Query().Get("status"); raw != "" {
+		status, err := domain.ParseProductStatus(raw)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		query.Status = status
+	}
+
+	page, err := h.service.ListProducts(r.Context(), query)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, page)
+}
+
+// HandleListByCategory lista os produtos da categoria e das suas subcategorias,
+// com a mesma paginação e filtros de HandleList.
+func (h *Handler) HandleListByCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	query, ok := productQueryFromRequest(w, r, h.cfg.DefaultCurrency)
+	if !ok {
+		return
+	}
+	query.CategoryID = &categoryID
+	query.Status = domain.StatusActive
+
+	page, err := h.service.ListProducts(r.Context(), query)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, page)
+}
+
+// HandleCategoryFacets devolve a página de HandleListByCategory com as
+// contagens por valor de atributo e por faixa de preço para os filtros da loja.
+func (h *Handler) HandleCategoryFacets(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	query, ok := productQueryFromRequest(w, r, h.cfg.DefaultCurrency)
+	if !ok {
+		return
+	}
+	query.CategoryID = &categoryID
+	query.Status = domain.StatusActive
+	query.IncludeFacets = true
+
+	page, err := h.service.ListProducts(r.Context(), query)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, page)
+}
+
+func (h *Handler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	limit, ok := queryParamInt(w, r, "limit")
+	if !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	query := domain.ProductSearchQuery{
+		Text:     values.Get("q"),
+		Language: domain.SearchLanguage(values.Get("lang")),
+		Limit:    limit,
+		Cursor:   values.Get("cursor"),
+	}
+
+	page, err := h.service.SearchProducts(r.Context(), query)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, page)
+}
+
+// productQueryFromRequest lê a paginação, os filtros e a ordenação da listagem
+// da query string. O intervalo de preço usa a moeda do parâmetro currency ou,
+// na sua ausência, defaultCurrency.
+func productQueryFromRequest(w http.ResponseWriter, r *http.Request, defaultCurrency string) (domain.ProductQuery, bool) {
+	values := r.URL.Query()
+	query := domain.ProductQuery{
+		Cursor:       values.Get("cursor"),
+		NameContains: values.Get("name"),
+		SortBy:       domain.ProductSortField(values.Get("sort")),
+	}
+
+	switch values.Get("order") {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: "invalid order, expected asc or desc"})
+		return query, false
+	}
+
+	var ok bool
+	if query.Limit, ok = queryParamInt(w, r, "limit"); !ok {
+		return query, false
+	}
+	currency := values.Get("currency")
+	if currency == "" {
+		currency = defaultCurrency
+	} else {
+		// Com uma moeda pedida, os preços são devolvidos nessa moeda.
+		parsed, err := domain.ParseCurrency(currency)
+		if err != nil {
+			handleError(w, err)
+			return query, false
+		}
+		currency, query.Currency = parsed, parsed
+	}
+	if query.MinPrice, ok = queryParamMoney(w, r, "min_price", currency); !ok {
+		return query, false
+	}
+	if query.MaxPrice, ok = queryParamMoney(w, r, "max_price", currency); !ok {
+		return query, false
+	}
+	if query.InStockOnly, ok = queryParamBool(w, r, "in_stock"); !ok {
+		return query, false
+	}
+	if query.OnSaleOnly, ok = queryParamBool(w, r, "on_sale"); !ok {
+		return query, false
+	}
+	if query.CreatedAfter, ok = queryParamTime(w, r, "created_after"); !ok {
+		return query, false
+	}
+	if query.UpdatedAfter, ok = queryParamTime(w, r, "updated_after"); !ok {
+		return query, false
+	}
+	if query.IncludeTotal, ok = queryParamBool(w, r, "include_total"); !ok {
+		return query, false
+	}
+	if raw := values.Get("category"); raw != "" {
+		categoryID, err := uuid.Parse(raw)
+		if err != nil {
+			WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: "invalid category"})
+			return query, false
+		}
+		query.CategoryID = &categoryID
+	}
+	if query.PriceInterval, ok = queryParamInt(w, r, "price_interval"); !ok {
+		return query, false
+	}
+	// Os filtros de atributo chegam como attr.<nome>=<valor>, repetidos para aceitar vários valores.
+	for key, attributeValues := range values {
+		if name, found := strings.CutPrefix(key, "attr."); found {
+			if query.Attributes == nil {
+				query.Attributes = make(map[string][]string)
+			}
+			query.Attributes[name] = append(query.Attributes[name], attributeValues...)
+		}
+	}
+	return query, true
 }
 
 func (h *Handler) HandleReduceStock(w http.ResponseWriter, r *http.Request) {
@@ -125,54 +676,143 @@ func (h *Handler) HandleReduceStock(w http.ResponseWriter, r *http.Request) {
 	var reduceStock ReduceStockRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&reduceStock); err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
+		return
+	}
+
+	err := h.service.ReduceStock(r.Context(), reduceStock.ID, reduceStock.WarehouseID, reduceStock.VariantID, reduceStock.Quantity)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Stock reduced successfully"})
+}
+
+func (h *Handler) HandleReduceStockBatch(w http.ResponseWriter, r *http.Request) {
+
+	var req ReduceStockBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
 		return
 	}
 
-	err := h.service.ReduceStock(r.Context(), reduceStock.ID, reduceStock.Quantity)
+	err := h.service.ReduceStockBatch(r.Context(), req.Items)
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
 
 	WriteJSON(w, http.StatusOK, map[string]string{"message": "Stock reduced successfully"})
 }
 
+func (h *Handler) HandleRestock(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req RestockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	stock, err := h.service.Restock(r.Context(), id, req.WarehouseID, req.Quantity, req.Reference)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, StockLevelResponse{ProductID: id, Stock: stock})
+}
+
+func (h *Handler) HandleAdjustStock(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req AdjustStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	stock, err := h.service.AdjustStock(r.Context(), id, req.WarehouseID, req.Stock, req.Reason)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, StockLevelResponse{ProductID: id, Stock: stock})
+}
+
+func (h *Handler) HandleListStockMovements(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	limit, ok := queryParamInt(w, r, "limit")
+	if !ok {
+		return
+	}
+
+	page, err := h.service.ListStockMovements(r.Context(), id, limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, page)
+}
+
 func (h *Handler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
 	var req UpdateProductRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
 
 	productToUpdate := &domain.Product{
 		ID:          req.ID,
+		SKU:         req.SKU,
+		Barcode:     req.Barcode,
 		Name:        req.Name,
 		Description: req.Description,
 		Price:       req.Price,
 		Stock:       req.Stock,
+		Attributes:  req.Attributes,
+		Version:     version,
 	}
 
 	err := h.service.Update(r.Context(), productToUpdate)
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", formatETag(productToUpdate.Version))
 	WriteJSON(w, http.StatusOK, map[string]string{"message": "Product updated successfully"})
 }
 
 func (h *Handler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
 
 	var deleteProduct DeleteProductResquest
 	if err := json.NewDecoder(r.Body).Decode(&deleteProduct); err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
 
-	err := h.service.Delete(r.Context(), deleteProduct.ID)
+	err := h.service.Delete(r.Context(), deleteProduct.ID, version)
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, err)
 		return
 	}
 	WriteJSON(w, http.StatusOK, map[string]string{"message": "Product deleted successfully"})
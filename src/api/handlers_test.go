@@ -3,13 +3,16 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"product-service/src/config"
 	"product-service/src/domain"
 	"product-service/src/service"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,12 +22,13 @@ func TestHandleCreate_Success(t *testing.T) {
 	mockService := new(service.ProductServiceMock)
 	handler := NewHandler(mockService, &config.Config{})
 
-	requestBody := `{"name": "New Product", "description": "A great product", "price": 99.99, "stock": 10}`
+	requestBody := `{"sku": "NP-001", "barcode": "7891234567895", "name": "New Product", "description": "A great product", "price": {"amount": "99.99", "currency": "BRL"}, "stock": 10}`
 	req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(requestBody))
 	rr := httptest.NewRecorder()
 
 	// Mock: Diz ao mock para esperar uma chamada ao método 'Create' com os parâmetros específicos e retornar nil (sem erro).
-	mockService.On("Create", mock.Anything, "New Product", "A great product", 99.99, 10).Return(nil)
+	mockService.On("Create", mock.Anything, "New Product", "A great product", "NP-001", "7891234567895", domain.Money{Minor: 9999, Currency: "BRL"}, 10,
+		domain.Attributes(nil), []uuid.UUID(nil)).Return(nil)
 
 	// Act: Chama o handler.
 	handler.HandleCreate(rr, req)
@@ -34,17 +38,63 @@ func TestHandleCreate_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestHandleCreate_WithAttributes(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	categoryID := uuid.New()
+	requestBody := fmt.Sprintf(`{"sku": "CHL-01", "name": "Chaleira", "description": "Chaleira elétrica", "price": {"amount": "89.90", "currency": "BRL"},
+		"stock": 5, "category_ids": ["%s"], "attributes": {"voltage": 220, "material": "inox"}}`, categoryID)
+	req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Mock: Os atributos e as categorias chegam ao serviço, que os valida.
+	attributes := domain.Attributes{"voltage": float64(220), "material": "inox"}
+	mockService.On("Create", mock.Anything, "Chaleira", "Chaleira elétrica", "CHL-01", "", domain.Money{Minor: 8990, Currency: "BRL"}, 5,
+		attributes, []uuid.UUID{categoryID}).Return(nil)
+
+	// Act: Chama o handler.
+	handler.HandleCreate(rr, req)
+
+	// Assert: Verifica se o produto é criado.
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleCreate_InvalidAttributes(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	requestBody := `{"sku": "CHL-01", "name": "Chaleira", "description": "Chaleira elétrica", "price": {"amount": "89.90", "currency": "BRL"}, "attributes": {"voltage": "alta"}}`
+	req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço rejeita um valor que não respeita a definição do atributo.
+	mockService.On("Create", mock.Anything, "Chaleira", "Chaleira elétrica", "CHL-01", "", mock.Anything, 0, mock.Anything, mock.Anything).
+		Return(fmt.Errorf("Error creating product: %w", domain.ErrInvalidAttributes))
+
+	// Act: Chama o handler.
+	handler.HandleCreate(rr, req)
+
+	// Assert: Verifica se responde com erro de validação.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestHandleCreate_ServiceError(t *testing.T) {
 	// Arrange: Cria o mock do serviço e o handler.
 	mockService := new(service.ProductServiceMock)
 	handler := NewHandler(mockService, &config.Config{})
 
-	requestBody := `{"name": "Invalid Product", "price": -10}` // Dados que causarão um erro
+	requestBody := `{"name": "Invalid Product", "price": {"amount": "-10", "currency": "BRL"}}` // Dados que causarão um erro
 	req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(requestBody))
 	rr := httptest.NewRecorder()
 
 	// Mock: Diz ao mock para esperar uma chamada ao método 'Create' e retornar um erro específico.
-	mockService.On("Create", mock.Anything, "Invalid Product", "", -10.0, 0).Return(domain.ErrInvalidPrice)
+	mockService.On("Create", mock.Anything, "Invalid Product", "", "", "", domain.Money{Minor: -1000, Currency: "BRL"}, 0,
+		mock.Anything, mock.Anything).Return(domain.ErrInvalidPrice)
 
 	// Act: Chama o handler.
 	handler.HandleCreate(rr, req)
@@ -62,6 +112,193 @@ func TestHandleCreate_ServiceError(t *testing.T) {
 	assert.Equal(t, domain.ErrInvalidPrice.Error(), errResponse.Message)
 }
 
+func TestHandleCreate_InvalidCurrency(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	requestBody := `{"name": "Produto", "description": "Descrição", "price": {"amount": "10.00", "currency": "XYZ"}, "stock": 1}`
+	req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler.
+	handler.HandleCreate(rr, req)
+
+	// Assert: Uma moeda desconhecida é rejeitada antes de chegar ao serviço.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var errResponse ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResponse))
+	assert.Equal(t, "INVALID_INPUT", errResponse.Code)
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleList_DefaultCurrency(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler com a moeda por omissão configurada.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{DefaultCurrency: "EUR"})
+
+	req := httptest.NewRequest(http.MethodGet, "/list?min_price=1.5", nil)
+	rr := httptest.NewRecorder()
+
+	// Mock: Sem o parâmetro currency, o intervalo usa a moeda por omissão.
+	mockService.On("ListProducts", mock.Anything, mock.MatchedBy(func(q domain.ProductQuery) bool {
+		return q.MinPrice != nil && *q.MinPrice == domain.Money{Minor: 150, Currency: "EUR"} && q.MaxPrice == nil
+	})).Return(&domain.ProductPage{Items: []*domain.Product{}}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleList(rr, req)
+
+	// Assert: Verifica se o pedido foi aceite.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleGetByBarcode_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/products/barcode/789123456789", nil), "barcode", "789123456789")
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço encontra o produto pelo código lido pelo leitor.
+	product := &domain.Product{ID: uuid.New(), SKU: "CAN-AZ", Barcode: "0789123456789", Status: domain.StatusActive, Price: domain.Money{Minor: 1990, Currency: "BRL"}, Version: 3}
+	mockService.On("GetProductByBarcode", mock.Anything, "789123456789").Return(product, nil)
+
+	// Act: Chama o handler.
+	handler.HandleGetByBarcode(rr, req)
+
+	// Assert: Verifica se o produto é devolvido com o ETag da versão.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleGetBySKU_DraftIsHidden(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/products/sku/CAN-AZ", nil), "sku", "CAN-AZ")
+	rr := httptest.NewRecorder()
+
+	// Mock: O produto existe mas ainda é um rascunho.
+	product := &domain.Product{ID: uuid.New(), SKU: "CAN-AZ", Status: domain.StatusDraft, Price: domain.Money{Minor: 1990, Currency: "BRL"}}
+	mockService.On("GetProductBySKU", mock.Anything, "CAN-AZ").Return(product, nil)
+
+	// Act: Chama o handler.
+	handler.HandleGetBySKU(rr, req)
+
+	// Assert: Verifica se o rascunho não é visível publicamente.
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	var errResponse ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResponse))
+	assert.Equal(t, "PRODUCT_NOT_FOUND", errResponse.Code)
+}
+
+func TestHandleAdminList_StatusFilter(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/products?status=draft", nil)
+	rr := httptest.NewRecorder()
+
+	// Mock: A listagem de administração filtra pelo estado pedido.
+	mockService.On("ListProducts", mock.Anything, domain.ProductQuery{Status: domain.StatusDraft}).Return(&domain.ProductPage{Items: []*domain.Product{}}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleAdminList(rr, req)
+
+	// Assert: Verifica se o pedido foi aceite.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetStatus_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	id := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+id.String()+"/status", bytes.NewBufferString(`{"status": "active"}`)), "id", id.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço publica o produto e devolve a nova versão.
+	product := &domain.Product{ID: id, Status: domain.StatusActive, Price: domain.Money{Minor: 1990, Currency: "BRL"}, Version: 2}
+	mockService.On("SetStatus", mock.Anything, id, domain.StatusActive).Return(product, nil)
+
+	// Act: Chama o handler.
+	handler.HandleSetStatus(rr, req)
+
+	// Assert: Verifica se o produto é devolvido com o novo ETag.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetStatus_InvalidTransition(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	id := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+id.String()+"/status", bytes.NewBufferString(`{"status": "draft"}`)), "id", id.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: Um produto arquivado não pode voltar a rascunho.
+	transitionErr := &domain.StatusTransitionError{From: domain.StatusArchived, To: domain.StatusDraft}
+	mockService.On("SetStatus", mock.Anything, id, domain.StatusDraft).Return(nil, fmt.Errorf("Error when changing product status: %w", transitionErr))
+
+	// Act: Chama o handler.
+	handler.HandleSetStatus(rr, req)
+
+	// Assert: Verifica se a transição é rejeitada com 409.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var errResponse ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResponse))
+	assert.Equal(t, "INVALID_STATUS_TRANSITION", errResponse.Code)
+}
+
+func TestHandleGetByBarcode_InvalidChecksum(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/products/barcode/789123456780", nil), "barcode", "789123456780")
+	rr := httptest.NewRecorder()
+
+	mockService.On("GetProductByBarcode", mock.Anything, "789123456780").Return(nil, domain.ErrInvalidBarcode)
+
+	// Act: Chama o handler.
+	handler.HandleGetByBarcode(rr, req)
+
+	// Assert: Verifica se o código inválido dá 400.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleCreate_SKUTaken(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	requestBody := `{"sku": "NP-001", "name": "Produto", "description": "Descrição", "price": {"amount": "10.00", "currency": "BRL"}, "stock": 1}`
+	req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	mockService.On("Create", mock.Anything, "Produto", "Descrição", "NP-001", "", mock.Anything, 1, mock.Anything, mock.Anything).Return(domain.ErrSKUTaken)
+
+	// Act: Chama o handler.
+	handler.HandleCreate(rr, req)
+
+	// Assert: Verifica se o SKU repetido dá 409.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var errResponse ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResponse))
+	assert.Equal(t, "SKU_TAKEN", errResponse.Code)
+}
+
 func TestHandleList_Success(t *testing.T) {
 	// Arrange: Cria o mock do serviço e o handler.
 	mockService := new(service.ProductServiceMock)
@@ -70,9 +307,13 @@ func TestHandleList_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/list", nil)
 	rr := httptest.NewRecorder()
 
-	// Mock: Mock para retornar uma lista de produtos.
-	expectedProducts := []*domain.Product{{Name: "Test Product 1"}, {Name: "Test Product 2"}}
-	mockService.On("ListProducts", mock.Anything).Return(expectedProducts, nil)
+	// Mock: Mock para retornar uma página de produtos.
+	price1, price2 := domain.Money{Minor: 1000, Currency: "BRL"}, domain.Money{Minor: 2500, Currency: "BRL"}
+	expectedPage := &domain.ProductPage{Items: []*domain.Product{
+		{Name: "Test Product 1", Price: price1, EffectivePrice: price1},
+		{Name: "Test Product 2", Price: price2, EffectivePrice: price2},
+	}, NextCursor: "abc"}
+	mockService.On("ListProducts", mock.Anything, domain.ProductQuery{Status: domain.StatusActive}).Return(expectedPage, nil)
 
 	// Act: Chama o handler.
 	handler.HandleList(rr, req)
@@ -81,10 +322,535 @@ func TestHandleList_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	mockService.AssertExpectations(t)
 
-	var products []*domain.Product
-	if err := json.Unmarshal(rr.Body.Bytes(), &products); err != nil {
+	var page domain.ProductPage
+	if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "Test Product 1", page.Items[0].Name)
+	assert.Equal(t, "10.00", page.Items[0].Price.Amount())
+	assert.Equal(t, "abc", page.NextCursor)
+	assert.Nil(t, page.Total)
+}
+
+func TestHandleList_WithFilters(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	url := "/list?limit=10&cursor=abc&min_price=5&max_price=20.5&currency=usd&in_stock=true&created_after=2025-10-01T00:00:00Z&name=caneca&sort=price&order=desc&include_total=true"
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço recebe todos os filtros lidos da query string.
+	mockService.On("ListProducts", mock.Anything, mock.MatchedBy(func(q domain.ProductQuery) bool {
+		return q.Limit == 10 && q.Cursor == "abc" && *q.MinPrice == domain.Money{Minor: 500, Currency: "USD"} && *q.MaxPrice == domain.Money{Minor: 2050, Currency: "USD"} && q.InStockOnly &&
+			q.CreatedAfter.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)) && q.UpdatedAfter == nil &&
+			q.NameContains == "caneca" && q.SortBy == domain.SortByPrice && q.Descending && q.IncludeTotal && q.Currency == "USD"
+	})).Return(&domain.ProductPage{Items: []*domain.Product{}}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleList(rr, req)
+
+	// Assert: Verifica se o pedido foi aceite com os filtros esperados.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleList_InvalidFilter(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/list?created_after=ontem", nil)
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler com uma data inválida.
+	handler.HandleList(rr, req)
+
+	// Assert: Verifica se o pedido é rejeitado sem chamar o serviço.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "ListProducts", mock.Anything, mock.Anything)
+}
+
+func TestHandleReduceStockBatch_Rejected(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	requestBody := `{"items": [{"product_id": "` + productID.String() + `", "quantity": 3}]}`
+	req := httptest.NewRequest(http.MethodPost, "/products/reduce-stock/batch", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço rejeita o lote por falta de stock na única linha.
+	batchErr := &domain.BatchStockError{Lines: []domain.StockLineError{
+		{ProductID: productID, Requested: 3, Available: 1, Code: "INSUFFICIENT_STOCK", Message: domain.ErrInsufficientStock.Error()},
+	}}
+	mockService.On("ReduceStockBatch", mock.Anything, []domain.StockItem{{ProductID: productID, Quantity: 3}}).Return(batchErr)
+
+	// Act: Chama o handler.
+	handler.HandleReduceStockBatch(rr, req)
+
+	// Assert: Verifica se o status code é 409 e se o relatório por linha é devolvido.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var errResponse StockBatchErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &errResponse); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Equal(t, "STOCK_BATCH_REJECTED", errResponse.Code)
+	assert.Len(t, errResponse.Lines, 1)
+	assert.Equal(t, 1, errResponse.Lines[0].Available)
+}
+
+func TestHandleReduceStock_InsufficientStock(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	requestBody := `{"id": "` + productID.String() + `", "quantity": 5}`
+	req := httptest.NewRequest(http.MethodPut, "/products/reduce-stock/"+productID.String(), bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Mock: O produto só tem 2 unidades disponíveis.
+	stockErr := &domain.InsufficientStockError{ProductID: productID, Requested: 5, Available: 2}
+	mockService.On("ReduceStock", mock.Anything, productID, uuid.Nil, uuid.Nil, 5).Return(stockErr)
+
+	// Act: Chama o handler.
+	handler.HandleReduceStock(rr, req)
+
+	// Assert: Verifica se o status code é 409 e se a quantidade disponível é devolvida.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var errResponse InsufficientStockResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &errResponse); err != nil {
 		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
 	}
-	assert.Len(t, products, 2)
-	assert.Equal(t, "Test Product 1", products[0].Name)
+	assert.Equal(t, "INSUFFICIENT_STOCK", errResponse.Code)
+	assert.Equal(t, 5, errResponse.Requested)
+	assert.Equal(t, 2, errResponse.Available)
+}
+
+func TestHandleReduceStock_ProductNotFound(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	requestBody := `{"id": "` + productID.String() + `", "quantity": 1}`
+	req := httptest.NewRequest(http.MethodPut, "/products/reduce-stock/"+productID.String(), bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	mockService.On("ReduceStock", mock.Anything, productID, uuid.Nil, uuid.Nil, 1).Return(domain.ErrProductNotFound)
+
+	// Act: Chama o handler.
+	handler.HandleReduceStock(rr, req)
+
+	// Assert: Verifica se o status code é 404 Not Found.
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleReduceStock_VariantNotFound(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID, variantID := uuid.New(), uuid.New()
+	requestBody := `{"id": "` + productID.String() + `", "variant_id": "` + variantID.String() + `", "quantity": 2}`
+	req := httptest.NewRequest(http.MethodPut, "/products/reduce-stock/"+productID.String(), bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Mock: A redução é dirigida à variante, que não pertence ao produto.
+	mockService.On("ReduceStock", mock.Anything, productID, uuid.Nil, variantID, 2).Return(domain.ErrVariantNotFound)
+
+	// Act: Chama o handler.
+	handler.HandleReduceStock(rr, req)
+
+	// Assert: Verifica se o status code é 404 com o código da variante.
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var errResponse ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResponse))
+	assert.Equal(t, "VARIANT_NOT_FOUND", errResponse.Code)
+}
+
+func TestHandleListStockMovements_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/products/"+productID.String()+"/stock-movements?limit=2&cursor=abc", nil)
+	req = withURLParam(req, "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: Devolve uma página com um movimento e um cursor seguinte.
+	page := &domain.StockMovementPage{
+		Items:      []*domain.StockMovement{{ProductID: productID, Delta: -2, Balance: 8, Reason: domain.MovementReduce}},
+		NextCursor: "next",
+	}
+	mockService.On("ListStockMovements", mock.Anything, productID, 2, "abc").Return(page, nil)
+
+	// Act: Chama o handler.
+	handler.HandleListStockMovements(rr, req)
+
+	// Assert: Verifica se a página é devolvida.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body domain.StockMovementPage
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Len(t, body.Items, 1)
+	assert.Equal(t, "next", body.NextCursor)
+}
+
+func TestHandleListStockMovements_InvalidLimit(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/products/"+productID.String()+"/stock-movements?limit=abc", nil)
+	req = withURLParam(req, "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler.
+	handler.HandleListStockMovements(rr, req)
+
+	// Assert: Verifica se o status code é 400 e se o serviço não foi chamado.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "ListStockMovements", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleRestock_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	requestBody := `{"quantity": 20, "reference": "NF-1234"}`
+	req := httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/restock", bytes.NewBufferString(requestBody))
+	req = withURLParam(req, "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	mockService.On("Restock", mock.Anything, productID, uuid.Nil, 20, "NF-1234").Return(30, nil)
+
+	// Act: Chama o handler.
+	handler.HandleRestock(rr, req)
+
+	// Assert: Verifica se o novo stock é devolvido.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body StockLevelResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Equal(t, 30, body.Stock)
+}
+
+func TestHandleAdjustStock_MissingReason(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	req := httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/stock-adjustments", bytes.NewBufferString(`{"stock": 7}`))
+	req = withURLParam(req, "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	mockService.On("AdjustStock", mock.Anything, productID, uuid.Nil, 7, "").Return(0, domain.ErrAdjustmentReason)
+
+	// Act: Chama o handler.
+	handler.HandleAdjustStock(rr, req)
+
+	// Assert: Verifica se o status code é 400 Bad Request.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleUpdate_MissingIfMatch(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	requestBody := `{"id": "` + uuid.New().String() + `", "name": "Produto", "description": "Descrição", "price": {"amount": "10.00", "currency": "BRL"}, "stock": 1}`
+	req := httptest.NewRequest(http.MethodPut, "/products/id", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler sem o cabeçalho If-Match.
+	handler.HandleUpdate(rr, req)
+
+	// Assert: Verifica se o pedido é rejeitado com 428 sem chamar o serviço.
+	assert.Equal(t, http.StatusPreconditionRequired, rr.Code)
+	mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestHandleUpdate_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	requestBody := `{"id": "` + productID.String() + `", "name": "Produto", "description": "Descrição", "price": {"amount": "10.00", "currency": "BRL"}, "stock": 1}`
+	req := httptest.NewRequest(http.MethodPut, "/products/id", bytes.NewBufferString(requestBody))
+	req.Header.Set("If-Match", `"3"`)
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço recebe a versão do If-Match e devolve a nova versão no produto.
+	mockService.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+		return p.ID == productID && p.Version == 3
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Product).Version = 4
+	}).Return(nil)
+
+	// Act: Chama o handler.
+	handler.HandleUpdate(rr, req)
+
+	// Assert: Verifica se a resposta traz o ETag da nova versão.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleDelete_VersionConflict(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	requestBody := `{"id": "` + productID.String() + `"}`
+	req := httptest.NewRequest(http.MethodDelete, "/products/id", bytes.NewBufferString(requestBody))
+	req.Header.Set("If-Match", `"2"`)
+	rr := httptest.NewRecorder()
+
+	conflict := &domain.VersionConflictError{ProductID: productID, Expected: 2, Current: 5}
+	mockService.On("Delete", mock.Anything, productID, int64(2)).Return(conflict)
+
+	// Act: Chama o handler.
+	handler.HandleDelete(rr, req)
+
+	// Assert: Verifica se a versão desatualizada devolve 412 com a versão atual.
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body VersionConflictResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Equal(t, "VERSION_CONFLICT", body.Code)
+	assert.Equal(t, int64(5), body.CurrentVersion)
+}
+
+func TestHandleSearch_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=caneca+azul&lang=pt&limit=5", nil)
+	rr := httptest.NewRecorder()
+
+	result := &domain.ProductSearchResult{
+		Product:       &domain.Product{ID: uuid.New(), Name: "Caneca azul"},
+		Rank:          0.6,
+		NameHighlight: "<mark>Caneca</mark> <mark>azul</mark>",
+	}
+	query := domain.ProductSearchQuery{Text: "caneca azul", Language: "pt", Limit: 5}
+	mockService.On("SearchProducts", mock.Anything, query).Return(&domain.ProductSearchPage{Items: []*domain.ProductSearchResult{result}}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleSearch(rr, req)
+
+	// Assert: Verifica se os campos do produto e o destaque vêm no mesmo objeto.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Len(t, body.Items, 1)
+	assert.Equal(t, "Caneca azul", body.Items[0]["name"])
+	assert.Equal(t, "<mark>Caneca</mark> <mark>azul</mark>", body.Items[0]["name_highlight"])
+}
+
+func TestHandleSearch_EmptyQuery(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/search", nil)
+	rr := httptest.NewRecorder()
+
+	mockService.On("SearchProducts", mock.Anything, domain.ProductSearchQuery{}).Return(nil, domain.ErrInvalidSearchQuery)
+
+	// Act: Chama o handler sem o parâmetro q.
+	handler.HandleSearch(rr, req)
+
+	// Assert: Verifica se o pedido é rejeitado com 400.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleRestore_NotDeleted(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	id := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+id.String()+"/restore", nil), "id", id.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: Não há nenhum produto removido com este ID.
+	mockService.On("Restore", mock.Anything, id).Return(nil, fmt.Errorf("Error when restoring product: %w", domain.ErrProductNotFound))
+
+	// Act: Chama o handler.
+	handler.HandleRestore(rr, req)
+
+	// Assert: Verifica se a resposta é 404.
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleListDeleted_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/products/deleted", nil)
+	rr := httptest.NewRecorder()
+
+	// Mock: A listagem pede apenas os produtos removidos.
+	mockService.On("ListProducts", mock.Anything, domain.ProductQuery{Deleted: true}).Return(&domain.ProductPage{Items: []*domain.Product{}}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleListDeleted(rr, req)
+
+	// Assert: Verifica se o pedido foi aceite.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetSale_NotBelowRegularPrice(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	id := uuid.New()
+	requestBody := `{"price": {"amount": "120.00", "currency": "BRL"}, "starts_at": "2025-11-28T00:00:00Z", "ends_at": "2025-11-29T00:00:00Z"}`
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/"+id.String()+"/sale", bytes.NewBufferString(requestBody)), "id", id.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O preço promocional não é inferior ao preço normal.
+	mockService.On("SetSale", mock.Anything, id, mock.MatchedBy(func(sale *domain.Sale) bool {
+		return sale.Price == domain.Money{Minor: 12000, Currency: "BRL"} && sale.StartsAt != nil && sale.EndsAt != nil
+	})).Return(nil, fmt.Errorf("Error when setting sale price: %w", domain.ErrInvalidSalePrice))
+
+	// Act: Chama o handler.
+	handler.HandleSetSale(rr, req)
+
+	// Assert: Verifica se a promoção é rejeitada com 400.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleList_OnSaleFilter(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/list?on_sale=true", nil)
+	rr := httptest.NewRecorder()
+
+	// Mock: A listagem pede apenas os produtos em promoção.
+	mockService.On("ListProducts", mock.Anything, domain.ProductQuery{OnSaleOnly: true, Status: domain.StatusActive}).Return(&domain.ProductPage{Items: []*domain.Product{}}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleList(rr, req)
+
+	// Assert: Verifica se o pedido foi aceite.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleGet_InCurrency(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/"+productID.String()+"?currency=USD", bytes.NewBufferString(`{"id": "`+productID.String()+`"}`))
+	rr := httptest.NewRecorder()
+
+	// Mock: O produto em BRL é convertido para dólares.
+	price := domain.Money{Minor: 10000, Currency: "BRL"}
+	product := &domain.Product{ID: productID, Status: domain.StatusActive, Price: price, EffectivePrice: price, Version: 2}
+	mockService.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+	mockService.On("ConvertPrices", mock.Anything, "USD", []*domain.Product{product}).Run(func(args mock.Arguments) {
+		converted := domain.Money{Minor: 1830, Currency: "USD"}
+		product.Price, product.EffectivePrice = converted, converted
+	}).Return(nil)
+
+	// Act: Chama o handler.
+	handler.HandleGet(rr, req)
+
+	// Assert: Verifica se os preços são devolvidos em dólares.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response domain.Product
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, domain.Money{Minor: 1830, Currency: "USD"}, response.Price)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleGet_CurrencyNotAvailable(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	productID := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/"+productID.String()+"?currency=EUR", bytes.NewBufferString(`{"id": "`+productID.String()+`"}`))
+	rr := httptest.NewRecorder()
+
+	// Mock: Não há preço explícito em euros nem taxa de câmbio.
+	price := domain.Money{Minor: 10000, Currency: "BRL"}
+	product := &domain.Product{ID: productID, Status: domain.StatusActive, Price: price, EffectivePrice: price}
+	mockService.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+	mockService.On("ConvertPrices", mock.Anything, "EUR", []*domain.Product{product}).
+		Return(fmt.Errorf("Error converting prices: %w", domain.ErrPriceNotAvailable))
+
+	// Act: Chama o handler.
+	handler.HandleGet(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	var response ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "CURRENCY_NOT_AVAILABLE", response.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleList_InvalidCurrency(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/list?currency=XYZ", nil)
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler com uma moeda não suportada.
+	handler.HandleList(rr, req)
+
+	// Assert: Verifica se o pedido é rejeitado sem chamar o serviço.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "ListProducts", mock.Anything, mock.Anything)
 }
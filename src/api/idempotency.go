@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/service"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+)
+
+type IdempotencyHandler struct {
+	service service.IdempotencyService
+}
+
+func NewIdempotencyHandler(svc service.IdempotencyService) *IdempotencyHandler {
+	return &IdempotencyHandler{service: svc}
+}
+
+// responseRecorder guarda o status e o corpo escritos pelo handler, sem deixar de os enviar ao cliente.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Middleware repete a primeira resposta guardada para pedidos do mesmo
+// principal com o mesmo Idempotency-Key. Pedidos sem o cabeçalho seguem
+// normalmente. Corre depois da autenticação, que identifica o principal.
+func (h *IdempotencyHandler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		principal := domain.PrincipalFromContext(r.Context())
+		record, err := h.service.Begin(r.Context(), principal, key, requestHash(principal, r, body))
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		if record != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(IdempotencyReplayedHeader, "true")
+			w.WriteHeader(record.StatusCode)
+			if _, err := w.Write(record.ResponseBody); err != nil {
+				log.Printf("Failed to replay idempotent response: %v", err)
+			}
+			return
+		}
+
+		// A resposta é guardada mesmo que o cliente desista do pedido.
+		ctx := context.WithoutCancel(r.Context())
+		rec := &responseRecorder{ResponseWriter: w}
+		completed := false
+		defer func() {
+			if !completed {
+				if err := h.service.Abandon(ctx, principal, key); err != nil {
+					log.Printf("ERROR: %v", err)
+				}
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+
+		// Erros do servidor não são guardados, para que o cliente possa repetir o pedido.
+		if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+			return
+		}
+		if err := h.service.Complete(ctx, principal, key, rec.status, rec.body.Bytes()); err != nil {
+			log.Printf("ERROR: %v", err)
+			return
+		}
+		completed = true
+	})
+}
+
+func requestHash(principal string, r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(principal + "\n" + r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyMiddleware_StoresFirstResponse(t *testing.T) {
+	// Arrange: Cria o mock do serviço e um handler que conta as chamadas.
+	mockService := new(service.IdempotencyServiceMock)
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		WriteJSON(w, http.StatusCreated, map[string]string{"message": "Product created successfully"})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(`{"name": "A"}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	rr := httptest.NewRecorder()
+
+	mockService.On("Begin", mock.Anything, "internal", "key-1", mock.Anything).Return(nil, nil)
+	mockService.On("Complete", mock.Anything, "internal", "key-1", http.StatusCreated, mock.Anything).Return(nil)
+
+	// Act: Executa o middleware.
+	NewIdempotencyHandler(mockService).Middleware(next).ServeHTTP(rr, req)
+
+	// Assert: O handler foi executado e a resposta foi guardada.
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, 1, calls)
+	mockService.AssertExpectations(t)
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	// Arrange: Cria o mock do serviço com uma resposta já guardada.
+	mockService := new(service.IdempotencyServiceMock)
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/products/reduce-stock/1", bytes.NewBufferString(`{"quantity": 1}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	rr := httptest.NewRecorder()
+
+	completedAt := time.Now()
+	record := &domain.IdempotencyRecord{Key: "key-1", StatusCode: http.StatusOK, ResponseBody: []byte(`{"message":"Stock reduced successfully"}`), CompletedAt: &completedAt}
+	mockService.On("Begin", mock.Anything, "internal", "key-1", mock.Anything).Return(record, nil)
+
+	// Act: Executa o middleware.
+	NewIdempotencyHandler(mockService).Middleware(next).ServeHTTP(rr, req)
+
+	// Assert: A resposta original é repetida sem executar o handler.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "true", rr.Header().Get(IdempotencyReplayedHeader))
+	assert.JSONEq(t, `{"message":"Stock reduced successfully"}`, rr.Body.String())
+}
+
+func TestIdempotencyMiddleware_KeyReused(t *testing.T) {
+	// Arrange: O serviço indica que a chave foi usada com outro corpo.
+	mockService := new(service.IdempotencyServiceMock)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(`{"name": "B"}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	rr := httptest.NewRecorder()
+
+	mockService.On("Begin", mock.Anything, "internal", "key-1", mock.Anything).Return(nil, domain.ErrIdempotencyKeyReused)
+
+	// Act: Executa o middleware.
+	NewIdempotencyHandler(mockService).Middleware(next).ServeHTTP(rr, req)
+
+	// Assert: Verifica se o status code é 409 Conflict.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestIdempotencyMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	// Arrange: O handler falha com um erro interno.
+	mockService := new(service.IdempotencyServiceMock)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, http.StatusInternalServerError, ErrorResponse{Code: "INTERNAL_SERVER_ERROR"})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	rr := httptest.NewRecorder()
+
+	mockService.On("Begin", mock.Anything, "internal", "key-1", mock.Anything).Return(nil, nil)
+	mockService.On("Abandon", mock.Anything, "internal", "key-1").Return(nil)
+
+	// Act: Executa o middleware.
+	NewIdempotencyHandler(mockService).Middleware(next).ServeHTTP(rr, req)
+
+	// Assert: A chave é libertada para permitir uma nova tentativa.
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_ScopesKeyByUser(t *testing.T) {
+	// Arrange: Dois utilizadores enviam o mesmo pedido com a mesma chave.
+	mockService := new(service.IdempotencyServiceMock)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, http.StatusCreated, map[string]string{"message": "Product created successfully"})
+	})
+	hashes := map[string]string{}
+	mockService.On("Begin", mock.Anything, mock.Anything, "key-1", mock.Anything).
+		Run(func(args mock.Arguments) { hashes[args.String(1)] = args.String(3) }).
+		Return(nil, nil)
+	mockService.On("Complete", mock.Anything, mock.Anything, "key-1", http.StatusCreated, mock.Anything).Return(nil)
+
+	// Act: Executa o middleware para cada utilizador.
+	for _, userID := range []string{"user-a", "user-b"} {
+		req := httptest.NewRequest(http.MethodPost, "/create", bytes.NewBufferString(`{"name": "A"}`))
+		req = req.WithContext(context.WithValue(req.Context(), domain.UserIDContextKey, userID))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		NewIdempotencyHandler(mockService).Middleware(next).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Assert: Cada utilizador tem a sua chave e o seu hash.
+	assert.Len(t, hashes, 2)
+	assert.NotEqual(t, hashes["user:user-a"], hashes["user:user-b"])
+	mockService.AssertCalled(t, "Complete", mock.Anything, "user:user-b", "key-1", http.StatusCreated, mock.Anything)
+}
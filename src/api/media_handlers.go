@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"product-service/src/config"
+	"product-service/src/domain"
+	"product-service/src/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead é a margem para os cabeçalhos e campos do formulário além do ficheiro.
+const multipartOverhead = 1 << 20
+
+type MediaHandler struct {
+	service service.MediaService
+	cfg     *config.Config
+}
+
+type UpdateMediaRequest struct {
+	AltText string `json:"alt_text"`
+}
+
+type ReorderMediaRequest struct {
+	MediaIDs []uuid.UUID `json:"media_ids"`
+}
+
+func NewMediaHandler(svc service.MediaService, cfg *config.Config) *MediaHandler {
+	return &MediaHandler{service: svc, cfg: cfg}
+}
+
+// HandleUpload recebe uma imagem em multipart/form-data, no campo file, com o
+// texto alternativo opcional no campo alt_text.
+func (h *MediaHandler) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.cfg.MediaMaxSize+multipartOverhead)
+	if err := r.ParseMultipartForm(h.cfg.MediaMaxSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			handleError(w, domain.ErrMediaTooLarge)
+			return
+		}
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Expected a multipart/form-data body with a file field"})
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: "file is required"})
+		return
+	}
+	defer file.Close()
+
+	media, err := h.service.Upload(r.Context(), productID, header.Header.Get("Content-Type"), file, r.FormValue("alt_text"))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, media)
+}
+
+func (h *MediaHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	mediaID, ok := urlParamUUID(w, r, "mediaId")
+	if !ok {
+		return
+	}
+
+	var req UpdateMediaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	media, err := h.service.UpdateAltText(r.Context(), productID, mediaID, req.AltText)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, media)
+}
+
+func (h *MediaHandler) HandleReorder(w http.ResponseWriter, r *http.Request) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req ReorderMediaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	media, err := h.service.Reorder(r.Context(), productID, req.MediaIDs)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, media)
+}
+
+func (h *MediaHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	mediaID, ok := urlParamUUID(w, r, "mediaId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), productID, mediaID); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Media deleted successfully"})
+}
+
+// HandleServe devolve o ficheiro de uma imagem. Cada envio tem uma chave
+// nova, pelo que o conteúdo de uma chave nunca muda e pode ficar em cache.
+func (h *MediaHandler) HandleServe(w http.ResponseWriter, r *http.Request) {
+	content, err := h.service.Open(r.Context(), chi.URLParam(r, "*"))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(chi.URLParam(r, "*"))))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("ERROR: serving media: %v", err)
+	}
+}
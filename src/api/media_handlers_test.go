@@ -0,0 +1,193 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"product-service/src/config"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// multipartImage monta um formulário com o ficheiro no campo file e o texto alternativo.
+func multipartImage(t *testing.T, contentType string, content []byte, altText string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="foto.png"`)
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.WriteField("alt_text", altText))
+	assert.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestHandleUploadMedia_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.MediaServiceMock)
+	handler := NewMediaHandler(mockService, &config.Config{MediaMaxSize: 1024})
+
+	productID := uuid.New()
+	body, contentType := multipartImage(t, "image/png", []byte("\x89PNG\r\n\x1a\n"), "Frente")
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/media", body), "id", productID.String())
+	req.Header.Set("Content-Type", contentType)
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço recebe o ficheiro com o tipo declarado e o texto alternativo.
+	media := &domain.ProductMedia{ID: uuid.New(), ProductID: productID, URL: "/media/products/x.png", ContentType: "image/png", Size: 8, AltText: "Frente"}
+	mockService.On("Upload", mock.Anything, productID, "image/png", mock.MatchedBy(func(content io.Reader) bool {
+		data, _ := io.ReadAll(content)
+		return string(data) == "\x89PNG\r\n\x1a\n"
+	}), "Frente").Return(media, nil)
+
+	// Act: Chama o handler.
+	handler.HandleUpload(rr, req)
+
+	// Assert: Verifica se a imagem é devolvida com o endereço público.
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response domain.ProductMedia
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "/media/products/x.png", response.URL)
+	assert.NotContains(t, rr.Body.String(), "storage_key")
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleUploadMedia_TooLarge(t *testing.T) {
+	// Arrange: Cria o handler com um limite abaixo do tamanho do pedido.
+	mockService := new(service.MediaServiceMock)
+	handler := NewMediaHandler(mockService, &config.Config{MediaMaxSize: 16})
+
+	productID := uuid.New()
+	body, contentType := multipartImage(t, "image/png", make([]byte, 2<<20), "")
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/media", body), "id", productID.String())
+	req.Header.Set("Content-Type", contentType)
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler.
+	handler.HandleUpload(rr, req)
+
+	// Assert: Verifica se o pedido é recusado antes de chegar ao serviço.
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	mockService.AssertNotCalled(t, "Upload")
+}
+
+func TestHandleUploadMedia_UnsupportedType(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.MediaServiceMock)
+	handler := NewMediaHandler(mockService, &config.Config{MediaMaxSize: 1024})
+
+	productID := uuid.New()
+	body, contentType := multipartImage(t, "application/pdf", []byte("%PDF-1.4"), "")
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/media", body), "id", productID.String())
+	req.Header.Set("Content-Type", contentType)
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço rejeita o tipo do ficheiro.
+	mockService.On("Upload", mock.Anything, productID, "application/pdf", mock.Anything, "").Return(nil, domain.ErrUnsupportedMediaType)
+
+	// Act: Chama o handler.
+	handler.HandleUpload(rr, req)
+
+	// Assert: Verifica se um tipo não suportado devolve 415.
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleUploadMedia_MissingFile(t *testing.T) {
+	// Arrange: Cria o handler e um formulário sem ficheiro.
+	mockService := new(service.MediaServiceMock)
+	handler := NewMediaHandler(mockService, &config.Config{MediaMaxSize: 1024})
+
+	productID := uuid.New()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	assert.NoError(t, writer.WriteField("alt_text", "Frente"))
+	assert.NoError(t, writer.Close())
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/media", body), "id", productID.String())
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler.
+	handler.HandleUpload(rr, req)
+
+	// Assert: Verifica se a falta do ficheiro devolve 400.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "Upload")
+}
+
+func TestHandleReorderMedia_InvalidOrder(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.MediaServiceMock)
+	handler := NewMediaHandler(mockService, &config.Config{})
+
+	productID, mediaID := uuid.New(), uuid.New()
+	requestBody := `{"media_ids": ["` + mediaID.String() + `"]}`
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/"+productID.String()+"/media/order", bytes.NewBufferString(requestBody)), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço rejeita uma ordem que não lista todas as imagens.
+	mockService.On("Reorder", mock.Anything, productID, []uuid.UUID{mediaID}).Return(nil, domain.ErrInvalidMedia)
+
+	// Act: Chama o handler.
+	handler.HandleReorder(rr, req)
+
+	// Assert: Verifica se a ordem inválida devolve 400.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleUpdateMedia_NotFound(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.MediaServiceMock)
+	handler := NewMediaHandler(mockService, &config.Config{})
+
+	productID, mediaID := uuid.New(), uuid.New()
+	req := httptest.NewRequest(http.MethodPut, "/products/"+productID.String()+"/media/"+mediaID.String(), bytes.NewBufferString(`{"alt_text": "Verso"}`))
+	req = withURLParam(withURLParam(req, "id", productID.String()), "mediaId", mediaID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: A imagem não pertence ao produto.
+	mockService.On("UpdateAltText", mock.Anything, productID, mediaID, "Verso").Return(nil, domain.ErrMediaNotFound)
+
+	// Act: Chama o handler.
+	handler.HandleUpdate(rr, req)
+
+	// Assert: Verifica se a imagem inexistente devolve 404.
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleServeMedia_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.MediaServiceMock)
+	handler := NewMediaHandler(mockService, &config.Config{})
+
+	key := "products/a/b.png"
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/media/"+key, nil), "*", key)
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço devolve o conteúdo guardado.
+	mockService.On("Open", mock.Anything, key).Return(io.NopCloser(strings.NewReader("png")), nil)
+
+	// Act: Chama o handler.
+	handler.HandleServe(rr, req)
+
+	// Assert: Verifica o conteúdo e o tipo pela extensão.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "png", rr.Body.String())
+	mockService.AssertExpectations(t)
+}
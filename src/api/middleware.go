@@ -5,12 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"product-service/src/domain"
 	"strings"
+
+	"github.com/google/uuid"
 )
 
-type contextKey string
+const (
+	UserIDContextKey    = domain.UserIDContextKey
+	CorrelationIDHeader = "X-Correlation-ID"
 
-const UserIDContextKey contextKey = "userID"
+	maxCorrelationIDLength = 255
+)
 
 type authResponse struct {
 	IsValid bool   `json:"is_valid"`
@@ -71,3 +77,18 @@ func (h *Handler) APIKeyAuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// CorrelationIDMiddleware propaga o X-Correlation-ID recebido, ou gera um novo,
+// para que os registos de auditoria possam ser ligados ao pedido de origem.
+func CorrelationIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID := r.Header.Get(CorrelationIDHeader)
+		if correlationID == "" || len(correlationID) > maxCorrelationIDLength {
+			correlationID = uuid.NewString()
+		}
+		w.Header().Set(CorrelationIDHeader, correlationID)
+
+		ctx := context.WithValue(r.Context(), domain.CorrelationIDContextKey, correlationID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"product-service/src/domain"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// urlParamUUID lê um UUID da URL, respondendo 400 quando o valor é inválido.
+func urlParamUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, name))
+	if err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: "invalid ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// urlParamInt lê um inteiro positivo de um parâmetro do caminho, respondendo 400 quando é inválido.
+func urlParamInt(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	value, err := strconv.Atoi(chi.URLParam(r, name))
+	if err != nil || value <= 0 {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: "invalid " + name})
+		return 0, false
+	}
+	return value, true
+}
+
+// queryParamInt lê um inteiro opcional da query string, devolvendo 0 quando ausente.
+func queryParamInt(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: "invalid " + name})
+		return 0, false
+	}
+	return value, true
+}
+
+// queryParamMoney lê um valor decimal opcional da query string na moeda indicada, devolvendo nil quando ausente.
+func queryParamMoney(w http.ResponseWriter, r *http.Request, name, currency string) (*domain.Money, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, true
+	}
+	value, err := domain.ParseMoney(raw, currency)
+	if err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: "invalid " + name + ": " + err.Error()})
+		return nil, false
+	}
+	return &value, true
+}
+
+// queryParamTime lê uma data RFC 3339 opcional da query string, devolvendo nil quando ausente.
+func queryParamTime(w http.ResponseWriter, r *http.Request, name string) (*time.Time, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, true
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: "invalid " + name + ", expected RFC 3339"})
+		return nil, false
+	}
+	return &value, true
+}
+
+// queryParamBool lê um booleano opcional da query string, devolvendo false quando ausente.
+func queryParamBool(w http.ResponseWriter, r *http.Request, name string) (bool, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return false, true
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_INPUT", Message: "invalid " + name})
+		return false, false
+	}
+	return value, true
+}
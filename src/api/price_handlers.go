@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"time"
+)
+
+type PriceHandler struct {
+	service service.PriceService
+}
+
+type CreatePriceScheduleRequest struct {
+	Price         domain.Money `json:"price"`
+	EffectiveFrom time.Time    `json:"effective_from"`
+	EffectiveTo   *time.Time   `json:"effective_to"`
+}
+
+func NewPriceHandler(svc service.PriceService) *PriceHandler {
+	return &PriceHandler{service: svc}
+}
+
+func (h *PriceHandler) HandleListHistory(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	limit, ok := queryParamInt(w, r, "limit")
+	if !ok {
+		return
+	}
+
+	page, err := h.service.ListPriceHistory(r.Context(), id, limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, page)
+}
+
+func (h *PriceHandler) HandleCreateSchedule(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req CreatePriceScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, domain.ErrInvalidMoney) || errors.Is(err, domain.ErrInvalidCurrency) {
+			handleError(w, err)
+			return
+		}
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	schedule, err := h.service.CreateSchedule(r.Context(), id, req.Price, req.EffectiveFrom, req.EffectiveTo)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, schedule)
+}
+
+func (h *PriceHandler) HandleListSchedules(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	schedules, err := h.service.ListSchedules(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, schedules)
+}
+
+func (h *PriceHandler) HandleCancelSchedule(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	scheduleID, ok := urlParamUUID(w, r, "scheduleId")
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.CancelSchedule(r.Context(), id, scheduleID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, schedule)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleCreatePriceSchedule_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.PriceServiceMock)
+	handler := NewPriceHandler(mockService)
+
+	productID := uuid.New()
+	requestBody := `{"price": {"amount": "79.90", "currency": "BRL"}, "effective_from": "2025-11-28T00:00:00Z", "effective_to": "2025-11-29T00:00:00Z"}`
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/price-schedules", bytes.NewBufferString(requestBody)), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço recebe o preço e a janela da promoção.
+	from := time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	schedule := &domain.PriceSchedule{ID: uuid.New(), ProductID: productID, Price: domain.Money{Minor: 7990, Currency: "BRL"}, EffectiveFrom: from, EffectiveTo: &to, Status: domain.SchedulePending}
+	mockService.On("CreateSchedule", mock.Anything, productID, domain.Money{Minor: 7990, Currency: "BRL"},
+		mock.MatchedBy(func(t time.Time) bool { return t.Equal(from) }),
+		mock.MatchedBy(func(t *time.Time) bool { return t != nil && t.Equal(to) })).Return(schedule, nil)
+
+	// Act: Chama o handler.
+	handler.HandleCreateSchedule(rr, req)
+
+	// Assert: Verifica se o agendamento é criado como pendente.
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response domain.PriceSchedule
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, domain.SchedulePending, response.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleCreatePriceSchedule_Overlap(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.PriceServiceMock)
+	handler := NewPriceHandler(mockService)
+
+	productID := uuid.New()
+	requestBody := `{"price": {"amount": "79.90", "currency": "BRL"}, "effective_from": "2025-11-28T00:00:00Z"}`
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/price-schedules", bytes.NewBufferString(requestBody)), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: Já existe um agendamento na mesma janela.
+	mockService.On("CreateSchedule", mock.Anything, productID, mock.Anything, mock.Anything, (*time.Time)(nil)).
+		Return(nil, fmt.Errorf("Error creating price schedule: %w", domain.ErrPriceScheduleOverlap))
+
+	// Act: Chama o handler.
+	handler.HandleCreateSchedule(rr, req)
+
+	// Assert: Verifica se a sobreposição dá 409.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var errResponse ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResponse))
+	assert.Equal(t, "PRICE_SCHEDULE_OVERLAP", errResponse.Code)
+}
+
+func TestHandleCancelPriceSchedule_Closed(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.PriceServiceMock)
+	handler := NewPriceHandler(mockService)
+
+	productID, scheduleID := uuid.New(), uuid.New()
+	req := httptest.NewRequest(http.MethodDelete, "/products/"+productID.String()+"/price-schedules/"+scheduleID.String(), nil)
+	req = withURLParam(withURLParam(req, "id", productID.String()), "scheduleId", scheduleID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O agendamento já terminou.
+	mockService.On("CancelSchedule", mock.Anything, productID, scheduleID).Return(nil, fmt.Errorf("Error cancelling price schedule: %w", domain.ErrPriceScheduleClosed))
+
+	// Act: Chama o handler.
+	handler.HandleCancelSchedule(rr, req)
+
+	// Assert: Verifica se o cancelamento é rejeitado com 409.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/service"
+)
+
+type PriceListHandler struct {
+	service service.PriceListService
+}
+
+type CreatePriceListRequest struct {
+	Name          string `json:"name"`
+	CustomerGroup string `json:"customer_group"`
+}
+
+type SetPriceTiersRequest struct {
+	Tiers []domain.PriceTier `json:"tiers"`
+}
+
+func NewPriceListHandler(svc service.PriceListService) *PriceListHandler {
+	return &PriceListHandler{service: svc}
+}
+
+func (h *PriceListHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req CreatePriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	priceList := &domain.PriceList{Name: req.Name, CustomerGroup: req.CustomerGroup}
+	if err := h.service.CreatePriceList(r.Context(), priceList); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, priceList)
+}
+
+func (h *PriceListHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	priceLists, err := h.service.ListPriceLists(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, priceLists)
+}
+
+func (h *PriceListHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	priceList, err := h.service.GetPriceList(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, priceList)
+}
+
+func (h *PriceListHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePriceList(r.Context(), id); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Price list deleted successfully"})
+}
+
+func (h *PriceListHandler) HandleSetProductTiers(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	productID, ok := urlParamUUID(w, r, "productId")
+	if !ok {
+		return
+	}
+
+	var req SetPriceTiersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, domain.ErrInvalidMoney) || errors.Is(err, domain.ErrInvalidCurrency) {
+			handleError(w, err)
+			return
+		}
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	tiers, err := h.service.SetProductTiers(r.Context(), id, productID, req.Tiers)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, tiers)
+}
+
+func (h *PriceListHandler) HandleListProductTiers(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	productID, ok := urlParamUUID(w, r, "productId")
+	if !ok {
+		return
+	}
+
+	tiers, err := h.service.ListProductTiers(r.Context(), id, productID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, tiers)
+}
+
+// HandleQuote devolve o preço de ?quantity= unidades (1 por omissão) para o ?customer_group= opcional.
+func (h *PriceListHandler) HandleQuote(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	quantity, ok := queryParamInt(w, r, "quantity")
+	if !ok {
+		return
+	}
+	if r.URL.Query().Get("quantity") == "" {
+		quantity = 1
+	}
+
+	quote, err := h.service.Quote(r.Context(), id, r.URL.Query().Get("customer_group"), quantity)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, quote)
+}
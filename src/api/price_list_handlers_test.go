@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleCreatePriceList_GroupTaken(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.PriceListServiceMock)
+	handler := NewPriceListHandler(mockService)
+
+	requestBody := `{"name": "Grossistas", "customer_group": "grossista"}`
+	req := httptest.NewRequest(http.MethodPost, "/price-lists", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Mock: O grupo de clientes já tem uma tabela de preços.
+	mockService.On("CreatePriceList", mock.Anything, mock.MatchedBy(func(p *domain.PriceList) bool { return p.CustomerGroup == "grossista" })).
+		Return(fmt.Errorf("Error creating price list: %w", domain.ErrPriceListGroupTaken))
+
+	// Act: Chama o handler.
+	handler.HandleCreate(rr, req)
+
+	// Assert: Verifica se responde com conflito.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var response ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "PRICE_LIST_GROUP_TAKEN", response.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetProductTiers_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.PriceListServiceMock)
+	handler := NewPriceListHandler(mockService)
+
+	priceListID, productID := uuid.New(), uuid.New()
+	requestBody := `{"tiers": [{"min_quantity": 10, "unit_price": {"amount": "9.00", "currency": "BRL"}}, {"min_quantity": 100, "unit_price": {"amount": "8.00", "currency": "BRL"}}]}`
+	req := httptest.NewRequest(http.MethodPut, "/price-lists/"+priceListID.String()+"/products/"+productID.String(), bytes.NewBufferString(requestBody))
+	req = withURLParam(withURLParam(req, "id", priceListID.String()), "productId", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço recebe os escalões do pedido.
+	tiers := []domain.PriceTier{{MinQuantity: 10, UnitPrice: domain.Money{Minor: 900, Currency: "BRL"}}, {MinQuantity: 100, UnitPrice: domain.Money{Minor: 800, Currency: "BRL"}}}
+	mockService.On("SetProductTiers", mock.Anything, priceListID, productID, tiers).Return(tiers, nil)
+
+	// Act: Chama o handler.
+	handler.HandleSetProductTiers(rr, req)
+
+	// Assert: Verifica se os escalões gravados são devolvidos.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []domain.PriceTier
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, tiers, response)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetProductTiers_InvalidTier(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.PriceListServiceMock)
+	handler := NewPriceListHandler(mockService)
+
+	priceListID, productID := uuid.New(), uuid.New()
+	requestBody := `{"tiers": [{"min_quantity": 0, "unit_price": {"amount": "9.00", "currency": "BRL"}}]}`
+	req := httptest.NewRequest(http.MethodPut, "/price-lists/"+priceListID.String()+"/products/"+productID.String(), bytes.NewBufferString(requestBody))
+	req = withURLParam(withURLParam(req, "id", priceListID.String()), "productId", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço rejeita uma quantidade mínima inválida.
+	mockService.On("SetProductTiers", mock.Anything, priceListID, productID, mock.Anything).
+		Return(nil, fmt.Errorf("Error setting price tiers: %w", domain.ErrInvalidPriceTier))
+
+	// Act: Chama o handler.
+	handler.HandleSetProductTiers(rr, req)
+
+	// Assert: Verifica se responde com erro de validação.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleQuote_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.PriceListServiceMock)
+	handler := NewPriceListHandler(mockService)
+
+	productID, priceListID := uuid.New(), uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/products/"+productID.String()+"/quote?quantity=12&customer_group=grossista", nil), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O escalão de 10 unidades da tabela do grupo aplica-se.
+	quote := &domain.PriceQuote{ProductID: productID, CustomerGroup: "grossista", Quantity: 12, UnitPrice: domain.Money{Minor: 900, Currency: "BRL"},
+		LinePrice: domain.Money{Minor: 10800, Currency: "BRL"}, Source: domain.QuoteSourcePriceList, PriceListID: &priceListID, MinQuantity: 10}
+	mockService.On("Quote", mock.Anything, productID, "grossista", 12).Return(quote, nil)
+
+	// Act: Chama o handler.
+	handler.HandleQuote(rr, req)
+
+	// Assert: Verifica o preço unitário e o total da linha.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response domain.PriceQuote
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "9.00", response.UnitPrice.Amount())
+	assert.Equal(t, "108.00", response.LinePrice.Amount())
+	assert.Equal(t, domain.QuoteSourcePriceList, response.Source)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleQuote_DefaultsToOneUnit(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.PriceListServiceMock)
+	handler := NewPriceListHandler(mockService)
+
+	productID := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/products/"+productID.String()+"/quote", nil), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: Sem quantidade nem grupo, o preço é o do produto para uma unidade.
+	price := domain.Money{Minor: 1000, Currency: "BRL"}
+	mockService.On("Quote", mock.Anything, productID, "", 1).
+		Return(&domain.PriceQuote{ProductID: productID, Quantity: 1, UnitPrice: price, LinePrice: price, Source: domain.QuoteSourceRegular}, nil)
+
+	// Act: Chama o handler.
+	handler.HandleQuote(rr, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleQuote_InvalidQuantity(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.PriceListServiceMock)
+	handler := NewPriceListHandler(mockService)
+
+	productID := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/products/"+productID.String()+"/quote?quantity=dez", nil), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler.
+	handler.HandleQuote(rr, req)
+
+	// Assert: O serviço não é chamado e responde com erro de validação.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "Quote")
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/src/service"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReservationHandler struct {
+	service service.ReservationService
+}
+
+type ReserveStockRequest struct {
+	ProductID      uuid.UUID `json:"product_id"`
+	OrderReference string    `json:"order_reference"`
+	Quantity       int       `json:"quantity"`
+	TTLSeconds     int       `json:"ttl_seconds"`
+}
+
+func NewReservationHandler(svc service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{service: svc}
+}
+
+func (h *ReservationHandler) HandleReserve(w http.ResponseWriter, r *http.Request) {
+	var req ReserveStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	reservation, err := h.service.Reserve(r.Context(), req.ProductID, req.OrderReference, req.Quantity, ttl)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, reservation)
+}
+
+func (h *ReservationHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	reservation, err := h.service.GetReservationByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, reservation)
+}
+
+func (h *ReservationHandler) HandleCommit(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Commit(r.Context(), id); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Reservation committed successfully"})
+}
+
+func (h *ReservationHandler) HandleRelease(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Release(r.Context(), id); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Reservation released successfully"})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// withURLParam injeta um parâmetro de rota do chi na requisição.
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	// Reutiliza o contexto da rota para permitir vários parâmetros no mesmo pedido.
+	routeCtx, ok := req.Context().Value(chi.RouteCtxKey).(*chi.Context)
+	if !ok {
+		routeCtx = chi.NewRouteContext()
+	}
+	routeCtx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+}
+
+func TestHandleReserve_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ReservationServiceMock)
+	handler := NewReservationHandler(mockService)
+
+	productID := uuid.New()
+	requestBody := `{"product_id": "` + productID.String() + `", "order_reference": "order-1", "quantity": 2, "ttl_seconds": 60}`
+	req := httptest.NewRequest(http.MethodPost, "/products/reservations", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	reservation := &domain.Reservation{ID: uuid.New(), ProductID: productID, Quantity: 2, Status: domain.ReservationActive}
+	mockService.On("Reserve", mock.Anything, productID, "order-1", 2, time.Minute).Return(reservation, nil)
+
+	// Act: Chama o handler.
+	handler.HandleReserve(rr, req)
+
+	// Assert: Verifica se a reserva criada é devolvida com status 201.
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body domain.Reservation
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Equal(t, reservation.ID, body.ID)
+}
+
+func TestHandleReserve_InsufficientStock(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ReservationServiceMock)
+	handler := NewReservationHandler(mockService)
+
+	productID := uuid.New()
+	requestBody := `{"product_id": "` + productID.String() + `", "order_reference": "order-1", "quantity": 50}`
+	req := httptest.NewRequest(http.MethodPost, "/products/reservations", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	mockService.On("Reserve", mock.Anything, productID, "order-1", 50, time.Duration(0)).Return(nil, domain.ErrInsufficientStock)
+
+	// Act: Chama o handler.
+	handler.HandleReserve(rr, req)
+
+	// Assert: Verifica se o status code é 409 Conflict.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleCommit_NotActive(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ReservationServiceMock)
+	handler := NewReservationHandler(mockService)
+
+	id := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/reservations/"+id.String()+"/commit", nil), "id", id.String())
+	rr := httptest.NewRecorder()
+
+	mockService.On("Commit", mock.Anything, id).Return(domain.ErrReservationNotActive)
+
+	// Act: Chama o handler.
+	handler.HandleCommit(rr, req)
+
+	// Assert: Verifica se o status code é 409 Conflict.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleRelease_InvalidID(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ReservationServiceMock)
+	handler := NewReservationHandler(mockService)
+
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/reservations/abc/release", nil), "id", "abc")
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler.
+	handler.HandleRelease(rr, req)
+
+	// Assert: Verifica se o status code é 400 e se o serviço não foi chamado.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+)
+
+func (h *Handler) HandleListRevisions(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	revisions, err := h.service.ListRevisions(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, revisions)
+}
+
+func (h *Handler) HandleGetRevision(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	number, ok := urlParamInt(w, r, "revision")
+	if !ok {
+		return
+	}
+
+	revision, err := h.service.GetRevision(r.Context(), id, number)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, revision)
+}
+
+// HandleDiffRevisions compara as revisões indicadas pelos parâmetros from e to.
+func (h *Handler) HandleDiffRevisions(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	from, ok := queryParamInt(w, r, "from")
+	if !ok {
+		return
+	}
+	to, ok := queryParamInt(w, r, "to")
+	if !ok {
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(r.Context(), id, from, to)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, diff)
+}
+
+// HandleRevert repõe o produto na revisão indicada, exigindo If-Match com a versão atual.
+func (h *Handler) HandleRevert(w http.ResponseWriter, r *http.Request) {
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+	number, ok := urlParamInt(w, r, "revision")
+	if !ok {
+		return
+	}
+
+	product, err := h.service.RevertToRevision(r.Context(), id, number, version)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	w.Header().Set("ETag", formatETag(product.Version))
+	WriteJSON(w, http.StatusOK, product)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/config"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleDiffRevisions_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	id := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/products/"+id.String()+"/revisions/diff?from=1&to=3", nil), "id", id.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: Entre as duas revisões só mudou a descrição.
+	diff := &domain.RevisionDiff{ProductID: id, From: 1, To: 3, Changes: []domain.FieldChange{
+		{Field: "description", From: json.RawMessage(`"Antiga"`), To: json.RawMessage(`"Nova"`)},
+	}}
+	mockService.On("DiffRevisions", mock.Anything, id, 1, 3).Return(diff, nil)
+
+	// Act: Chama o handler.
+	handler.HandleDiffRevisions(rr, req)
+
+	// Assert: Verifica se a diferença é devolvida campo a campo.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response domain.RevisionDiff
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response.Changes, 1)
+	assert.Equal(t, "description", response.Changes[0].Field)
+	assert.JSONEq(t, `"Nova"`, string(response.Changes[0].To))
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleGetRevision_InvalidNumber(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	id := uuid.New()
+	req := withURLParam(withURLParam(httptest.NewRequest(http.MethodGet, "/products/"+id.String()+"/revisions/abc", nil), "id", id.String()), "revision", "abc")
+	rr := httptest.NewRecorder()
+
+	// Act: Chama o handler.
+	handler.HandleGetRevision(rr, req)
+
+	// Assert: Verifica se o número inválido é rejeitado sem chamar o serviço.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "GetRevision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleRevert_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.ProductServiceMock)
+	handler := NewHandler(mockService, &config.Config{})
+
+	id := uuid.New()
+	req := withURLParam(withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+id.String()+"/revisions/2/revert", nil), "id", id.String()), "revision", "2")
+	req.Header.Set("If-Match", `"5"`)
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço reverte o produto e devolve a nova versão.
+	product := &domain.Product{ID: id, Status: domain.StatusActive, Price: domain.Money{Minor: 1990, Currency: "BRL"}, Version: 6}
+	mockService.On("RevertToRevision", mock.Anything, id, 2, int64(5)).Return(product, nil)
+
+	// Act: Chama o handler.
+	handler.HandleRevert(rr, req)
+
+	// Assert: Verifica se o produto é devolvido com o novo ETag.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"6"`, rr.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
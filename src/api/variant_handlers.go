@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/service"
+)
+
+type VariantHandler struct {
+	service service.VariantService
+}
+
+type SetProductOptionsRequest struct {
+	Options []domain.ProductOption `json:"options"`
+}
+
+type CreateVariantRequest struct {
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	// Price é opcional; sem ele a variante usa o preço do produto.
+	Price *domain.Money `json:"price"`
+	Stock int           `json:"stock"`
+}
+
+func NewVariantHandler(svc service.VariantService) *VariantHandler {
+	return &VariantHandler{service: svc}
+}
+
+func (h *VariantHandler) HandleSetProductOptions(w http.ResponseWriter, r *http.Request) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req SetProductOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	options, err := h.service.SetProductOptions(r.Context(), productID, req.Options)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, options)
+}
+
+// HandleListProductOptions é a leitura pública, que só mostra produtos ativos.
+func (h *VariantHandler) HandleListProductOptions(w http.ResponseWriter, r *http.Request) {
+	h.listProductOptions(w, r, domain.StatusActive)
+}
+
+// HandleAdminListProductOptions lê as opções do produto em qualquer estado.
+func (h *VariantHandler) HandleAdminListProductOptions(w http.ResponseWriter, r *http.Request) {
+	h.listProductOptions(w, r, "")
+}
+
+func (h *VariantHandler) listProductOptions(w http.ResponseWriter, r *http.Request, status domain.ProductStatus) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	options, err := h.service.ListProductOptions(r.Context(), productID, status)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, options)
+}
+
+func (h *VariantHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req CreateVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if errors.Is(err, domain.ErrInvalidMoney) || errors.Is(err, domain.ErrInvalidCurrency) {
+			handleError(w, err)
+			return
+		}
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	variant := &domain.Variant{ProductID: productID, SKU: req.SKU, Options: req.Options, Price: req.Price, Stock: req.Stock}
+	if err := h.service.Create(r.Context(), variant); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, variant)
+}
+
+// HandleList é a leitura pública, que só mostra as variantes de produtos ativos.
+func (h *VariantHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, domain.StatusActive)
+}
+
+// HandleAdminList lê as variantes do produto em qualquer estado.
+func (h *VariantHandler) HandleAdminList(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, "")
+}
+
+func (h *VariantHandler) list(w http.ResponseWriter, r *http.Request, status domain.ProductStatus) {
+	productID, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	variants, err := h.service.ListVariants(r.Context(), productID, status)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, variants)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleCreateVariant_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.VariantServiceMock)
+	handler := NewVariantHandler(mockService)
+
+	productID := uuid.New()
+	requestBody := `{"sku": "CAM-AZ-M", "options": {"cor": "azul", "tamanho": "M"}, "price": {"amount": "59.90", "currency": "BRL"}, "stock": 4}`
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/variants", bytes.NewBufferString(requestBody)), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	// Mock: O serviço recebe a variante do produto da URL e preenche o preço efetivo.
+	mockService.On("Create", mock.Anything, mock.MatchedBy(func(v *domain.Variant) bool {
+		return v.ProductID == productID && v.SKU == "CAM-AZ-M" && v.Options["tamanho"] == "M" &&
+			*v.Price == domain.Money{Minor: 5990, Currency: "BRL"} && v.Stock == 4
+	})).Run(func(args mock.Arguments) {
+		variant := args.Get(1).(*domain.Variant)
+		variant.ID = uuid.New()
+		variant.EffectivePrice = *variant.Price
+	}).Return(nil)
+
+	// Act: Chama o handler.
+	handler.HandleCreate(rr, req)
+
+	// Assert: Verifica se a variante criada é devolvida com status 201.
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body domain.Variant
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Equal(t, "CAM-AZ-M", body.SKU)
+	assert.Equal(t, "59.90", body.EffectivePrice.Amount())
+}
+
+func TestHandleCreateVariant_SKUTaken(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.VariantServiceMock)
+	handler := NewVariantHandler(mockService)
+
+	productID := uuid.New()
+	requestBody := `{"sku": "CAM-AZ-M", "options": {"tamanho": "M"}, "stock": 1}`
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/products/"+productID.String()+"/variants", bytes.NewBufferString(requestBody)), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	mockService.On("Create", mock.Anything, mock.Anything).Return(domain.ErrSKUTaken)
+
+	// Act: Chama o handler.
+	handler.HandleCreate(rr, req)
+
+	// Assert: Verifica se o SKU repetido dá 409.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var errResponse ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResponse))
+	assert.Equal(t, "SKU_TAKEN", errResponse.Code)
+}
+
+func TestHandleSetProductOptions_InvalidOptions(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.VariantServiceMock)
+	handler := NewVariantHandler(mockService)
+
+	productID := uuid.New()
+	requestBody := `{"options": [{"name": "tamanho", "values": []}]}`
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/"+productID.String()+"/options", bytes.NewBufferString(requestBody)), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	mockService.On("SetProductOptions", mock.Anything, productID, []domain.ProductOption{{Name: "tamanho", Values: []string{}}}).
+		Return(nil, domain.ErrInvalidVariantOptions)
+
+	// Act: Chama o handler.
+	handler.HandleSetProductOptions(rr, req)
+
+	// Assert: Uma opção sem valores é rejeitada.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleSetProductOptions_ProductHasVariants(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.VariantServiceMock)
+	handler := NewVariantHandler(mockService)
+
+	productID := uuid.New()
+	requestBody := `{"options": [{"name": "tamanho", "values": ["P", "M"]}]}`
+	req := withURLParam(httptest.NewRequest(http.MethodPut, "/products/"+productID.String()+"/options", bytes.NewBufferString(requestBody)), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	mockService.On("SetProductOptions", mock.Anything, productID, mock.Anything).Return(nil, domain.ErrProductHasVariants)
+
+	// Act: Chama o handler.
+	handler.HandleSetProductOptions(rr, req)
+
+	// Assert: As opções não mudam depois de criadas variantes.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var errResponse ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResponse))
+	assert.Equal(t, "PRODUCT_HAS_VARIANTS", errResponse.Code)
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/service"
+
+	"github.com/google/uuid"
+)
+
+type WarehouseHandler struct {
+	service service.WarehouseService
+}
+
+type CreateWarehouseRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+}
+
+type UpdateWarehouseRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+	Active   bool   `json:"active"`
+}
+
+type TransferStockRequest struct {
+	ProductID       uuid.UUID `json:"product_id"`
+	FromWarehouseID uuid.UUID `json:"from_warehouse_id"`
+	ToWarehouseID   uuid.UUID `json:"to_warehouse_id"`
+	Quantity        int       `json:"quantity"`
+}
+
+func NewWarehouseHandler(svc service.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{service: svc}
+}
+
+func (h *WarehouseHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req CreateWarehouseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	warehouse, err := h.service.Create(r.Context(), req.Code, req.Name, req.Priority)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, warehouse)
+}
+
+func (h *WarehouseHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	warehouse, err := h.service.GetWarehouseByID(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, warehouse)
+}
+
+func (h *WarehouseHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	warehouses, err := h.service.ListWarehouses(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, warehouses)
+}
+
+func (h *WarehouseHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req UpdateWarehouseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	warehouse := &domain.Warehouse{
+		ID:       id,
+		Code:     req.Code,
+		Name:     req.Name,
+		Priority: req.Priority,
+		Active:   req.Active,
+	}
+	if err := h.service.Update(r.Context(), warehouse); err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"message": "Warehouse updated successfully"})
+}
+
+func (h *WarehouseHandler) HandleTransfer(w http.ResponseWriter, r *http.Request) {
+	var req TransferStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteJSON(w, http.StatusBadRequest, ErrorResponse{Code: "INVALID_REQUEST_BODY", Message: "Invalid request body"})
+		return
+	}
+
+	transfer, err := h.service.Transfer(r.Context(), req.ProductID, req.FromWarehouseID, req.ToWarehouseID, req.Quantity)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusCreated, transfer)
+}
+
+func (h *WarehouseHandler) HandleGetProductInventory(w http.ResponseWriter, r *http.Request) {
+	id, ok := urlParamUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	inventory, err := h.service.GetProductInventory(r.Context(), id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, inventory)
+}
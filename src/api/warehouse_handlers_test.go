@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"product-service/src/domain"
+	"product-service/src/service"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleCreateWarehouse_CodeTaken(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.WarehouseServiceMock)
+	handler := NewWarehouseHandler(mockService)
+
+	requestBody := `{"code": "LIS", "name": "Lisboa", "priority": 1}`
+	req := httptest.NewRequest(http.MethodPost, "/warehouses", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	mockService.On("Create", mock.Anything, "LIS", "Lisboa", 1).Return(nil, domain.ErrWarehouseCodeTaken)
+
+	// Act: Chama o handler.
+	handler.HandleCreate(rr, req)
+
+	// Assert: Verifica se o código duplicado devolve 409.
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "WAREHOUSE_CODE_TAKEN")
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleTransfer_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.WarehouseServiceMock)
+	handler := NewWarehouseHandler(mockService)
+
+	productID, fromID, toID := uuid.New(), uuid.New(), uuid.New()
+	requestBody := `{"product_id": "` + productID.String() + `", "from_warehouse_id": "` + fromID.String() + `", "to_warehouse_id": "` + toID.String() + `", "quantity": 3}`
+	req := httptest.NewRequest(http.MethodPost, "/warehouses/transfers", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	transfer := &domain.StockTransfer{ID: uuid.New(), ProductID: productID, FromWarehouseID: fromID, ToWarehouseID: toID, Quantity: 3}
+	mockService.On("Transfer", mock.Anything, productID, fromID, toID, 3).Return(transfer, nil)
+
+	// Act: Chama o handler.
+	handler.HandleTransfer(rr, req)
+
+	// Assert: Verifica se a transferência é devolvida com status 201.
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body domain.StockTransfer
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Equal(t, transfer.ID, body.ID)
+}
+
+func TestHandleTransfer_SameWarehouse(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.WarehouseServiceMock)
+	handler := NewWarehouseHandler(mockService)
+
+	productID, warehouseID := uuid.New(), uuid.New()
+	requestBody := `{"product_id": "` + productID.String() + `", "from_warehouse_id": "` + warehouseID.String() + `", "to_warehouse_id": "` + warehouseID.String() + `", "quantity": 3}`
+	req := httptest.NewRequest(http.MethodPost, "/warehouses/transfers", bytes.NewBufferString(requestBody))
+	rr := httptest.NewRecorder()
+
+	mockService.On("Transfer", mock.Anything, productID, warehouseID, warehouseID, 3).Return(nil, domain.ErrSameWarehouseTransfer)
+
+	// Act: Chama o handler.
+	handler.HandleTransfer(rr, req)
+
+	// Assert: Verifica se o pedido é rejeitado com 400.
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleGetProductInventory_Success(t *testing.T) {
+	// Arrange: Cria o mock do serviço e o handler.
+	mockService := new(service.WarehouseServiceMock)
+	handler := NewWarehouseHandler(mockService)
+
+	productID := uuid.New()
+	req := withURLParam(httptest.NewRequest(http.MethodGet, "/products/"+productID.String()+"/inventory", nil), "id", productID.String())
+	rr := httptest.NewRecorder()
+
+	inventory := &domain.ProductInventory{ProductID: productID, Stock: 10, AvailableStock: 8, Unassigned: 4,
+		Locations: []*domain.LocationStock{{WarehouseID: uuid.New(), Code: "LIS", Name: "Lisboa", Quantity: 6}}}
+	mockService.On("GetProductInventory", mock.Anything, productID).Return(inventory, nil)
+
+	// Act: Chama o handler.
+	handler.HandleGetProductInventory(rr, req)
+
+	// Assert: Verifica se o inventário por armazém é devolvido.
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+
+	var body domain.ProductInventory
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", domain.ErrFailedToUnmarshalJSON)
+	}
+	assert.Equal(t, 4, body.Unassigned)
+	assert.Len(t, body.Locations, 1)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"log"
 	"product-service/src/config"
+	"product-service/src/domain"
 	"product-service/src/repository"
 	"product-service/src/server"
 	"product-service/src/service"
+	"product-service/src/storage"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
@@ -26,9 +28,72 @@ func main() {
 	defer pool.Close()
 	log.Println("Successfully connected to PostgreSQL.")
 
-	productRepo := repository.NewProduct(pool)
-	productService := service.NewProductService(productRepo)
-	httpServer := server.NewServer(cfg, productService)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	allocation, err := domain.ParseAllocationStrategy(cfg.StockAllocationStrategy)
+	if err != nil {
+		log.Fatalf("Invalid STOCK_ALLOCATION_STRATEGY: %v", err)
+	}
+
+	blobs, err := storage.NewLocal(cfg.MediaDir, cfg.MediaBaseURL)
+	if err != nil {
+		log.Fatalf("Failed to open media storage: %v", err)
+	}
+
+	productRepo := repository.NewProduct(pool, allocation)
+	attributeRepo := repository.NewAttribute(pool)
+	mediaRepo := repository.NewMedia(pool)
+	productService := service.NewProductService(productRepo, mediaRepo, blobs, cfg.DeletedProductRetention)
+	go service.RunPeriodically(ctx, "deleted product purge", cfg.DeletedProductPurgeInterval, func(ctx context.Context) error {
+		_, err := productService.PurgeDeleted(ctx)
+		return err
+	})
+
+	reservationRepo := repository.NewReservation(pool, allocation)
+	reservationService := service.NewReservationService(reservationRepo, cfg.ReservationTTL)
+	go service.RunPeriodically(ctx, "reservation expiry", cfg.ReservationSweepInterval, func(ctx context.Context) error {
+		_, err := reservationService.ReleaseExpired(ctx)
+		return err
+	})
+
+	idempotencyRepo := repository.NewIdempotency(pool)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.IdempotencyKeyRetention)
+	go service.RunPeriodically(ctx, "idempotency key purge", cfg.IdempotencyPurgeInterval, func(ctx context.Context) error {
+		_, err := idempotencyService.PurgeExpired(ctx)
+		return err
+	})
+
+	warehouseRepo := repository.NewWarehouse(pool)
+	warehouseService := service.NewWarehouseService(warehouseRepo)
+
+	categoryRepo := repository.NewCategory(pool)
+	categoryService := service.NewCategoryService(categoryRepo)
+
+	variantRepo := repository.NewVariant(pool)
+	variantService := service.NewVariantService(variantRepo)
+
+	priceRepo := repository.NewPrice(pool)
+	priceService := service.NewPriceService(priceRepo)
+	go service.RunPeriodically(ctx, "scheduled prices", cfg.PriceScheduleInterval, func(ctx context.Context) error {
+		_, err := priceService.ApplyDueSchedules(ctx)
+		return err
+	})
+
+	priceListRepo := repository.NewPriceList(pool)
+	priceListService := service.NewPriceListService(priceListRepo, productRepo)
+
+	currencyRepo := repository.NewCurrency(pool)
+	currencyService := service.NewCurrencyService(currencyRepo)
+
+	bundleRepo := repository.NewBundle(pool)
+	bundleService := service.NewBundleService(bundleRepo)
+
+	attributeService := service.NewAttributeService(attributeRepo)
+
+	mediaService := service.NewMediaService(mediaRepo, blobs, cfg.MediaMaxSize)
+
+	httpServer := server.NewServer(cfg, productService, reservationService, idempotencyService, warehouseService, categoryService, variantService, priceService, priceListService, currencyService, bundleService, attributeService, mediaService)
 
 	httpServer.Run()
 
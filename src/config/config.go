@@ -1,20 +1,49 @@
 package config
 
-import "os"
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
 
 type Config struct {
-	ListenAddr     string
-	InternalAPIKey string
-	DatabaseURL    string
-	AuthServiceURL string
+	ListenAddr                  string
+	InternalAPIKey              string
+	DatabaseURL                 string
+	AuthServiceURL              string
+	ReservationTTL              time.Duration
+	ReservationSweepInterval    time.Duration
+	IdempotencyKeyRetention     time.Duration
+	IdempotencyPurgeInterval    time.Duration
+	DeletedProductRetention     time.Duration
+	DeletedProductPurgeInterval time.Duration
+	PriceScheduleInterval       time.Duration
+	StockAllocationStrategy     string
+	DefaultCurrency             string
+	MediaDir                    string
+	MediaBaseURL                string
+	MediaMaxSize                int64
 }
 
 func Load() *Config {
 	return &Config{
-		ListenAddr:     getEnv("LISTEN_ADDR", ":8083"),
-		InternalAPIKey: getEnv("INTERNAL_API_KEY", ""),
-		DatabaseURL:    getEnv("DATABASE_URL", ""),
-		AuthServiceURL: getEnv("AUTH_SERVICE_URL", "http://localhost:8081"),
+		ListenAddr:                  getEnv("LISTEN_ADDR", ":8083"),
+		InternalAPIKey:              getEnv("INTERNAL_API_KEY", ""),
+		DatabaseURL:                 getEnv("DATABASE_URL", ""),
+		AuthServiceURL:              getEnv("AUTH_SERVICE_URL", "http://localhost:8081"),
+		ReservationTTL:              getEnvDuration("RESERVATION_TTL", 15*time.Minute),
+		ReservationSweepInterval:    getEnvDuration("RESERVATION_SWEEP_INTERVAL", time.Minute),
+		IdempotencyKeyRetention:     getEnvDuration("IDEMPOTENCY_KEY_RETENTION", 24*time.Hour),
+		IdempotencyPurgeInterval:    getEnvDuration("IDEMPOTENCY_PURGE_INTERVAL", time.Hour),
+		DeletedProductRetention:     getEnvDuration("DELETED_PRODUCT_RETENTION", 30*24*time.Hour),
+		DeletedProductPurgeInterval: getEnvDuration("DELETED_PRODUCT_PURGE_INTERVAL", time.Hour),
+		PriceScheduleInterval:       getEnvDuration("PRICE_SCHEDULE_INTERVAL", time.Minute),
+		StockAllocationStrategy:     getEnv("STOCK_ALLOCATION_STRATEGY", "priority"),
+		DefaultCurrency:             getEnv("DEFAULT_CURRENCY", "BRL"),
+		MediaDir:                    getEnv("MEDIA_DIR", "./media"),
+		MediaBaseURL:                getEnv("MEDIA_BASE_URL", "/media"),
+		MediaMaxSize:                getEnvInt64("MEDIA_MAX_SIZE", 5<<20),
 	}
 }
 
@@ -24,3 +53,25 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getEnvDuration lê uma duração positiva; valores inválidos, nulos ou negativos
+// são ignorados, já que um intervalo assim faria parar as rotinas periódicas.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		duration, err := time.ParseDuration(value)
+		if err == nil && duration > 0 {
+			return duration
+		}
+		log.Printf("WARNING: invalid %s %q, using %s", key, value, fallback)
+	}
+	return fallback
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	if value, ok := os.LookupEnv(key); ok {
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return number
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_RejectsNonPositiveDurations(t *testing.T) {
+	// Arrange: Define intervalos nulos, negativos e inválidos.
+	t.Setenv("RESERVATION_SWEEP_INTERVAL", "0s")
+	t.Setenv("DELETED_PRODUCT_PURGE_INTERVAL", "-5m")
+	t.Setenv("PRICE_SCHEDULE_INTERVAL", "depressa")
+	t.Setenv("IDEMPOTENCY_PURGE_INTERVAL", "30m")
+
+	// Act: Carrega a configuração.
+	cfg := Load()
+
+	// Assert: Os valores não positivos dão lugar aos valores por omissão.
+	assert.Equal(t, time.Minute, cfg.ReservationSweepInterval)
+	assert.Equal(t, time.Hour, cfg.DeletedProductPurgeInterval)
+	assert.Equal(t, time.Minute, cfg.PriceScheduleInterval)
+	assert.Equal(t, 30*time.Minute, cfg.IdempotencyPurgeInterval)
+}
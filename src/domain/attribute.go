@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttributeType é o tipo de valor aceite por um atributo.
+type AttributeType string
+
+const (
+	AttributeText    AttributeType = "text"
+	AttributeNumber  AttributeType = "number"
+	AttributeBoolean AttributeType = "boolean"
+	AttributeEnum    AttributeType = "enum"
+)
+
+// AttributeDefinition descreve um atributo dos produtos de uma categoria e das
+// suas subcategorias (ex: voltagem em Eletrodomésticos).
+type AttributeDefinition struct {
+	CategoryID uuid.UUID     `json:"category_id"`
+	Name       string        `json:"name"`
+	Type       AttributeType `json:"type"`
+	// Unit é informativa (ex: "V", "meses") e só se aplica a números.
+	Unit string `json:"unit,omitempty"`
+	// AllowedValues lista os valores aceites por um atributo enum.
+	AllowedValues []string  `json:"allowed_values,omitempty"`
+	Required      bool      `json:"required"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Attributes são os valores dos atributos de um produto por nome: texto,
+// número ou booleano, conforme a definição.
+type Attributes map[string]any
+
+// NormalizeAttributeDefinition valida a definição e normaliza o nome, a
+// unidade e os valores permitidos.
+func NormalizeAttributeDefinition(definition *AttributeDefinition) error {
+	definition.Name = strings.ToLower(strings.TrimSpace(definition.Name))
+	if len(definition.Name) > 100 || !ValidSlug(definition.Name) {
+		return fmt.Errorf("%w: name must be a slug of up to 100 characters", ErrInvalidAttributeDefinition)
+	}
+	definition.Unit = strings.TrimSpace(definition.Unit)
+	if len(definition.Unit) > 20 {
+		return fmt.Errorf("%w: unit must have up to 20 characters", ErrInvalidAttributeDefinition)
+	}
+
+	switch definition.Type {
+	case AttributeText, AttributeNumber, AttributeBoolean:
+		if len(definition.AllowedValues) > 0 {
+			return fmt.Errorf("%w: allowed values only apply to enum attributes", ErrInvalidAttributeDefinition)
+		}
+	case AttributeEnum:
+		values := make([]string, 0, len(definition.AllowedValues))
+		for _, value := range definition.AllowedValues {
+			value = strings.TrimSpace(value)
+			if value == "" || slices.Contains(values, value) {
+				return fmt.Errorf("%w: allowed values must be non-empty and unique", ErrInvalidAttributeDefinition)
+			}
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("%w: enum attributes need allowed values", ErrInvalidAttributeDefinition)
+		}
+		definition.AllowedValues = values
+	default:
+		return fmt.Errorf("%w: type must be text, number, boolean or enum", ErrInvalidAttributeDefinition)
+	}
+	if definition.Unit != "" && definition.Type != AttributeNumber {
+		return fmt.Errorf("%w: unit only applies to number attributes", ErrInvalidAttributeDefinition)
+	}
+	return nil
+}
+
+// validate confirma que o valor é do tipo da definição.
+func (d AttributeDefinition) validate(value any) error {
+	var valid bool
+	switch d.Type {
+	case AttributeText:
+		text, ok := value.(string)
+		valid = ok && strings.TrimSpace(text) != ""
+	case AttributeNumber:
+		switch value.(type) {
+		case float64, int, int64:
+			valid = true
+		}
+	case AttributeBoolean:
+		_, valid = value.(bool)
+	case AttributeEnum:
+		text, ok := value.(string)
+		valid = ok && slices.Contains(d.AllowedValues, text)
+	}
+	if !valid {
+		if d.Type == AttributeEnum {
+			return fmt.Errorf("%w: %s must be one of %s", ErrInvalidAttributes, d.Name, strings.Join(d.AllowedValues, ", "))
+		}
+		return fmt.Errorf("%w: %s must be a %s value", ErrInvalidAttributes, d.Name, d.Type)
+	}
+	return nil
+}
+
+// ValidateAttributes confirma os valores de um produto contra as definições
+// das suas categorias: todos os atributos têm de estar definidos, os
+// obrigatórios presentes e cada valor ser do tipo definido. Um atributo
+// definido em mais de uma categoria tem de respeitar todas as definições.
+func ValidateAttributes(schema []AttributeDefinition, values Attributes) error {
+	defined := make(map[string]bool, len(schema))
+	for _, definition := range schema {
+		defined[definition.Name] = true
+		value, ok := values[definition.Name]
+		if !ok {
+			if definition.Required {
+				return fmt.Errorf("%w: %s is required", ErrInvalidAttributes, definition.Name)
+			}
+			continue
+		}
+		if err := definition.validate(value); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !defined[name] {
+			return fmt.Errorf("%w: %s is not defined for the product's categories", ErrInvalidAttributes, name)
+		}
+	}
+	return nil
+}
+
+// NormalizeAttributeFilters normaliza os filtros de atributo de uma listagem:
+// nomes em minúsculas e valores sem espaços nas pontas.
+func NormalizeAttributeFilters(filters map[string][]string) (map[string][]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	normalized := make(map[string][]string, len(filters))
+	for name, values := range filters {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ValidSlug(name) {
+			return nil, fmt.Errorf("%w: attribute filter %q is not a valid attribute name", ErrInvalidFilter, name)
+		}
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return nil, fmt.Errorf("%w: attribute filter %q has an empty value", ErrInvalidFilter, name)
+			}
+			if !slices.Contains(normalized[name], value) {
+				normalized[name] = append(normalized[name], value)
+			}
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ProductType distingue os produtos com stock próprio dos kits, cujo stock
+// disponível é calculado a partir dos componentes.
+type ProductType string
+
+const (
+	ProductSimple ProductType = "simple"
+	ProductBundle ProductType = "bundle"
+)
+
+// BundleComponent é um produto que entra num kit com a quantidade indicada por
+// unidade do kit.
+type BundleComponent struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	SKU       string    `json:"sku,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	// AvailableStock é o stock disponível do componente, não o número de kits.
+	AvailableStock int `json:"available_stock"`
+}
+
+// ValidateBundleComponents confirma que cada componente aparece uma só vez,
+// com quantidade positiva, e que o kit não se inclui a si próprio.
+func ValidateBundleComponents(bundleID uuid.UUID, components []BundleComponent) error {
+	seen := make(map[uuid.UUID]bool, len(components))
+	for _, component := range components {
+		switch {
+		case component.ProductID == uuid.Nil:
+			return fmt.Errorf("%w: component product_id is required", ErrInvalidBundleComponents)
+		case component.ProductID == bundleID:
+			return fmt.Errorf("%w: a bundle cannot contain itself", ErrInvalidBundleComponents)
+		case component.Quantity < 1:
+			return fmt.Errorf("%w: quantity of %s must be at least 1", ErrInvalidBundleComponents, component.ProductID)
+		case seen[component.ProductID]:
+			return fmt.Errorf("%w: %s appears more than once", ErrInvalidBundleComponents, component.ProductID)
+		}
+		seen[component.ProductID] = true
+	}
+	return nil
+}
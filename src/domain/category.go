@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Category struct {
+	ID uuid.UUID `json:"id" db:"id"`
+	// ParentID é nil para as categorias de topo.
+	ParentID  *uuid.UUID `json:"parent_id" db:"parent_id"`
+	Name      string     `json:"name" db:"name"`
+	Slug      string     `json:"slug" db:"slug"`
+	Position  int        `json:"position" db:"position"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CategoryNode é uma categoria com os seus filhos, para construir a navegação.
+type CategoryNode struct {
+	*Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// BuildCategoryTree organiza uma lista de categorias ordenada por posição em
+// árvore, mantendo essa ordem entre irmãos.
+func BuildCategoryTree(categories []*Category) []*CategoryNode {
+	nodes := make(map[uuid.UUID]*CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{Category: category, Children: make([]*CategoryNode, 0)}
+	}
+
+	roots := make([]*CategoryNode, 0)
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+var (
+	slugPattern    = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+	accentReplacer = strings.NewReplacer("á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a", "é", "e", "è", "e", "ê", "e", "ë", "e",
+		"í", "i", "ì", "i", "î", "i", "ï", "i", "ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+		"ú", "u", "ù", "u", "û", "u", "ü", "u", "ç", "c", "ñ", "n")
+)
+
+// Slugify gera um slug a partir do nome (ex: "Decoração & Têxteis" → "decoracao-texteis").
+func Slugify(name string) string {
+	slug := accentReplacer.Replace(strings.ToLower(name))
+	return strings.Trim(slugSeparators.ReplaceAllString(slug, "-"), "-")
+}
+
+// ValidSlug indica se o slug só tem letras minúsculas sem acentos, dígitos e hífenes entre palavras.
+func ValidSlug(slug string) bool {
+	return len(slug) <= 255 && slugPattern.MatchString(slug)
+}
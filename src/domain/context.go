@@ -0,0 +1,30 @@
+package domain
+
+import "context"
+
+type contextKey string
+
+const (
+	UserIDContextKey        contextKey = "userID"
+	CorrelationIDContextKey contextKey = "correlationID"
+)
+
+// UserIDFromContext devolve o utilizador autenticado pelo JWTAuthMiddleware, ou "" para chamadas internas.
+func UserIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(UserIDContextKey).(string)
+	return userID
+}
+
+// PrincipalFromContext identifica quem faz o pedido: o utilizador autenticado
+// ou, nas chamadas com a chave interna, os serviços internos.
+func PrincipalFromContext(ctx context.Context) string {
+	if userID := UserIDFromContext(ctx); userID != "" {
+		return "user:" + userID
+	}
+	return "internal"
+}
+
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(CorrelationIDContextKey).(string)
+	return correlationID
+}
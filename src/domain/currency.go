@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"time"
+)
+
+// ExchangeRate converte preços de BaseCurrency para Currency: uma unidade de
+// BaseCurrency vale Rate unidades de Currency.
+type ExchangeRate struct {
+	BaseCurrency string    `json:"base_currency" db:"base_currency"`
+	Currency     string    `json:"currency" db:"currency"`
+	Rate         string    `json:"rate" db:"rate"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// maxRateDecimals acompanha a escala da coluna exchange_rates.rate.
+const maxRateDecimals = 10
+
+var ratePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,10})?$`)
+
+// ParseExchangeRate lê uma taxa decimal positiva como "5.4321".
+func ParseExchangeRate(value string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(value)
+	if !ratePattern.MatchString(value) || !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q must be a positive decimal with at most %d decimal places", ErrInvalidExchangeRate, value, maxRateDecimals)
+	}
+	return rate, nil
+}
+
+// CurrencyExponent devolve o número de casas decimais da moeda (2 para BRL, 0 para JPY).
+func CurrencyExponent(currency string) int {
+	return currencyMinorUnits[currency]
+}
+
+// Convert multiplica o valor por rate (unidades da moeda de destino por unidade
+// da moeda de origem) e arredonda às casas da moeda de destino, afastando as
+// metades de zero, tal como o ROUND do PostgreSQL.
+func (m Money) Convert(currency string, rate *big.Rat) (Money, error) {
+	scale := new(big.Rat).SetFrac(
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(CurrencyExponent(currency))), nil),
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(CurrencyExponent(m.Currency))), nil))
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(m.Minor), rate)
+	value.Mul(value, scale)
+
+	// Arredonda |value| somando metade e truncando, e repõe o sinal.
+	num := new(big.Int).Abs(value.Num())
+	den := value.Denom()
+	minor := new(big.Int).Quo(new(big.Int).Add(new(big.Int).Mul(num, big.NewInt(2)), den), new(big.Int).Mul(den, big.NewInt(2)))
+	if value.Sign() < 0 {
+		minor.Neg(minor)
+	}
+	if !minor.IsInt64() || minor.Int64() == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %s in %s is out of range", ErrInvalidMoney, m, currency)
+	}
+	return Money{Minor: minor.Int64(), Currency: currency}, nil
+}
+
+// rateBetween devolve a taxa que converte from em to.
+func rateBetween(from, to Money) *big.Rat {
+	return new(big.Rat).SetFrac(
+		new(big.Int).Mul(big.NewInt(to.Minor), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(CurrencyExponent(from.Currency))), nil)),
+		new(big.Int).Mul(big.NewInt(from.Minor), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(CurrencyExponent(to.Currency))), nil)))
+}
+
+// ConvertPrices mostra os preços do produto noutra moeda. Com um preço
+// explícito nessa moeda, o preço normal passa a ser esse e a promoção mantém a
+// mesma proporção do preço normal; sem ele, todos os preços são convertidos
+// pela taxa de câmbio. Sem preço explícito nem taxa, devolve ErrPriceNotAvailable.
+func (p *Product) ConvertPrices(currency string, explicit *Money, rate *big.Rat) error {
+	if p.Price.Currency == currency {
+		return nil
+	}
+	switch {
+	case explicit != nil && p.Price.IsPositive():
+		rate = rateBetween(p.Price, *explicit)
+	case rate == nil:
+		return fmt.Errorf("%w: product %s has no price in %s", ErrPriceNotAvailable, p.ID, currency)
+	}
+
+	price, err := p.Price.Convert(currency, rate)
+	if err != nil {
+		return err
+	}
+	effective, err := p.EffectivePrice.Convert(currency, rate)
+	if err != nil {
+		return err
+	}
+	if p.Sale != nil {
+		sale := *p.Sale
+		if sale.Price, err = sale.Price.Convert(currency, rate); err != nil {
+			return err
+		}
+		p.Sale = &sale
+	}
+	p.Price, p.EffectivePrice = price, effective
+	return nil
+}
@@ -0,0 +1,27 @@
+package domain
+
+// ProductFacets resume os produtos de uma listagem para os filtros da loja.
+// Cada faceta conta os produtos que respeitam todos os outros filtros, pelo
+// que escolher um valor não esconde as alternativas do mesmo atributo.
+type ProductFacets struct {
+	Attributes []AttributeFacet `json:"attributes"`
+	Prices     []PriceFacet     `json:"prices"`
+}
+
+// AttributeFacet conta os produtos por valor de um atributo, do mais frequente para o menos frequente.
+type AttributeFacet struct {
+	Name   string       `json:"name"`
+	Values []FacetValue `json:"values"`
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// PriceFacet conta os produtos com preço em [Min, Max).
+type PriceFacet struct {
+	Min   Money `json:"min"`
+	Max   Money `json:"max"`
+	Count int64 `json:"count"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord é o primeiro pedido feito por Principal com a chave Key.
+type IdempotencyRecord struct {
+	Principal    string     `json:"principal" db:"principal"`
+	Key          string     `json:"key" db:"key"`
+	RequestHash  string     `json:"request_hash" db:"request_hash"`
+	StatusCode   int        `json:"status_code" db:"status_code"`
+	ResponseBody []byte     `json:"response_body" db:"response_body"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at" db:"completed_at"`
+}
+
+// Completed indica se a resposta do primeiro pedido já foi guardada e pode ser repetida.
+func (r *IdempotencyRecord) Completed() bool {
+	return r.CompletedAt != nil
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var skuPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ValidSKU aceita até 64 letras, dígitos, pontos, hífenes e sublinhados, começando por letra ou dígito.
+func ValidSKU(sku string) bool {
+	return skuPattern.MatchString(sku)
+}
+
+// NormalizeBarcode valida um código UPC-A (12 dígitos), EAN-13 (13) ou
+// GTIN-14 (14), incluindo o dígito de controlo, e devolve-o sem espaços.
+func NormalizeBarcode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	switch len(code) {
+	case 12, 13, 14:
+	default:
+		return "", fmt.Errorf("%w: expected 12, 13 or 14 digits", ErrInvalidBarcode)
+	}
+	if strings.Trim(code, "0123456789") != "" {
+		return "", fmt.Errorf("%w: only digits are allowed", ErrInvalidBarcode)
+	}
+	if gtinCheckDigit(code[:len(code)-1]) != code[len(code)-1] {
+		return "", fmt.Errorf("%w: check digit mismatch", ErrInvalidBarcode)
+	}
+	return code, nil
+}
+
+// GTIN14 completa o código com zeros à esquerda; UPC-A, EAN-13 e GTIN-14 do
+// mesmo artigo dão o mesmo valor.
+func GTIN14(code string) string {
+	return strings.Repeat("0", max(0, 14-len(code))) + code
+}
+
+// gtinCheckDigit calcula o dígito de controlo GS1: da direita para a esquerda,
+// os dígitos são multiplicados alternadamente por 3 e por 1.
+func gtinCheckDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
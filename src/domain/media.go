@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ProductMedia é uma imagem da galeria de um produto. Position começa em 0 e
+// define a ordem da galeria; a primeira imagem é a principal.
+type ProductMedia struct {
+	ID          uuid.UUID `json:"id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	AltText     string    `json:"alt_text"`
+	Position    int       `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+	// StorageKey identifica o ficheiro no armazenamento e não é exposto.
+	StorageKey string `json:"-"`
+}
+
+// mediaExtensions são os tipos de imagem aceites e a extensão com que são guardados.
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+const maxAltTextLength = 255
+
+// MediaExtension devolve a extensão de um tipo de imagem aceite.
+func MediaExtension(contentType string) (string, error) {
+	extension, ok := mediaExtensions[contentType]
+	if !ok {
+		return "", fmt.Errorf("%w: %q, expected JPEG, PNG, WebP or GIF", ErrUnsupportedMediaType, contentType)
+	}
+	return extension, nil
+}
+
+// NormalizeAltText remove os espaços nas pontas do texto alternativo e limita o seu tamanho.
+func NormalizeAltText(altText string) (string, error) {
+	altText = strings.TrimSpace(altText)
+	if utf8.RuneCountInString(altText) > maxAltTextLength {
+		return "", fmt.Errorf("%w: alt text must have up to %d characters", ErrInvalidMedia, maxAltTextLength)
+	}
+	return altText, nil
+}
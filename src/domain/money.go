@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// currencyMinorUnits guarda, para cada moeda ISO 4217 suportada, o número de
+// casas decimais da sua unidade menor (ex: centavos).
+var currencyMinorUnits = map[string]int{
+	"ARS": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2,
+	"COP": 2, "EUR": 2, "GBP": 2, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "PYG": 0,
+	"USD": 2, "UYU": 2,
+}
+
+// ParseCurrency valida um código ISO 4217, aceitando minúsculas.
+func ParseCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := currencyMinorUnits[code]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+	return code, nil
+}
+
+// Money é um valor monetário exato, guardado como um inteiro de unidades
+// menores da moeda (ex: 1999 centavos para 19,99 BRL).
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// ParseMoney lê um valor decimal como "19.99" na moeda indicada. Casas
+// decimais além das da moeda só são aceites quando são zeros.
+func ParseMoney(amount, currency string) (Money, error) {
+	currency, err := ParseCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	exponent := currencyMinorUnits[currency]
+
+	amount = strings.TrimSpace(amount)
+	negative := strings.HasPrefix(amount, "-")
+	units, fraction, _ := strings.Cut(strings.TrimPrefix(amount, "-"), ".")
+	if units == "" || strings.Trim(units, "0123456789") != "" || strings.Trim(fraction, "0123456789") != "" {
+		return Money{}, fmt.Errorf("%w: %q is not a decimal amount", ErrInvalidMoney, amount)
+	}
+	if len(fraction) > exponent {
+		if strings.Trim(fraction[exponent:], "0") != "" {
+			return Money{}, fmt.Errorf("%w: %s allows at most %d decimal places", ErrInvalidMoney, currency, exponent)
+		}
+		fraction = fraction[:exponent]
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	minor, err := strconv.ParseInt(units+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q is out of range", ErrInvalidMoney, amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// Amount devolve o valor decimal com as casas da moeda (ex: "19.99").
+func (m Money) Amount() string {
+	exponent := currencyMinorUnits[m.Currency]
+	minor := m.Minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	if exponent == 0 {
+		return sign + strconv.FormatInt(minor, 10)
+	}
+
+	scale := int64(math.Pow10(exponent))
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/scale, exponent, minor%scale)
+}
+
+func (m Money) String() string {
+	return m.Amount() + " " + m.Currency
+}
+
+func (m Money) IsZero() bool {
+	return m.Minor == 0 && m.Currency == ""
+}
+
+func (m Money) IsPositive() bool {
+	return m.Minor > 0
+}
+
+// Compare devolve -1, 0 ou 1 como cmp.Compare; valores em moedas diferentes não são comparáveis.
+func (m Money) Compare(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	switch {
+	case m.Minor < other.Minor:
+		return -1, nil
+	case m.Minor > other.Minor:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Multiply devolve o valor multiplicado por uma quantidade (ex: o total de uma linha).
+func (m Money) Multiply(quantity int) (Money, error) {
+	if quantity < 0 {
+		return Money{}, fmt.Errorf("%w: %d", ErrInvalidQuantity, quantity)
+	}
+	if quantity > 0 && (m.Minor > math.MaxInt64/int64(quantity) || m.Minor < math.MinInt64/int64(quantity)) {
+		return Money{}, fmt.Errorf("%w: %s × %d is out of range", ErrInvalidMoney, m, quantity)
+	}
+	return Money{Minor: m.Minor * int64(quantity), Currency: m.Currency}, nil
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON serializa o valor como {"amount": "19.99", "currency": "BRL"};
+// o valor é uma string para que os clientes não o convertam em vírgula flutuante.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Amount(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: expected {\"amount\": \"19.99\", \"currency\": \"BRL\"}", ErrInvalidMoney)
+	}
+	parsed, err := ParseMoney(raw.Amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PriceChangeReason string
+
+const (
+	PriceChangeInitial        PriceChangeReason = "initial"
+	PriceChangeUpdate         PriceChangeReason = "update"
+	PriceChangeRevert         PriceChangeReason = "revert"
+	PriceChangeScheduleStart  PriceChangeReason = "schedule_start"
+	PriceChangeScheduleEnd    PriceChangeReason = "schedule_end"
+	PriceChangeScheduleCancel PriceChangeReason = "schedule_cancel"
+)
+
+// PriceChange é um registo imutável de uma alteração do preço de um produto.
+type PriceChange struct {
+	ID            uuid.UUID         `json:"id" db:"id"`
+	ProductID     uuid.UUID         `json:"product_id" db:"product_id"`
+	Price         Money             `json:"price" db:"price"`
+	PreviousPrice *Money            `json:"previous_price,omitempty" db:"previous_price"`
+	Reason        PriceChangeReason `json:"reason" db:"reason"`
+	ScheduleID    *uuid.UUID        `json:"schedule_id,omitempty" db:"schedule_id"`
+	ActorID       string            `json:"actor_id,omitempty" db:"actor_id"`
+	CorrelationID string            `json:"correlation_id,omitempty" db:"correlation_id"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+}
+
+type PriceChangePage struct {
+	Items      []*PriceChange `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type PriceScheduleStatus string
+
+const (
+	// Um agendamento pending aguarda effective_from; active já foi aplicado e aguarda effective_to.
+	SchedulePending   PriceScheduleStatus = "pending"
+	ScheduleActive    PriceScheduleStatus = "active"
+	ScheduleCompleted PriceScheduleStatus = "completed"
+	ScheduleCancelled PriceScheduleStatus = "cancelled"
+)
+
+// PriceSchedule é uma mudança de preço agendada. Entre EffectiveFrom e
+// EffectiveTo o produto é vendido a Price; no fim o preço anterior é reposto.
+// Sem EffectiveTo, a mudança é permanente.
+type PriceSchedule struct {
+	ID            uuid.UUID           `json:"id" db:"id"`
+	ProductID     uuid.UUID           `json:"product_id" db:"product_id"`
+	Price         Money               `json:"price" db:"price"`
+	EffectiveFrom time.Time           `json:"effective_from" db:"effective_from"`
+	EffectiveTo   *time.Time          `json:"effective_to,omitempty" db:"effective_to"`
+	Status        PriceScheduleStatus `json:"status" db:"status"`
+	// PreviousPrice é o preço substituído quando o agendamento foi aplicado.
+	PreviousPrice *Money    `json:"previous_price,omitempty" db:"previous_price"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
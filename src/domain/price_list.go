@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceList agrupa os preços negociados com um grupo de clientes (ex: "grossista").
+type PriceList struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	Name          string    `json:"name" db:"name"`
+	CustomerGroup string    `json:"customer_group" db:"customer_group"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PriceTier é o preço unitário de um produto a partir de uma quantidade mínima.
+type PriceTier struct {
+	MinQuantity int   `json:"min_quantity" db:"min_quantity"`
+	UnitPrice   Money `json:"unit_price" db:"price"`
+}
+
+type PriceQuoteSource string
+
+const (
+	QuoteSourceRegular   PriceQuoteSource = "regular"
+	QuoteSourceSale      PriceQuoteSource = "sale"
+	QuoteSourcePriceList PriceQuoteSource = "price_list"
+)
+
+// PriceQuote é o preço de uma quantidade de um produto para um grupo de clientes.
+type PriceQuote struct {
+	ProductID     uuid.UUID        `json:"product_id"`
+	CustomerGroup string           `json:"customer_group,omitempty"`
+	Quantity      int              `json:"quantity"`
+	UnitPrice     Money            `json:"unit_price"`
+	LinePrice     Money            `json:"line_price"`
+	Source        PriceQuoteSource `json:"source"`
+	// PriceListID e MinQuantity identificam o escalão aplicado quando Source é price_list.
+	PriceListID *uuid.UUID `json:"price_list_id,omitempty"`
+	MinQuantity int        `json:"min_quantity,omitempty"`
+}
@@ -1,17 +1,58 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Product struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	Price       float64   `json:"price" db:"price"`
-	Stock       int       `json:"stock" db:"stock"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID  uuid.UUID `json:"id" db:"id"`
+	SKU string    `json:"sku" db:"sku"`
+	// Barcode é um UPC-A, EAN-13 ou GTIN-14 opcional.
+	Barcode     string `json:"barcode,omitempty" db:"barcode"`
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+	// Attributes são validados contra as definições das categorias do produto.
+	Attributes Attributes `json:"attributes" db:"attributes"`
+	// Media é a galeria de imagens por ordem, carregada só nas leituras.
+	Media []ProductMedia `json:"media,omitempty"`
+	// Status começa em draft; só os produtos ativos são públicos.
+	Status ProductStatus `json:"status" db:"status"`
+	// Type é simple ou bundle; o stock de um kit vem dos componentes.
+	Type ProductType `json:"type" db:"type"`
+	// Price é o preço normal; EffectivePrice é o preço de venda atual, que é o
+	// de Sale enquanto a promoção estiver em vigor.
+	Price          Money `json:"price" db:"price"`
+	Sale           *Sale `json:"sale,omitempty"`
+	EffectivePrice Money `json:"effective_price"`
+	OnSale         bool  `json:"on_sale"`
+	Stock          int   `json:"stock" db:"stock"`
+	// AvailableStock é o stock em mão menos as reservas ativas. Num kit é o
+	// número de unidades que o stock disponível dos componentes permite montar.
+	AvailableStock int       `json:"available_stock" db:"available_stock"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	// Version é incrementada a cada escrita e exposta como ETag.
+	Version int64 `json:"version" db:"version"`
+	// DeletedAt só está preenchido nos produtos removidos que aguardam a purga.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Variant só está preenchida quando o produto é procurado pelo SKU de uma das suas variantes.
+	Variant *Variant `json:"variant,omitempty"`
+}
+
+// VersionConflictError indica que o produto mudou desde a versão lida pelo cliente.
+type VersionConflictError struct {
+	ProductID uuid.UUID
+	Expected  int64
+	Current   int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s (expected version %d, current version %d)", ErrVersionConflict, e.Expected, e.Current)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
 }
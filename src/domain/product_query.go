@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProductSortField string
+
+const (
+	SortByCreatedAt ProductSortField = "created_at"
+	SortByUpdatedAt ProductSortField = "updated_at"
+	SortByPrice     ProductSortField = "price"
+	SortByName      ProductSortField = "name"
+)
+
+func ParseProductSortField(value string) (ProductSortField, error) {
+	switch field := ProductSortField(value); field {
+	case "":
+		return SortByCreatedAt, nil
+	case SortByCreatedAt, SortByUpdatedAt, SortByPrice, SortByName:
+		return field, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidSortField, value)
+	}
+}
+
+// ProductQuery descreve uma página da listagem de produtos. Os filtros a nil
+// ou vazios são ignorados.
+type ProductQuery struct {
+	Limit        int
+	Cursor       string
+	MinPrice     *Money
+	MaxPrice     *Money
+	InStockOnly  bool
+	CreatedAfter *time.Time
+	UpdatedAfter *time.Time
+	NameContains string
+	// CategoryID limita a listagem à categoria e a todas as suas subcategorias.
+	CategoryID *uuid.UUID
+	// Status limita a listagem a um estado; vazio lista todos.
+	Status ProductStatus
+	// Deleted lista apenas os produtos removidos, em vez de os excluir.
+	Deleted bool
+	// OnSaleOnly lista apenas os produtos com uma promoção em vigor.
+	OnSaleOnly bool
+	// Attributes filtra pelo valor dos atributos: um produto tem de ter um dos
+	// valores indicados para cada atributo.
+	Attributes map[string][]string
+	// Currency mostra os preços nessa moeda e aplica-lhe o intervalo de preço e a
+	// ordenação por preço, excluindo os produtos sem preço nela. Vazio mantém a moeda de cada produto.
+	Currency     string
+	SortBy       ProductSortField
+	Descending   bool
+	IncludeTotal bool
+	// IncludeFacets calcula as contagens por valor de atributo e por faixa de
+	// preço, com PriceInterval unidades da moeda em cada faixa.
+	IncludeFacets bool
+	PriceInterval int
+}
+
+type ProductPage struct {
+	Items      []*Product `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	// Total só é calculado quando pedido, por ser uma contagem de toda a tabela filtrada.
+	Total *int64 `json:"total,omitempty"`
+	// Facets só é calculado quando pedido.
+	Facets *ProductFacets `json:"facets,omitempty"`
+}
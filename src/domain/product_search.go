@@ -0,0 +1,45 @@
+package domain
+
+import "fmt"
+
+// SearchLanguage é a configuração de texto do Postgres usada para extrair os radicais da pesquisa.
+type SearchLanguage string
+
+const (
+	SearchPortuguese SearchLanguage = "portuguese"
+	SearchEnglish    SearchLanguage = "english"
+)
+
+// ParseSearchLanguage aceita o código ISO 639-1 ou o nome da configuração; por omissão usa português.
+func ParseSearchLanguage(value string) (SearchLanguage, error) {
+	switch value {
+	case "", "pt", string(SearchPortuguese):
+		return SearchPortuguese, nil
+	case "en", string(SearchEnglish):
+		return SearchEnglish, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported language %q", ErrInvalidSearchQuery, value)
+	}
+}
+
+type ProductSearchQuery struct {
+	Text     string
+	Language SearchLanguage
+	Limit    int
+	Cursor   string
+}
+
+// ProductSearchResult é um produto encontrado, com a relevância e os excertos
+// onde os termos pesquisados aparecem entre <mark></mark>. O restante texto
+// dos excertos vem escapado para HTML.
+type ProductSearchResult struct {
+	*Product
+	Rank               float32 `json:"rank"`
+	NameHighlight      string  `json:"name_highlight"`
+	DescriptionSnippet string  `json:"description_snippet"`
+}
+
+type ProductSearchPage struct {
+	Items      []*ProductSearchResult `json:"items"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ProductStatus é a fase do ciclo de vida do produto. Só os produtos ativos
+// aparecem nas rotas públicas.
+type ProductStatus string
+
+const (
+	StatusDraft    ProductStatus = "draft"
+	StatusActive   ProductStatus = "active"
+	StatusArchived ProductStatus = "archived"
+)
+
+// productStatusTransitions lista, para cada estado, os estados para onde o produto pode passar.
+var productStatusTransitions = map[ProductStatus][]ProductStatus{
+	StatusDraft:    {StatusActive},
+	StatusActive:   {StatusArchived},
+	StatusArchived: {StatusActive},
+}
+
+func ParseProductStatus(value string) (ProductStatus, error) {
+	switch status := ProductStatus(value); status {
+	case StatusDraft, StatusActive, StatusArchived:
+		return status, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidProductStatus, value)
+}
+
+// CanTransitionTo indica se o produto pode passar do estado atual para next.
+func (s ProductStatus) CanTransitionTo(next ProductStatus) bool {
+	return slices.Contains(productStatusTransitions[s], next)
+}
+
+// StatusTransitionError indica que a mudança de estado pedida não é permitida a partir do estado atual.
+type StatusTransitionError struct {
+	From ProductStatus
+	To   ProductStatus
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("%s (from %s to %s)", ErrInvalidStatusTransition, e.From, e.To)
+}
+
+func (e *StatusTransitionError) Unwrap() error {
+	return ErrInvalidStatusTransition
+}
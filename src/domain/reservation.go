@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationActive    ReservationStatus = "active"
+	ReservationCommitted ReservationStatus = "committed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+type Reservation struct {
+	ID             uuid.UUID         `json:"id" db:"id"`
+	ProductID      uuid.UUID         `json:"product_id" db:"product_id"`
+	OrderReference string            `json:"order_reference" db:"order_reference"`
+	Quantity       int               `json:"quantity" db:"quantity"`
+	Status         ReservationStatus `json:"status" db:"status"`
+	ExpiresAt      time.Time         `json:"expires_at" db:"expires_at"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevisionAction string
+
+const (
+	RevisionCreate  RevisionAction = "create"
+	RevisionUpdate  RevisionAction = "update"
+	RevisionDelete  RevisionAction = "delete"
+	RevisionRestore RevisionAction = "restore"
+	RevisionRevert  RevisionAction = "revert"
+)
+
+// ProductRevision é uma cópia completa do produto tal como ficou depois de uma escrita.
+// Revision é sequencial por produto, a começar em 1.
+type ProductRevision struct {
+	ID            uuid.UUID      `json:"id" db:"id"`
+	ProductID     uuid.UUID      `json:"product_id" db:"product_id"`
+	Revision      int            `json:"revision" db:"revision"`
+	Action        RevisionAction `json:"action" db:"action"`
+	Snapshot      Product        `json:"snapshot" db:"snapshot"`
+	ActorID       string         `json:"actor_id,omitempty" db:"actor_id"`
+	CorrelationID string         `json:"correlation_id,omitempty" db:"correlation_id"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+}
+
+// FieldChange é um campo do produto com valores diferentes entre duas revisões.
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+type RevisionDiff struct {
+	ProductID uuid.UUID     `json:"product_id"`
+	From      int           `json:"from"`
+	To        int           `json:"to"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// revisionIgnoredFields mudam em todas as escritas ou são calculados, pelo que
+// não são mostrados como diferenças.
+var revisionIgnoredFields = map[string]bool{"updated_at": true, "version": true, "available_stock": true}
+
+// DiffProducts compara dois produtos campo a campo, pelos nomes e valores JSON,
+// e devolve as diferenças ordenadas pelo nome do campo.
+func DiffProducts(from, to *Product) ([]FieldChange, error) {
+	before, err := productFields(from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := productFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := make([]FieldChange, 0)
+	for _, field := range fields {
+		if revisionIgnoredFields[field] {
+			continue
+		}
+		// Campos omitidos (ex: barcode vazio) aparecem como null.
+		old, ok := before[field]
+		if !ok {
+			old = json.RawMessage("null")
+		}
+		current, ok := after[field]
+		if !ok {
+			current = json.RawMessage("null")
+		}
+		if !bytes.Equal(old, current) {
+			changes = append(changes, FieldChange{Field: field, From: old, To: current})
+		}
+	}
+	return changes, nil
+}
+
+func productFields(product *Product) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(product)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// Sale é um preço promocional, na moeda do produto, válido entre StartsAt e
+// EndsAt. Sem datas, a promoção vale até ser removida.
+type Sale struct {
+	Price    Money      `json:"price"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// StockItem é uma linha de um pedido: um produto e a quantidade a movimentar.
+// Sem WarehouseID o stock é alocado pela estratégia configurada.
+type StockItem struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Quantity    int       `json:"quantity"`
+}
+
+// StockLineError descreve porque uma linha de uma redução em lote foi rejeitada.
+type StockLineError struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Requested int       `json:"requested"`
+	Available int       `json:"available"`
+	Code      string    `json:"code"`
+	Message   string    `json:"message"`
+}
+
+// InsufficientStockError indica que o produto existe mas não tem stock
+// disponível suficiente para a quantidade pedida.
+type InsufficientStockError struct {
+	ProductID uuid.UUID
+	Requested int
+	Available int
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("%s (requested %d, available %d)", ErrInsufficientStock, e.Requested, e.Available)
+}
+
+func (e *InsufficientStockError) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+// BatchStockError é devolvido quando pelo menos uma linha de uma redução em lote
+// falha; nenhuma linha é aplicada nesse caso.
+type BatchStockError struct {
+	Lines []StockLineError
+}
+
+func (e *BatchStockError) Error() string {
+	return fmt.Sprintf("%s: %d line(s) rejected", ErrStockBatchRejected, len(e.Lines))
+}
+
+func (e *BatchStockError) Unwrap() error {
+	return ErrStockBatchRejected
+}
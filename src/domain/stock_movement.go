@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MovementReason string
+
+const (
+	MovementCreate            MovementReason = "create"
+	MovementUpdate            MovementReason = "update"
+	MovementReduce            MovementReason = "reduce"
+	MovementRestock           MovementReason = "restock"
+	MovementAdjustment        MovementReason = "adjustment"
+	MovementReservationCommit MovementReason = "reservation_commit"
+	MovementTransferOut       MovementReason = "transfer_out"
+	MovementTransferIn        MovementReason = "transfer_in"
+)
+
+// StockMovement é um registo imutável de uma alteração de stock.
+type StockMovement struct {
+	ID            uuid.UUID      `json:"id" db:"id"`
+	ProductID     uuid.UUID      `json:"product_id" db:"product_id"`
+	WarehouseID   *uuid.UUID     `json:"warehouse_id,omitempty" db:"warehouse_id"`
+	VariantID     *uuid.UUID     `json:"variant_id,omitempty" db:"variant_id"`
+	Delta         int            `json:"delta" db:"delta"`
+	Balance       int            `json:"balance" db:"balance"`
+	Reason        MovementReason `json:"reason" db:"reason"`
+	Reference     string         `json:"reference,omitempty" db:"reference"`
+	ActorID       string         `json:"actor_id,omitempty" db:"actor_id"`
+	CorrelationID string         `json:"correlation_id,omitempty" db:"correlation_id"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+}
+
+type StockMovementPage struct {
+	Items      []*StockMovement `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// NewStockMovement cria um movimento preenchendo o autor e o correlation ID a partir do contexto do pedido.
+func NewStockMovement(ctx context.Context, productID uuid.UUID, delta, balance int, reason MovementReason, reference string) *StockMovement {
+	return &StockMovement{
+		ID:            uuid.New(),
+		ProductID:     productID,
+		Delta:         delta,
+		Balance:       balance,
+		Reason:        reason,
+		Reference:     reference,
+		ActorID:       UserIDFromContext(ctx),
+		CorrelationID: CorrelationIDFromContext(ctx),
+		CreatedAt:     time.Now().UTC(),
+	}
+}
@@ -16,6 +16,118 @@ var (
 	ErrToReduceStock         = errors.New("failed to reduce stock")
 	ErrToUpdateProduct       = errors.New("failed to update product")
 	ErrToDeletegProduct      = errors.New("failed to delete product")
+	ErrToRestoreProduct      = errors.New("failed to restore product")
 	ErrScanningRows          = errors.New("failed to scan rows")
 	ErrFailedToUnmarshalJSON = errors.New("failed to unmarshal JSON")
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrInvalidOrderReference = errors.New("invalid order reference")
+	ErrReservationNotFound   = errors.New("reservation not found")
+	ErrReservationNotActive  = errors.New("reservation is not active")
+	ErrReservationExpired    = errors.New("reservation expired")
+	ErrToReserveStock        = errors.New("failed to reserve stock")
+	ErrEmptyStockBatch       = errors.New("empty stock batch")
+	ErrStockBatchRejected    = errors.New("stock batch rejected")
+	ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyReused  = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still in progress")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrToListStockMovements  = errors.New("failed to list stock movements")
+	ErrAdjustmentReason      = errors.New("adjustment reason is required")
+	ErrReferenceTooLong      = errors.New("stock movement reference is too long")
+	ErrToAdjustStock         = errors.New("failed to adjust stock")
+
+	ErrWarehouseNotFound         = errors.New("warehouse not found")
+	ErrWarehouseInactive         = errors.New("warehouse is inactive")
+	ErrWarehouseCodeTaken        = errors.New("warehouse code already in use")
+	ErrInvalidAllocationStrategy = errors.New("invalid allocation strategy")
+	ErrSameWarehouseTransfer     = errors.New("source and destination warehouses must differ")
+	ErrStockBelowAllocated       = errors.New("stock cannot be lower than the quantity allocated to warehouses")
+	ErrToSaveWarehouse           = errors.New("failed to save warehouse")
+	ErrToTransferStock           = errors.New("failed to transfer stock")
+
+	ErrVersionConflict      = errors.New("product was modified by another request")
+	ErrPreconditionRequired = errors.New("If-Match header with the product version is required")
+	ErrInvalidVersion       = errors.New("invalid product version")
+
+	ErrInvalidSortField = errors.New("invalid sort field")
+	ErrInvalidFilter    = errors.New("invalid filter")
+
+	ErrInvalidSearchQuery = errors.New("invalid search query")
+	ErrToSearchProducts   = errors.New("failed to search products")
+
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrInvalidSlug         = errors.New("invalid slug")
+	ErrCategorySlugTaken   = errors.New("category slug already in use")
+	ErrCategoryCycle       = errors.New("a category cannot be moved below itself or one of its descendants")
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+	ErrToSaveCategory      = errors.New("failed to save category")
+	ErrToAssignCategories  = errors.New("failed to assign product categories")
+
+	ErrInvalidMoney     = errors.New("invalid money amount")
+	ErrInvalidCurrency  = errors.New("invalid currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+
+	ErrVariantNotFound       = errors.New("variant not found")
+	ErrInvalidSKU            = errors.New("invalid SKU")
+	ErrSKUTaken              = errors.New("SKU already in use")
+	ErrInvalidVariantOptions = errors.New("invalid variant options")
+	ErrVariantOptionsTaken   = errors.New("a variant with these options already exists")
+	ErrProductHasVariants    = errors.New("product options cannot change while the product has variants")
+	ErrVariantWarehouseStock = errors.New("variant stock is not tracked per warehouse")
+	ErrToSaveVariant         = errors.New("failed to save variant")
+	ErrToSaveProductOptions  = errors.New("failed to save product options")
+
+	ErrInvalidBarcode = errors.New("invalid barcode")
+	ErrBarcodeTaken   = errors.New("barcode already in use")
+
+	ErrInvalidProductStatus    = errors.New("invalid product status")
+	ErrInvalidStatusTransition = errors.New("product status transition not allowed")
+	ErrProductArchived         = errors.New("product is archived")
+	ErrToChangeProductStatus   = errors.New("failed to change product status")
+
+	ErrRevisionNotFound = errors.New("revision not found")
+	ErrInvalidRevision  = errors.New("invalid revision")
+	ErrToListRevisions  = errors.New("failed to list product revisions")
+	ErrToRevertProduct  = errors.New("failed to revert product")
+
+	ErrPriceScheduleNotFound  = errors.New("price schedule not found")
+	ErrInvalidSalePrice       = errors.New("invalid sale price")
+	ErrToSetSale              = errors.New("failed to set sale price")
+	ErrInvalidPriceSchedule   = errors.New("invalid price schedule")
+	ErrPriceScheduleOverlap   = errors.New("price schedule overlaps another schedule of the product")
+	ErrPriceScheduleClosed    = errors.New("price schedule is already completed or cancelled")
+	ErrToSavePriceSchedule    = errors.New("failed to save price schedule")
+	ErrToListPriceHistory     = errors.New("failed to list price history")
+	ErrToApplyScheduledPrices = errors.New("failed to apply scheduled prices")
+
+	ErrPriceListNotFound    = errors.New("price list not found")
+	ErrInvalidCustomerGroup = errors.New("invalid customer group")
+	ErrPriceListGroupTaken  = errors.New("customer group already has a price list")
+	ErrInvalidPriceTier     = errors.New("invalid price tier")
+	ErrToSavePriceList      = errors.New("failed to save price list")
+	ErrToQuotePrice         = errors.New("failed to quote price")
+
+	ErrInvalidExchangeRate  = errors.New("invalid exchange rate")
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+	ErrProductPriceNotFound = errors.New("product has no explicit price in this currency")
+	ErrPriceNotAvailable    = errors.New("price not available in the requested currency")
+	ErrToSaveExchangeRate   = errors.New("failed to save exchange rate")
+	ErrToSaveProductPrice   = errors.New("failed to save product price")
+	ErrToConvertPrices      = errors.New("failed to convert prices")
+
+	ErrInvalidBundleComponents = errors.New("invalid bundle components")
+	ErrBundleStock             = errors.New("bundle stock is derived from its components")
+	ErrProductInBundle         = errors.New("product is a component of a bundle")
+	ErrToSaveBundle            = errors.New("failed to save bundle components")
+
+	ErrAttributeNotFound          = errors.New("attribute definition not found")
+	ErrInvalidAttributeDefinition = errors.New("invalid attribute definition")
+	ErrInvalidAttributes          = errors.New("invalid product attributes")
+	ErrToSaveAttribute            = errors.New("failed to save attribute definition")
+
+	ErrMediaNotFound        = errors.New("media not found")
+	ErrInvalidMedia         = errors.New("invalid media")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrMediaTooLarge        = errors.New("media file too large")
+	ErrToSaveMedia          = errors.New("failed to save media")
 )
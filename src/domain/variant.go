@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductOption é uma dimensão de variação de um produto (ex: tamanho) com os
+// valores permitidos pela ordem em que devem ser apresentados.
+type ProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// Variant é uma combinação concreta das opções do produto, com SKU e stock
+// próprios. Sem Price, a variante é vendida ao preço do produto.
+type Variant struct {
+	ID        uuid.UUID         `json:"id" db:"id"`
+	ProductID uuid.UUID         `json:"product_id" db:"product_id"`
+	SKU       string            `json:"sku" db:"sku"`
+	Options   map[string]string `json:"options" db:"options"`
+	Price     *Money            `json:"price,omitempty" db:"price"`
+	// EffectivePrice é o preço da variante ou, na sua ausência, o do produto.
+	EffectivePrice Money     `json:"effective_price"`
+	Stock          int       `json:"stock" db:"stock"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NormalizeProductOptions limpa os espaços dos nomes e valores e exige nomes
+// e valores não vazios e sem repetições.
+func NormalizeProductOptions(options []ProductOption) ([]ProductOption, error) {
+	normalized := make([]ProductOption, 0, len(options))
+	names := make(map[string]bool, len(options))
+	for _, option := range options {
+		name := strings.TrimSpace(option.Name)
+		if name == "" || names[name] {
+			return nil, fmt.Errorf("%w: option names must be unique and not empty", ErrInvalidVariantOptions)
+		}
+		names[name] = true
+
+		if len(option.Values) == 0 {
+			return nil, fmt.Errorf("%w: option %q has no values", ErrInvalidVariantOptions, name)
+		}
+		values := make([]string, 0, len(option.Values))
+		for _, value := range option.Values {
+			value = strings.TrimSpace(value)
+			if value == "" || slices.Contains(values, value) {
+				return nil, fmt.Errorf("%w: values of option %q must be unique and not empty", ErrInvalidVariantOptions, name)
+			}
+			values = append(values, value)
+		}
+		normalized = append(normalized, ProductOption{Name: name, Values: values})
+	}
+	return normalized, nil
+}
+
+// MatchProductOptions confirma que a seleção de uma variante indica exatamente
+// um valor permitido para cada opção do produto.
+func MatchProductOptions(options []ProductOption, selection map[string]string) error {
+	if len(options) == 0 {
+		return fmt.Errorf("%w: product has no options", ErrInvalidVariantOptions)
+	}
+	if len(selection) != len(options) {
+		return fmt.Errorf("%w: expected a value for each of the %d product options", ErrInvalidVariantOptions, len(options))
+	}
+	for _, option := range options {
+		value, ok := selection[option.Name]
+		if !ok {
+			return fmt.Errorf("%w: missing value for option %q", ErrInvalidVariantOptions, option.Name)
+		}
+		if !slices.Contains(option.Values, value) {
+			return fmt.Errorf("%w: %q is not a value of option %q", ErrInvalidVariantOptions, value, option.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Warehouse struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Code      string    `json:"code" db:"code"`
+	Name      string    `json:"name" db:"name"`
+	Priority  int       `json:"priority" db:"priority"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// LocationStock é o stock de um produto num armazém.
+type LocationStock struct {
+	WarehouseID uuid.UUID `json:"warehouse_id" db:"warehouse_id"`
+	Code        string    `json:"code" db:"code"`
+	Name        string    `json:"name" db:"name"`
+	Quantity    int       `json:"quantity" db:"quantity"`
+}
+
+// ProductInventory detalha o stock total de um produto pelos seus locais.
+type ProductInventory struct {
+	ProductID      uuid.UUID        `json:"product_id"`
+	Stock          int              `json:"stock"`
+	AvailableStock int              `json:"available_stock"`
+	Unassigned     int              `json:"unassigned"`
+	Locations      []*LocationStock `json:"locations"`
+}
+
+type StockTransfer struct {
+	ID              uuid.UUID `json:"id"`
+	ProductID       uuid.UUID `json:"product_id"`
+	FromWarehouseID uuid.UUID `json:"from_warehouse_id"`
+	ToWarehouseID   uuid.UUID `json:"to_warehouse_id"`
+	Quantity        int       `json:"quantity"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AllocationStrategy define de que armazéns sai o stock quando uma redução não indica o local.
+type AllocationStrategy string
+
+const (
+	AllocationPriority  AllocationStrategy = "priority"
+	AllocationMostStock AllocationStrategy = "most_stock"
+)
+
+func ParseAllocationStrategy(value string) (AllocationStrategy, error) {
+	switch strategy := AllocationStrategy(value); strategy {
+	case AllocationPriority, AllocationMostStock:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidAllocationStrategy, value)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AttributeRepository interface {
+	// SetDefinition cria ou substitui a definição do atributo com o mesmo nome na categoria.
+	SetDefinition(ctx context.Context, definition *domain.AttributeDefinition) error
+	DeleteDefinition(ctx context.Context, categoryID uuid.UUID, name string) error
+	// ListDefinitions devolve as definições da categoria, incluindo as herdadas das categorias acima.
+	ListDefinitions(ctx context.Context, categoryID uuid.UUID) ([]domain.AttributeDefinition, error)
+	// ProductSchema devolve as definições que se aplicam ao produto pelas categorias que tem atribuídas.
+	ProductSchema(ctx context.Context, productID uuid.UUID) ([]domain.AttributeDefinition, error)
+}
+
+type postgresAttributeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAttribute(db *pgxpool.Pool) AttributeRepository {
+	return &postgresAttributeRepository{db: db}
+}
+
+// schemaQuery lê as definições das categorias devolvidas por seed e de todas
+// as categorias acima delas.
+func schemaQuery(seed string) string {
+	return `WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM categories WHERE id IN (` + seed + `)
+			UNION
+			SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT ca.category_id, ca.name, ca.type, ca.unit, ca.allowed_values, ca.required, ca.updated_at
+		FROM category_attributes ca WHERE ca.category_id IN (SELECT id FROM ancestors) ORDER BY ca.name, ca.category_id`
+}
+
+func collectDefinitions(rows pgx.Rows) ([]domain.AttributeDefinition, error) {
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[domain.AttributeDefinition])
+}
+
+func (r *postgresAttributeRepository) SetDefinition(ctx context.Context, definition *domain.AttributeDefinition) error {
+
+	query := `INSERT INTO category_attributes (category_id, name, type, unit, allowed_values, required, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (category_id, name) DO UPDATE SET type = EXCLUDED.type, unit = EXCLUDED.unit, allowed_values = EXCLUDED.allowed_values,
+			required = EXCLUDED.required, updated_at = EXCLUDED.updated_at`
+	allowedValues := definition.AllowedValues
+	if allowedValues == nil {
+		allowedValues = []string{}
+	}
+	_, err := r.db.Exec(ctx, query, definition.CategoryID, definition.Name, definition.Type, definition.Unit, allowedValues, definition.Required, definition.UpdatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("Error when setting attribute definition: %w", domain.ErrCategoryNotFound)
+		}
+		return fmt.Errorf("Error when setting attribute definition: %w", domain.ErrToSaveAttribute)
+	}
+	return nil
+}
+
+func (r *postgresAttributeRepository) DeleteDefinition(ctx context.Context, categoryID uuid.UUID, name string) error {
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM category_attributes WHERE category_id = $1 AND name = $2`, categoryID, name)
+	if err != nil {
+		return fmt.Errorf("Error when deleting attribute definition: %w", domain.ErrToSaveAttribute)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Error when deleting attribute definition: %w", domain.ErrAttributeNotFound)
+	}
+	return nil
+}
+
+func (r *postgresAttributeRepository) ListDefinitions(ctx context.Context, categoryID uuid.UUID) ([]domain.AttributeDefinition, error) {
+
+	var id uuid.UUID
+	if err := r.db.QueryRow(ctx, `SELECT id FROM categories WHERE id = $1`, categoryID).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when listing attribute definitions: %w", domain.ErrCategoryNotFound)
+		}
+		return nil, fmt.Errorf("Error when listing attribute definitions: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, schemaQuery(`$1`), categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing attribute definitions: %w", err)
+	}
+	definitions, err := collectDefinitions(rows)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing attribute definitions: %w", err)
+	}
+	return definitions, nil
+}
+
+// productSchema lê as definições que se aplicam ao produto pelas categorias que tem atribuídas.
+func productSchema(ctx context.Context, db dbtx, productID uuid.UUID) ([]domain.AttributeDefinition, error) {
+	rows, err := db.Query(ctx, schemaQuery(`SELECT category_id FROM product_categories WHERE product_id = $1`), productID)
+	if err != nil {
+		return nil, err
+	}
+	return collectDefinitions(rows)
+}
+
+// validateProductAttributes confirma os atributos contra as categorias do
+// produto na transação do chamador, que já bloqueou ou criou o produto: uma
+// atribuição de categorias e uma gravação de atributos não se podem cruzar.
+func validateProductAttributes(ctx context.Context, tx dbtx, productID uuid.UUID, attributes domain.Attributes) error {
+	schema, err := productSchema(ctx, tx, productID)
+	if err != nil {
+		return err
+	}
+	return domain.ValidateAttributes(schema, attributes)
+}
+
+func (r *postgresAttributeRepository) ProductSchema(ctx context.Context, productID uuid.UUID) ([]domain.AttributeDefinition, error) {
+
+	definitions, err := productSchema(ctx, r.db, productID)
+	if err != nil {
+		return nil, fmt.Errorf("Error when loading attribute schema: %w", err)
+	}
+	return definitions, nil
+}
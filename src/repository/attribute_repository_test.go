@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Attributes", func() {
+	var productRepo ProductRepository
+	var categoryRepo CategoryRepository
+	var attributeRepo AttributeRepository
+	var ctx context.Context
+	var parent, child *domain.Category
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		categoryRepo = NewCategory(db)
+		attributeRepo = NewAttribute(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products, categories RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+
+		now := time.Now().UTC()
+		parent = &domain.Category{ID: uuid.New(), Name: "Eletrodomésticos", Slug: "eletrodomesticos", CreatedAt: now, UpdatedAt: now}
+		child = &domain.Category{ID: uuid.New(), ParentID: &parent.ID, Name: "Chaleiras", Slug: "chaleiras", CreatedAt: now, UpdatedAt: now}
+		Expect(categoryRepo.Create(ctx, parent)).To(Succeed())
+		Expect(categoryRepo.Create(ctx, child)).To(Succeed())
+		Expect(attributeRepo.SetDefinition(ctx, &domain.AttributeDefinition{CategoryID: parent.ID, Name: "voltage", Type: domain.AttributeNumber, Unit: "V", Required: true, UpdatedAt: now})).To(Succeed())
+		Expect(attributeRepo.SetDefinition(ctx, &domain.AttributeDefinition{CategoryID: child.ID, Name: "material", Type: domain.AttributeEnum, AllowedValues: []string{"inox", "vidro"}, UpdatedAt: now})).To(Succeed())
+	})
+
+	It("should include the definitions inherited from parent categories", func() {
+		definitions, err := attributeRepo.ListDefinitions(ctx, child.ID)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(definitions).To(HaveLen(2))
+		Expect(definitions[0].Name).To(Equal("material"))
+		Expect(definitions[0].AllowedValues).To(Equal([]string{"inox", "vidro"}))
+		Expect(definitions[1].Name).To(Equal("voltage"))
+		Expect(definitions[1].Unit).To(Equal("V"))
+
+		definitions, err = attributeRepo.ListDefinitions(ctx, parent.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(definitions).To(HaveLen(1))
+	})
+
+	It("should replace a definition with the same name", func() {
+		Expect(attributeRepo.SetDefinition(ctx, &domain.AttributeDefinition{CategoryID: parent.ID, Name: "voltage", Type: domain.AttributeText, UpdatedAt: time.Now().UTC()})).To(Succeed())
+
+		definitions, err := attributeRepo.ListDefinitions(ctx, parent.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(definitions).To(HaveLen(1))
+		Expect(definitions[0].Type).To(Equal(domain.AttributeText))
+		Expect(definitions[0].Required).To(BeFalse())
+	})
+
+	It("should load the schema of the categories assigned on create", func() {
+		product := stubs.NewProductStub().Get()
+		product.Attributes = domain.Attributes{"voltage": 220, "material": "inox"}
+		Expect(productRepo.Create(ctx, product, child.ID)).To(Succeed())
+
+		schema, err := attributeRepo.ProductSchema(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schema).To(HaveLen(2))
+
+		found, err := productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Attributes).To(HaveKeyWithValue("material", "inox"))
+	})
+
+	It("should validate the attributes of the product against the categories it is assigned", func() {
+		product := stubs.NewProductStub().Get()
+		product.Attributes = domain.Attributes{"material": "inox", "voltage": 220}
+		Expect(productRepo.Create(ctx, product, child.ID)).To(Succeed())
+
+		// Act: o material só é definido na subcategoria
+		err := categoryRepo.SetProductCategories(ctx, product.ID, []uuid.UUID{parent.ID})
+
+		// Assert: a atribuição é desfeita
+		Expect(errors.Is(err, domain.ErrInvalidAttributes)).To(BeTrue())
+		categories, err := categoryRepo.ListProductCategories(ctx, product.ID, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(categories).To(HaveLen(1))
+		Expect(categories[0].ID).To(Equal(child.ID))
+
+		// Act: a atualização continua a exigir a voltagem herdada
+		found, err := productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		found.Attributes = domain.Attributes{"material": "inox"}
+		err = productRepo.Update(ctx, found)
+
+		// Assert
+		Expect(errors.Is(err, domain.ErrInvalidAttributes)).To(BeTrue())
+	})
+
+	It("should validate the attributes on create in the same transaction as the categories", func() {
+		product := stubs.NewProductStub().Get()
+		product.Attributes = domain.Attributes{"material": "inox"}
+
+		err := productRepo.Create(ctx, product, child.ID)
+
+		Expect(errors.Is(err, domain.ErrInvalidAttributes)).To(BeTrue())
+		_, err = productRepo.GetProductByID(ctx, product.ID)
+		Expect(errors.Is(err, domain.ErrProductNotFound)).To(BeTrue())
+	})
+
+	It("should fail to delete a definition that does not exist", func() {
+		err := attributeRepo.DeleteDefinition(ctx, child.ID, "voltage")
+
+		Expect(errors.Is(err, domain.ErrAttributeNotFound)).To(BeTrue())
+	})
+})
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BundleRepository interface {
+	// SetComponents substitui os componentes do kit; sem componentes o produto volta a ser simples.
+	SetComponents(ctx context.Context, bundleID uuid.UUID, components []domain.BundleComponent) error
+	// ListComponents só lê kits no estado status; vazio lê em qualquer estado.
+	ListComponents(ctx context.Context, bundleID uuid.UUID, status domain.ProductStatus) ([]domain.BundleComponent, error)
+}
+
+type postgresBundleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBundle(db *pgxpool.Pool) BundleRepository {
+	return &postgresBundleRepository{db: db}
+}
+
+// listBundleComponents devolve os componentes do kit por ordem de id, a ordem
+// pela qual são bloqueados.
+func listBundleComponents(ctx context.Context, db dbtx, bundleID uuid.UUID) ([]domain.BundleComponent, error) {
+	rows, err := db.Query(ctx, `SELECT component_id, quantity FROM bundle_components WHERE bundle_id = $1 ORDER BY component_id`, bundleID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.BundleComponent, error) {
+		var component domain.BundleComponent
+		err := row.Scan(&component.ProductID, &component.Quantity)
+		return component, err
+	})
+}
+
+// lockBuildableStock bloqueia os componentes e devolve o número de kits que o
+// stock disponível deles permite montar. Um componente arquivado não conta.
+func lockBuildableStock(ctx context.Context, tx dbtx, components []domain.BundleComponent) (int, error) {
+	buildable := 0
+	for i, component := range components {
+		available, err := lockAvailableStock(ctx, tx, component.ProductID)
+		if errors.Is(err, domain.ErrProductArchived) {
+			available = 0
+		} else if err != nil {
+			return 0, err
+		}
+		if units := available / component.Quantity; i == 0 || units < buildable {
+			buildable = units
+		}
+	}
+	return buildable, nil
+}
+
+// reduceBundleStock retira quantity kits do stock dos componentes na transação
+// do chamador. A falta de stock é relatada no kit, com o número de kits que o
+// stock disponível dos componentes permite montar.
+func reduceBundleStock(ctx context.Context, tx dbtx, bundleID, warehouseID uuid.UUID, quantity int, strategy domain.AllocationStrategy, reason domain.MovementReason) error {
+	components, err := listBundleComponents(ctx, tx, bundleID)
+	if err != nil {
+		return err
+	}
+
+	buildable, err := lockBuildableStock(ctx, tx, components)
+	if err != nil {
+		return err
+	}
+	if buildable < quantity {
+		return &domain.InsufficientStockError{ProductID: bundleID, Requested: quantity, Available: buildable}
+	}
+
+	reference := "bundle:" + bundleID.String()
+	for _, component := range components {
+		err := reduceProductStock(ctx, tx, component.ProductID, warehouseID, quantity*component.Quantity, strategy, reason, reference)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockBundles bloqueia os kits ativos de um lote e devolve os componentes de
+// cada um. Os kits arquivados ficam de fora para serem rejeitados como
+// qualquer outro produto arquivado.
+func lockBundles(ctx context.Context, tx dbtx, items []domain.StockItem) (map[uuid.UUID][]domain.BundleComponent, error) {
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+
+	query := `SELECT b.id, bc.component_id, bc.quantity FROM products b LEFT JOIN bundle_components bc ON bc.bundle_id = b.id
+		WHERE b.id = ANY($1) AND b.type = 'bundle' AND b.status <> 'archived' AND b.deleted_at IS NULL
+		ORDER BY b.id, bc.component_id FOR UPDATE OF b`
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bundles := make(map[uuid.UUID][]domain.BundleComponent)
+	for rows.Next() {
+		var bundleID uuid.UUID
+		var componentID *uuid.UUID
+		var quantity *int
+		if err := rows.Scan(&bundleID, &componentID, &quantity); err != nil {
+			return nil, err
+		}
+		components := bundles[bundleID]
+		if componentID != nil {
+			components = append(components, domain.BundleComponent{ProductID: *componentID, Quantity: *quantity})
+		}
+		bundles[bundleID] = components
+	}
+	return bundles, rows.Err()
+}
+
+func (r *postgresBundleRepository) SetComponents(ctx context.Context, bundleID uuid.UUID, components []domain.BundleComponent) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var stock int
+		var hasVariants, isComponent, isReserved bool
+		query := `SELECT p.stock, EXISTS (SELECT 1 FROM product_variants WHERE product_id = p.id),
+			EXISTS (SELECT 1 FROM bundle_components WHERE component_id = p.id),
+			EXISTS (SELECT 1 FROM stock_reservations WHERE product_id = p.id AND status = 'active' AND expires_at > NOW())
+			FROM products p WHERE p.id = $1 AND p.deleted_at IS NULL FOR UPDATE`
+		if err := tx.QueryRow(ctx, query, bundleID).Scan(&stock, &hasVariants, &isComponent, &isReserved); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+
+		// As reservas de um kit já repartiram as unidades pelos componentes atuais.
+		if isReserved {
+			return fmt.Errorf("%w: the product has active reservations", domain.ErrBundleStock)
+		}
+		if len(components) > 0 {
+			switch {
+			case stock != 0:
+				return fmt.Errorf("%w: the product still has %d units of its own stock", domain.ErrBundleStock, stock)
+			case hasVariants:
+				return fmt.Errorf("%w: products with variants cannot be bundles", domain.ErrInvalidBundleComponents)
+			case isComponent:
+				return fmt.Errorf("%w: a component of another bundle cannot be a bundle", domain.ErrInvalidBundleComponents)
+			}
+		}
+
+		// Os componentes ficam bloqueados para não passarem a kits nem serem removidos entretanto.
+		ids := make([]uuid.UUID, 0, len(components))
+		for _, component := range components {
+			ids = append(ids, component.ProductID)
+		}
+		rows, err := tx.Query(ctx, `SELECT id, type FROM products WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY id FOR SHARE`, ids)
+		if err != nil {
+			return err
+		}
+		types := make(map[uuid.UUID]domain.ProductType, len(ids))
+		for rows.Next() {
+			var id uuid.UUID
+			var productType domain.ProductType
+			if err := rows.Scan(&id, &productType); err != nil {
+				rows.Close()
+				return err
+			}
+			types[id] = productType
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, component := range components {
+			switch productType, found := types[component.ProductID]; {
+			case !found:
+				return fmt.Errorf("%w: component %s not found", domain.ErrInvalidBundleComponents, component.ProductID)
+			case productType == domain.ProductBundle:
+				return fmt.Errorf("%w: component %s is a bundle", domain.ErrInvalidBundleComponents, component.ProductID)
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM bundle_components WHERE bundle_id = $1`, bundleID); err != nil {
+			return err
+		}
+		for _, component := range components {
+			query := `INSERT INTO bundle_components (bundle_id, component_id, quantity) VALUES ($1, $2, $3)`
+			if _, err := tx.Exec(ctx, query, bundleID, component.ProductID, component.Quantity); err != nil {
+				return err
+			}
+		}
+
+		productType := domain.ProductSimple
+		if len(components) > 0 {
+			productType = domain.ProductBundle
+		}
+		if _, err := tx.Exec(ctx, `UPDATE products SET type = $1, updated_at = NOW(), version = version + 1 WHERE id = $2`, productType, bundleID); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, bundleID, domain.RevisionUpdate)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrInvalidBundleComponents) || errors.Is(err, domain.ErrBundleStock) {
+			return fmt.Errorf("Error when setting bundle components: %w", err)
+		}
+		return fmt.Errorf("Error when setting bundle components: %w", domain.ErrToSaveBundle)
+	}
+	return nil
+}
+
+func (r *postgresBundleRepository) ListComponents(ctx context.Context, bundleID uuid.UUID, status domain.ProductStatus) ([]domain.BundleComponent, error) {
+
+	// O alias p é o do componente, para que availableStockExpr devolva o stock disponível de cada um.
+	query := `SELECT p.id, bc.quantity, p.sku, p.name, ` + availableStockExpr + `
+		FROM bundle_components bc JOIN products b ON b.id = bc.bundle_id JOIN products p ON p.id = bc.component_id
+		WHERE bc.bundle_id = $1 AND b.deleted_at IS NULL AND ($2::text = '' OR b.status = $2) ORDER BY p.sku`
+	rows, err := r.db.Query(ctx, query, bundleID, status)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing bundle components: %w", err)
+	}
+	components, err := pgx.CollectRows(rows, pgx.RowToStructByPos[domain.BundleComponent])
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing bundle components: %w", err)
+	}
+	return components, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bundles", func() {
+	var productRepo ProductRepository
+	var bundleRepo BundleRepository
+	var ctx context.Context
+	var bundle, first, second *domain.Product
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		bundleRepo = NewBundle(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Arrange: um kit com 1 unidade do primeiro produto e 2 do segundo
+		bundle = stubs.NewProductStub().WithStock(0).Get()
+		first = stubs.NewProductStub().WithStock(5).Get()
+		second = stubs.NewProductStub().WithStock(7).Get()
+		for _, product := range []*domain.Product{bundle, first, second} {
+			Expect(productRepo.Create(ctx, product)).To(Succeed())
+		}
+		Expect(bundleRepo.SetComponents(ctx, bundle.ID, []domain.BundleComponent{
+			{ProductID: first.ID, Quantity: 1},
+			{ProductID: second.ID, Quantity: 2},
+		})).To(Succeed())
+	})
+
+	It("should derive the available stock from the components", func() {
+		found, err := productRepo.GetProductByID(ctx, bundle.ID)
+
+		// Assert: 7 unidades do segundo produto só chegam para 3 kits
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Type).To(Equal(domain.ProductBundle))
+		Expect(found.AvailableStock).To(Equal(3))
+
+		components, err := bundleRepo.ListComponents(ctx, bundle.ID, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(components).To(HaveLen(2))
+	})
+
+	It("should reduce every component in the same transaction", func() {
+		Expect(productRepo.ReduceStock(ctx, bundle.ID, uuid.Nil, uuid.Nil, 2)).To(Succeed())
+
+		foundFirst, err := productRepo.GetProductByID(ctx, first.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(foundFirst.Stock).To(Equal(3))
+		foundSecond, err := productRepo.GetProductByID(ctx, second.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(foundSecond.Stock).To(Equal(3))
+	})
+
+	It("should not reduce any component when one of them is short", func() {
+		err := productRepo.ReduceStock(ctx, bundle.ID, uuid.Nil, uuid.Nil, 4)
+
+		var stockErr *domain.InsufficientStockError
+		Expect(errors.As(err, &stockErr)).To(BeTrue())
+		Expect(stockErr.ProductID).To(Equal(bundle.ID))
+		Expect(stockErr.Available).To(Equal(3))
+
+		foundFirst, err := productRepo.GetProductByID(ctx, first.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(foundFirst.Stock).To(Equal(5))
+	})
+
+	It("should report bundle lines of a batch against the bundle", func() {
+		err := productRepo.ReduceStockBatch(ctx, []domain.StockItem{
+			{ProductID: bundle.ID, Quantity: 2},
+			{ProductID: second.ID, Quantity: 4},
+		})
+
+		// Assert: o kit consome 4 unidades do segundo produto e a linha seguinte fica sem stock
+		var batchErr *domain.BatchStockError
+		Expect(errors.As(err, &batchErr)).To(BeTrue())
+		Expect(batchErr.Lines).To(HaveLen(1))
+		Expect(batchErr.Lines[0].ProductID).To(Equal(second.ID))
+		Expect(batchErr.Lines[0].Available).To(Equal(3))
+	})
+
+	It("should reject stock changes on the bundle itself", func() {
+		_, err := productRepo.IncreaseStock(ctx, bundle.ID, uuid.Nil, 10, "")
+
+		Expect(errors.Is(err, domain.ErrBundleStock)).To(BeTrue())
+	})
+
+	It("should not delete a product that is a component of a bundle", func() {
+		err := productRepo.Delete(ctx, first.ID, first.Version)
+
+		Expect(errors.Is(err, domain.ErrProductInBundle)).To(BeTrue())
+	})
+
+	It("should reject a bundle as a component", func() {
+		other := stubs.NewProductStub().WithStock(0).Get()
+		Expect(productRepo.Create(ctx, other)).To(Succeed())
+
+		err := bundleRepo.SetComponents(ctx, other.ID, []domain.BundleComponent{{ProductID: bundle.ID, Quantity: 1}})
+
+		Expect(errors.Is(err, domain.ErrInvalidBundleComponents)).To(BeTrue())
+	})
+})
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *domain.Category) error
+	GetCategoryByID(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	// ListCategories devolve todas as categorias ordenadas por posição.
+	ListCategories(ctx context.Context) ([]*domain.Category, error)
+	Update(ctx context.Context, category *domain.Category) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// SetProductCategories substitui as categorias atribuídas ao produto.
+	SetProductCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error
+	// ListProductCategories só lê produtos no estado status; vazio lê em qualquer estado.
+	ListProductCategories(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Category, error)
+}
+
+type postgresCategoryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCategory(db *pgxpool.Pool) CategoryRepository {
+	return &postgresCategoryRepository{db: db}
+}
+
+const categoryColumns = `c.id, c.parent_id, c.name, c.slug, c.position, c.created_at, c.updated_at`
+
+func scanCategory(row pgx.Row) (*domain.Category, error) {
+	category := &domain.Category{}
+	err := row.Scan(&category.ID, &category.ParentID, &category.Name, &category.Slug, &category.Position, &category.CreatedAt, &category.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func collectCategories(rows pgx.Rows) ([]*domain.Category, error) {
+	defer rows.Close()
+
+	categories := make([]*domain.Category, 0)
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning category row: %w", err)
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+// categorySaveError traduz as violações de restrições ao gravar uma categoria.
+func categorySaveError(err error) error {
+	switch {
+	case isUniqueViolation(err):
+		return domain.ErrCategorySlugTaken
+	case isForeignKeyViolation(err):
+		return fmt.Errorf("parent %w", domain.ErrCategoryNotFound)
+	case errors.Is(err, domain.ErrCategoryNotFound), errors.Is(err, domain.ErrCategoryCycle):
+		return err
+	default:
+		return domain.ErrToSaveCategory
+	}
+}
+
+func (r *postgresCategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+
+	query := `INSERT INTO categories (id, parent_id, name, slug, position, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.db.Exec(ctx, query, category.ID, category.ParentID, category.Name, category.Slug, category.Position, category.CreatedAt, category.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("Error creating category: %w", categorySaveError(err))
+	}
+	return nil
+}
+
+func (r *postgresCategoryRepository) GetCategoryByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.id = $1`
+	category, err := scanCategory(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when searching for category by ID: %w", domain.ErrCategoryNotFound)
+		}
+		return nil, fmt.Errorf("Error when searching for category by ID: %w", err)
+	}
+	return category, nil
+}
+
+func (r *postgresCategoryRepository) ListCategories(ctx context.Context) ([]*domain.Category, error) {
+
+	rows, err := r.db.Query(ctx, `SELECT `+categoryColumns+` FROM categories c ORDER BY c.position, c.name`)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing categories: %w", err)
+	}
+	categories, err := collectCategories(rows)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (r *postgresCategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if category.ParentID != nil {
+			// Serializa as mudanças de pai para que duas movimentações concorrentes não formem um ciclo.
+			if _, err := tx.Exec(ctx, `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+				return err
+			}
+
+			var cycle bool
+			query := `WITH RECURSIVE ancestors AS (
+					SELECT id, parent_id FROM categories WHERE id = $1
+					UNION ALL
+					SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+				)
+				SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`
+			if err := tx.QueryRow(ctx, query, *category.ParentID, category.ID).Scan(&cycle); err != nil {
+				return err
+			}
+			if cycle {
+				return domain.ErrCategoryCycle
+			}
+		}
+
+		query := `UPDATE categories SET parent_id = $1, name = $2, slug = $3, position = $4, updated_at = $5 WHERE id = $6`
+		tag, err := tx.Exec(ctx, query, category.ParentID, category.Name, category.Slug, category.Position, category.UpdatedAt, category.ID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrCategoryNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error when updating category: %w", categorySaveError(err))
+	}
+	return nil
+}
+
+func (r *postgresCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return fmt.Errorf("Error when deleting category: %w", domain.ErrCategoryHasChildren)
+		}
+		return fmt.Errorf("Error when deleting category: %w", domain.ErrToSaveCategory)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Error when deleting category: %w", domain.ErrCategoryNotFound)
+	}
+	return nil
+}
+
+func (r *postgresCategoryRepository) SetProductCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var attributes domain.Attributes
+		err := tx.QueryRow(ctx, `SELECT attributes FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, productID).Scan(&attributes)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM product_categories WHERE product_id = $1`, productID); err != nil {
+			return err
+		}
+		if len(categoryIDs) > 0 {
+			query := `INSERT INTO product_categories (product_id, category_id) SELECT $1, unnest($2::uuid[]) ON CONFLICT DO NOTHING`
+			if _, err := tx.Exec(ctx, query, productID, categoryIDs); err != nil {
+				if isForeignKeyViolation(err) {
+					return domain.ErrCategoryNotFound
+				}
+				return err
+			}
+		}
+
+		// Os atributos atuais têm de cumprir o esquema das novas categorias.
+		return validateProductAttributes(ctx, tx, productID, attributes)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrCategoryNotFound) || errors.Is(err, domain.ErrInvalidAttributes) {
+			return fmt.Errorf("Error when assigning product categories: %w", err)
+		}
+		return fmt.Errorf("Error when assigning product categories: %w", domain.ErrToAssignCategories)
+	}
+	return nil
+}
+
+func (r *postgresCategoryRepository) ListProductCategories(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Category, error) {
+
+	query := `SELECT ` + categoryColumns + ` FROM categories c JOIN product_categories pc ON pc.category_id = c.id JOIN products p ON p.id = pc.product_id
+		WHERE pc.product_id = $1 AND p.deleted_at IS NULL AND ($2::text = '' OR p.status = $2) ORDER BY c.position, c.name`
+	rows, err := r.db.Query(ctx, query, productID, status)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing product categories: %w", err)
+	}
+	categories, err := collectCategories(rows)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing product categories: %w", err)
+	}
+	return categories, nil
+}
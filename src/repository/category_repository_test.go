@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Categories", func() {
+	var productRepo ProductRepository
+	var categoryRepo CategoryRepository
+	var ctx context.Context
+
+	newCategory := func(slug string, parent *domain.Category) *domain.Category {
+		now := time.Now().UTC()
+		category := &domain.Category{ID: uuid.New(), Name: slug, Slug: slug, CreatedAt: now, UpdatedAt: now}
+		if parent != nil {
+			category.ParentID = &parent.ID
+		}
+		Expect(categoryRepo.Create(ctx, category)).To(Succeed())
+		return category
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		categoryRepo = NewCategory(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products, categories RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a duplicated slug", func() {
+		newCategory("casa", nil)
+
+		now := time.Now().UTC()
+		err := categoryRepo.Create(ctx, &domain.Category{ID: uuid.New(), Name: "Casa", Slug: "casa", CreatedAt: now, UpdatedAt: now})
+
+		Expect(errors.Is(err, domain.ErrCategorySlugTaken)).To(BeTrue())
+	})
+
+	It("should not move a category below one of its descendants", func() {
+		// Arrange: casa > cozinha > talheres
+		casa := newCategory("casa", nil)
+		cozinha := newCategory("cozinha", casa)
+		talheres := newCategory("talheres", cozinha)
+
+		// Act: Tenta pôr casa dentro de talheres
+		casa.ParentID = &talheres.ID
+		err := categoryRepo.Update(ctx, casa)
+
+		// Assert: A mudança é rejeitada
+		Expect(errors.Is(err, domain.ErrCategoryCycle)).To(BeTrue())
+	})
+
+	It("should not delete a category that has subcategories", func() {
+		casa := newCategory("casa", nil)
+		newCategory("cozinha", casa)
+
+		err := categoryRepo.Delete(ctx, casa.ID)
+
+		Expect(errors.Is(err, domain.ErrCategoryHasChildren)).To(BeTrue())
+	})
+
+	It("should list the products of a category and of its descendants", func() {
+		// Arrange: um produto em casa, outro em talheres (neto de casa) e outro numa categoria à parte
+		casa := newCategory("casa", nil)
+		talheres := newCategory("talheres", newCategory("cozinha", casa))
+		jardim := newCategory("jardim", nil)
+
+		inRoot := stubs.NewProductStub().Get()
+		inGrandchild := stubs.NewProductStub().Get()
+		elsewhere := stubs.NewProductStub().Get()
+		for _, product := range []*domain.Product{inRoot, inGrandchild, elsewhere} {
+			Expect(productRepo.Create(ctx, product)).To(Succeed())
+		}
+		Expect(categoryRepo.SetProductCategories(ctx, inRoot.ID, []uuid.UUID{casa.ID})).To(Succeed())
+		Expect(categoryRepo.SetProductCategories(ctx, inGrandchild.ID, []uuid.UUID{talheres.ID, jardim.ID})).To(Succeed())
+		Expect(categoryRepo.SetProductCategories(ctx, elsewhere.ID, []uuid.UUID{jardim.ID})).To(Succeed())
+
+		// Act: Lista os produtos de casa
+		page, err := productRepo.ListProducts(ctx, domain.ProductQuery{Limit: 50, SortBy: domain.SortByCreatedAt, CategoryID: &casa.ID})
+
+		// Assert: Inclui o produto da subcategoria, sem repetir nem incluir o de jardim
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items).To(HaveLen(2))
+		Expect([]uuid.UUID{page.Items[0].ID, page.Items[1].ID}).To(ConsistOf(inRoot.ID, inGrandchild.ID))
+	})
+
+	It("should replace the categories assigned to a product", func() {
+		casa := newCategory("casa", nil)
+		jardim := newCategory("jardim", nil)
+		product := stubs.NewProductStub().Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		Expect(categoryRepo.SetProductCategories(ctx, product.ID, []uuid.UUID{casa.ID})).To(Succeed())
+
+		Expect(categoryRepo.SetProductCategories(ctx, product.ID, []uuid.UUID{jardim.ID})).To(Succeed())
+
+		categories, err := categoryRepo.ListProductCategories(ctx, product.ID, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(categories).To(HaveLen(1))
+		Expect(categories[0].ID).To(Equal(jardim.ID))
+	})
+})
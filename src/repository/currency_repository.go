@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CurrencyRepository interface {
+	ListExchangeRates(ctx context.Context) ([]*domain.ExchangeRate, error)
+	// SetExchangeRate cria ou substitui a taxa de câmbio entre as duas moedas.
+	SetExchangeRate(ctx context.Context, rate *domain.ExchangeRate) error
+	DeleteExchangeRate(ctx context.Context, baseCurrency, currency string) error
+	ListProductPrices(ctx context.Context, productID uuid.UUID) ([]domain.Money, error)
+	// SetProductPrice cria ou substitui o preço explícito do produto na moeda do preço.
+	SetProductPrice(ctx context.Context, productID uuid.UUID, price domain.Money) error
+	DeleteProductPrice(ctx context.Context, productID uuid.UUID, currency string) error
+}
+
+type postgresCurrencyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCurrency(db *pgxpool.Pool) CurrencyRepository {
+	return &postgresCurrencyRepository{db: db}
+}
+
+// priceInCurrencyExpr calcula em SQL o preço do produto na moeda do parâmetro
+// indicado, com as mesmas regras de domain.Product.ConvertPrices: o preço base
+// na própria moeda, o preço explícito ou a conversão pela taxa de câmbio,
+// arredondada às casas da moeda. É NULL quando o produto não tem preço na moeda.
+func priceInCurrencyExpr(param, currency string) string {
+	return fmt.Sprintf(`(CASE WHEN p.currency = %[1]s THEN p.price ELSE COALESCE(
+		(SELECT pp.price FROM product_prices pp WHERE pp.product_id = p.id AND pp.currency = %[1]s),
+		ROUND(p.price * (SELECT er.rate FROM exchange_rates er WHERE er.base_currency = p.currency AND er.currency = %[1]s), %[2]d)) END)`,
+		param, domain.CurrencyExponent(currency))
+}
+
+// convertProductPrices mostra os preços dos produtos na moeda indicada, lendo
+// de uma só vez os preços explícitos e as taxas de câmbio necessárias.
+func convertProductPrices(ctx context.Context, db dbtx, currency string, products []*domain.Product) error {
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		if product.Price.Currency != currency {
+			ids = append(ids, product.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	explicit := make(map[uuid.UUID]domain.Money)
+	rows, err := db.Query(ctx, `SELECT product_id, price::text FROM product_prices WHERE product_id = ANY($1) AND currency = $2`, ids, currency)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var productID uuid.UUID
+		var amount string
+		if err := rows.Scan(&productID, &amount); err != nil {
+			return err
+		}
+		if explicit[productID], err = domain.ParseMoney(amount, currency); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rates := make(map[string]*big.Rat)
+	rows, err = db.Query(ctx, `SELECT base_currency, rate::text FROM exchange_rates WHERE currency = $1`, currency)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var base, value string
+		if err := rows.Scan(&base, &value); err != nil {
+			return err
+		}
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok {
+			return fmt.Errorf("%w: %q", domain.ErrInvalidExchangeRate, value)
+		}
+		rates[base] = rate
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		var price *domain.Money
+		if p, ok := explicit[product.ID]; ok {
+			price = &p
+		}
+		if err := product.ConvertPrices(currency, price, rates[product.Price.Currency]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresCurrencyRepository) ListExchangeRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+
+	rows, err := r.db.Query(ctx, `SELECT base_currency, currency, trim_scale(rate)::text, updated_at FROM exchange_rates ORDER BY base_currency, currency`)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := make([]*domain.ExchangeRate, 0)
+	for rows.Next() {
+		rate := &domain.ExchangeRate{}
+		if err := rows.Scan(&rate.BaseCurrency, &rate.Currency, &rate.Rate, &rate.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning exchange rate row: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when listing exchange rates: %w", err)
+	}
+	return rates, nil
+}
+
+func (r *postgresCurrencyRepository) SetExchangeRate(ctx context.Context, rate *domain.ExchangeRate) error {
+
+	query := `INSERT INTO exchange_rates (base_currency, currency, rate, updated_at) VALUES ($1, $2, $3::text::numeric, NOW())
+		ON CONFLICT (base_currency, currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at
+		RETURNING trim_scale(rate)::text, updated_at`
+	err := r.db.QueryRow(ctx, query, rate.BaseCurrency, rate.Currency, rate.Rate).Scan(&rate.Rate, &rate.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("Error setting exchange rate: %w", domain.ErrToSaveExchangeRate)
+	}
+	return nil
+}
+
+func (r *postgresCurrencyRepository) DeleteExchangeRate(ctx context.Context, baseCurrency, currency string) error {
+
+	result, err := r.db.Exec(ctx, `DELETE FROM exchange_rates WHERE base_currency = $1 AND currency = $2`, baseCurrency, currency)
+	if err != nil {
+		return fmt.Errorf("Error deleting exchange rate: %w", domain.ErrToSaveExchangeRate)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("Error deleting exchange rate: %w", domain.ErrExchangeRateNotFound)
+	}
+	return nil
+}
+
+func (r *postgresCurrencyRepository) ListProductPrices(ctx context.Context, productID uuid.UUID) ([]domain.Money, error) {
+
+	rows, err := r.db.Query(ctx, `SELECT price::text, currency FROM product_prices WHERE product_id = $1 ORDER BY currency`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing product prices: %w", err)
+	}
+	defer rows.Close()
+
+	prices := make([]domain.Money, 0)
+	for rows.Next() {
+		var amount, currency string
+		if err := rows.Scan(&amount, &currency); err != nil {
+			return nil, fmt.Errorf("error scanning product price row: %w", err)
+		}
+		price, err := domain.ParseMoney(amount, currency)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning product price row: %w", err)
+		}
+		prices = append(prices, price)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when listing product prices: %w", err)
+	}
+	return prices, nil
+}
+
+func (r *postgresCurrencyRepository) SetProductPrice(ctx context.Context, productID uuid.UUID, price domain.Money) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var currency string
+		err := tx.QueryRow(ctx, `SELECT currency FROM products WHERE id = $1 AND deleted_at IS NULL FOR SHARE`, productID).Scan(&currency)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+		// O preço na moeda base é o próprio preço do produto.
+		if price.Currency == currency {
+			return fmt.Errorf("%w: %s is the base currency of the product", domain.ErrCurrencyMismatch, currency)
+		}
+
+		query := `INSERT INTO product_prices (product_id, currency, price, updated_at) VALUES ($1, $2, $3::text::numeric, NOW())
+			ON CONFLICT (product_id, currency) DO UPDATE SET price = EXCLUDED.price, updated_at = EXCLUDED.updated_at`
+		_, err = tx.Exec(ctx, query, productID, price.Currency, price.Amount())
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrCurrencyMismatch) {
+			return fmt.Errorf("Error setting product price: %w", err)
+		}
+		return fmt.Errorf("Error setting product price: %w", domain.ErrToSaveProductPrice)
+	}
+	return nil
+}
+
+func (r *postgresCurrencyRepository) DeleteProductPrice(ctx context.Context, productID uuid.UUID, currency string) error {
+
+	result, err := r.db.Exec(ctx, `DELETE FROM product_prices WHERE product_id = $1 AND currency = $2`, productID, currency)
+	if err != nil {
+		return fmt.Errorf("Error deleting product price: %w", domain.ErrToSaveProductPrice)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("Error deleting product price: %w", domain.ErrProductPriceNotFound)
+	}
+	return nil
+}
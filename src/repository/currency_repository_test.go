@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Currencies", func() {
+	var productRepo ProductRepository
+	var currencyRepo CurrencyRepository
+	var ctx context.Context
+	var product *domain.Product
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		currencyRepo = NewCurrency(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products, exchange_rates RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+
+		product = stubs.NewProductStub().WithPrice("100.00").Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		Expect(currencyRepo.SetExchangeRate(ctx, &domain.ExchangeRate{BaseCurrency: "BRL", Currency: "USD", Rate: "0.1835"})).To(Succeed())
+		Expect(currencyRepo.SetExchangeRate(ctx, &domain.ExchangeRate{BaseCurrency: "BRL", Currency: "JPY", Rate: "27.4567"})).To(Succeed())
+	})
+
+	It("should convert prices with the exchange rate and the rounding of the currency", func() {
+		// Act
+		found, err := productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(productRepo.ConvertPrices(ctx, "JPY", found)).To(Succeed())
+
+		// Assert: 100.00 × 27.4567 = 2745.67, arredondado a ienes inteiros
+		Expect(found.Price).To(Equal(domain.Money{Minor: 2746, Currency: "JPY"}))
+		Expect(found.EffectivePrice).To(Equal(found.Price))
+	})
+
+	It("should prefer the explicit price and keep the sale proportional to it", func() {
+		// Arrange: um preço explícito em dólares e uma promoção de 20%
+		Expect(currencyRepo.SetProductPrice(ctx, product.ID, domain.Money{Minor: 1990, Currency: "USD"})).To(Succeed())
+		_, err := productRepo.SetSale(ctx, product.ID, &domain.Sale{Price: domain.Money{Minor: 8000, Currency: "BRL"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Act
+		found, err := productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(productRepo.ConvertPrices(ctx, "USD", found)).To(Succeed())
+
+		// Assert
+		Expect(found.Price.Amount()).To(Equal("19.90"))
+		Expect(found.Sale.Price.Amount()).To(Equal("15.92"))
+		Expect(found.EffectivePrice.Amount()).To(Equal("15.92"))
+	})
+
+	It("should fail when there is neither an explicit price nor an exchange rate", func() {
+		found, err := productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = productRepo.ConvertPrices(ctx, "EUR", found)
+
+		Expect(errors.Is(err, domain.ErrPriceNotAvailable)).To(BeTrue())
+	})
+
+	It("should filter and sort the list by the price in the requested currency", func() {
+		// Arrange: um produto mais barato e outro sem preço em dólares
+		cheaper := stubs.NewProductStub().WithPrice("50.00").Get()
+		Expect(productRepo.Create(ctx, cheaper)).To(Succeed())
+		inEuros := stubs.NewProductStub().Get()
+		inEuros.Price = domain.Money{Minor: 1000, Currency: "EUR"}
+		Expect(productRepo.Create(ctx, inEuros)).To(Succeed())
+		minPrice := domain.Money{Minor: 900, Currency: "USD"}
+
+		// Act
+		page, err := productRepo.ListProducts(ctx, domain.ProductQuery{Currency: "USD", MinPrice: &minPrice, SortBy: domain.SortByPrice, Limit: 10})
+
+		// Assert: 50.00 BRL = 9.18 USD e 100.00 BRL = 18.35 USD, por ordem de preço
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items).To(HaveLen(2))
+		Expect(page.Items[0].ID).To(Equal(cheaper.ID))
+		Expect(page.Items[0].Price.Amount()).To(Equal("9.18"))
+		Expect(page.Items[1].Price.Amount()).To(Equal("18.35"))
+	})
+
+	It("should not store an explicit price in the base currency of the product", func() {
+		err := currencyRepo.SetProductPrice(ctx, product.ID, domain.Money{Minor: 9000, Currency: "BRL"})
+
+		Expect(errors.Is(err, domain.ErrCurrencyMismatch)).To(BeTrue())
+	})
+})
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"product-service/src/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Os cursores de paginação são opacos para o cliente: codificam a chave de
+// ordenação (timestamp e ID) do último registo da página anterior.
+
+func encodeCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("Error when decoding cursor: %w", domain.ErrInvalidCursor)
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, fmt.Errorf("Error when decoding cursor: %w", domain.ErrInvalidCursor)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("Error when decoding cursor: %w", domain.ErrInvalidCursor)
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("Error when decoding cursor: %w", domain.ErrInvalidCursor)
+	}
+	return createdAt, id, nil
+}
+
+// Os cursores da listagem de produtos incluem também o campo e a direção da
+// ordenação, para rejeitar cursores usados com uma ordenação diferente.
+
+func productCursorSort(sortBy domain.ProductSortField, descending bool) string {
+	if descending {
+		return "-" + string(sortBy)
+	}
+	return string(sortBy)
+}
+
+func encodeProductCursor(sortBy domain.ProductSortField, descending bool, value string, id uuid.UUID) string {
+	raw := productCursorSort(sortBy, descending) + "|" + id.String() + "|" + value
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeProductCursor(cursor string, sortBy domain.ProductSortField, descending bool) (string, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", uuid.Nil, fmt.Errorf("Error when decoding cursor: %w", domain.ErrInvalidCursor)
+	}
+
+	// O valor vai no fim porque pode conter o separador (ex: nomes de produtos).
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 || parts[0] != productCursorSort(sortBy, descending) {
+		return "", uuid.Nil, fmt.Errorf("Error when decoding cursor: %w", domain.ErrInvalidCursor)
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return "", uuid.Nil, fmt.Errorf("Error when decoding cursor: %w", domain.ErrInvalidCursor)
+	}
+	return parts[2], id, nil
+}
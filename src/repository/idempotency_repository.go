@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository interface {
+	// Claim regista a chave do principal como em processamento. Devolve
+	// created = false e o registo existente quando a chave já tinha sido usada.
+	Claim(ctx context.Context, principal, key, requestHash string) (record *domain.IdempotencyRecord, created bool, err error)
+	Complete(ctx context.Context, principal, key string, statusCode int, responseBody []byte) error
+	Delete(ctx context.Context, principal, key string) error
+	PurgeOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
+type postgresIdempotencyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotency(db *pgxpool.Pool) IdempotencyRepository {
+	return &postgresIdempotencyRepository{db: db}
+}
+
+// maxClaimAttempts limita as tentativas quando a chave existente desaparece
+// entre o INSERT e o SELECT (abandonada ou purgada entretanto).
+const maxClaimAttempts = 2
+
+func (r *postgresIdempotencyRepository) Claim(ctx context.Context, principal, key, requestHash string) (*domain.IdempotencyRecord, bool, error) {
+
+	for attempt := 0; attempt < maxClaimAttempts; attempt++ {
+		query := `INSERT INTO idempotency_keys (principal, key, request_hash, created_at) VALUES ($1, $2, $3, NOW()) ON CONFLICT (principal, key) DO NOTHING`
+		tag, err := r.db.Exec(ctx, query, principal, key, requestHash)
+		if err != nil {
+			return nil, false, fmt.Errorf("Error when claiming idempotency key: %w", err)
+		}
+		if tag.RowsAffected() == 1 {
+			return nil, true, nil
+		}
+
+		query = `SELECT principal, key, request_hash, COALESCE(status_code, 0), response_body, created_at, completed_at
+			FROM idempotency_keys WHERE principal = $1 AND key = $2`
+		record := &domain.IdempotencyRecord{}
+		err = r.db.QueryRow(ctx, query, principal, key).
+			Scan(&record.Principal, &record.Key, &record.RequestHash, &record.StatusCode, &record.ResponseBody, &record.CreatedAt, &record.CompletedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("Error when claiming idempotency key: %w", err)
+		}
+		return record, false, nil
+	}
+	// A chave continua a mudar de mãos; o cliente pode repetir o pedido mais tarde.
+	return nil, false, fmt.Errorf("Error when claiming idempotency key: %w", domain.ErrIdempotencyInProgress)
+}
+
+func (r *postgresIdempotencyRepository) Complete(ctx context.Context, principal, key string, statusCode int, responseBody []byte) error {
+
+	query := `UPDATE idempotency_keys SET status_code = $1, response_body = $2, completed_at = NOW() WHERE principal = $3 AND key = $4`
+	_, err := r.db.Exec(ctx, query, statusCode, responseBody, principal, key)
+	if err != nil {
+		return fmt.Errorf("Error when completing idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresIdempotencyRepository) Delete(ctx context.Context, principal, key string) error {
+
+	_, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE principal = $1 AND key = $2`, principal, key)
+	if err != nil {
+		return fmt.Errorf("Error when deleting idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresIdempotencyRepository) PurgeOlderThan(ctx context.Context, before time.Time) (int64, error) {
+
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("Error when purging idempotency keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
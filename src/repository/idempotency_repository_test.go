@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Idempotency keys", func() {
+	var idempotencyRepo IdempotencyRepository
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		idempotencyRepo = NewIdempotency(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE idempotency_keys")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should claim a new key and return the stored response on the next claim", func() {
+		// Act: primeiro pedido
+		record, created, err := idempotencyRepo.Claim(ctx, "user:a", "key-1", "hash-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(BeTrue())
+		Expect(record).To(BeNil())
+
+		Expect(idempotencyRepo.Complete(ctx, "user:a", "key-1", 201, []byte(`{"id":"1"}`))).To(Succeed())
+
+		// Act: repetição
+		record, created, err = idempotencyRepo.Claim(ctx, "user:a", "key-1", "hash-1")
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(BeFalse())
+		Expect(record.Principal).To(Equal("user:a"))
+		Expect(record.StatusCode).To(Equal(201))
+		Expect(record.ResponseBody).To(Equal([]byte(`{"id":"1"}`)))
+		Expect(record.CompletedAt).NotTo(BeNil())
+	})
+
+	It("should return the existing record when the key is reused with another request", func() {
+		_, _, err := idempotencyRepo.Claim(ctx, "user:a", "key-1", "hash-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Act
+		record, created, err := idempotencyRepo.Claim(ctx, "user:a", "key-1", "hash-2")
+
+		// Assert: o serviço compara os hashes e recusa o pedido
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(BeFalse())
+		Expect(record.RequestHash).To(Equal("hash-1"))
+		Expect(record.CompletedAt).To(BeNil())
+	})
+
+	It("should keep the keys of different principals apart", func() {
+		_, _, err := idempotencyRepo.Claim(ctx, "user:a", "key-1", "hash-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idempotencyRepo.Complete(ctx, "user:a", "key-1", 201, []byte(`{"id":"1"}`))).To(Succeed())
+
+		// Act
+		record, created, err := idempotencyRepo.Claim(ctx, "user:b", "key-1", "hash-2")
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(BeTrue())
+		Expect(record).To(BeNil())
+
+		// Abandonar a chave de um principal não liberta a do outro
+		Expect(idempotencyRepo.Delete(ctx, "user:b", "key-1")).To(Succeed())
+		record, created, err = idempotencyRepo.Claim(ctx, "user:a", "key-1", "hash-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(BeFalse())
+		Expect(record.StatusCode).To(Equal(201))
+	})
+
+	It("should free an abandoned key and purge old keys", func() {
+		_, _, err := idempotencyRepo.Claim(ctx, "internal", "key-1", "hash-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idempotencyRepo.Delete(ctx, "internal", "key-1")).To(Succeed())
+
+		_, created, err := idempotencyRepo.Claim(ctx, "internal", "key-1", "hash-2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(BeTrue())
+
+		// Act
+		purged, err := idempotencyRepo.PurgeOlderThan(ctx, time.Now().Add(time.Minute))
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(Equal(int64(1)))
+	})
+})
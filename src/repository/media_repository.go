@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MediaRepository interface {
+	// Create acrescenta a imagem no fim da galeria do produto e preenche Position.
+	Create(ctx context.Context, media *domain.ProductMedia) error
+	// ListByProducts devolve as galerias dos produtos indicados, por produto e posição.
+	ListByProducts(ctx context.Context, productIDs []uuid.UUID) ([]domain.ProductMedia, error)
+	UpdateAltText(ctx context.Context, productID, mediaID uuid.UUID, altText string) (*domain.ProductMedia, error)
+	// Reorder define a ordem da galeria; mediaIDs tem de conter todas as imagens do produto.
+	Reorder(ctx context.Context, productID uuid.UUID, mediaIDs []uuid.UUID) error
+	// Delete remove a imagem, fecha o espaço na galeria e devolve a chave do ficheiro.
+	Delete(ctx context.Context, productID, mediaID uuid.UUID) (string, error)
+}
+
+type postgresMediaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMedia(db *pgxpool.Pool) MediaRepository {
+	return &postgresMediaRepository{db: db}
+}
+
+const mediaColumns = `id, product_id, content_type, size, alt_text, position, created_at, storage_key`
+
+func scanMedia(row pgx.Row) (*domain.ProductMedia, error) {
+	var media domain.ProductMedia
+	err := row.Scan(&media.ID, &media.ProductID, &media.ContentType, &media.Size, &media.AltText, &media.Position, &media.CreatedAt, &media.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// lockMediaProduct bloqueia o produto para que as alterações à galeria não se cruzem.
+func lockMediaProduct(ctx context.Context, tx dbtx, productID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, productID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrProductNotFound
+	}
+	return err
+}
+
+// mediaSaveError preserva os erros que o cliente pode corrigir e esconde os restantes.
+func mediaSaveError(action string, err error) error {
+	if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrMediaNotFound) || errors.Is(err, domain.ErrInvalidMedia) {
+		return fmt.Errorf("Error when %s media: %w", action, err)
+	}
+	return fmt.Errorf("Error when %s media: %w", action, domain.ErrToSaveMedia)
+}
+
+func (r *postgresMediaRepository) Create(ctx context.Context, media *domain.ProductMedia) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if err := lockMediaProduct(ctx, tx, media.ProductID); err != nil {
+			return err
+		}
+		query := `INSERT INTO product_media (id, product_id, storage_key, content_type, size, alt_text, position, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, (SELECT COUNT(*) FROM product_media WHERE product_id = $2), $7)
+			RETURNING position`
+		return tx.QueryRow(ctx, query, media.ID, media.ProductID, media.StorageKey, media.ContentType, media.Size, media.AltText, media.CreatedAt).
+			Scan(&media.Position)
+	})
+	if err != nil {
+		return mediaSaveError("creating", err)
+	}
+	return nil
+}
+
+func (r *postgresMediaRepository) ListByProducts(ctx context.Context, productIDs []uuid.UUID) ([]domain.ProductMedia, error) {
+
+	rows, err := r.db.Query(ctx, `SELECT `+mediaColumns+` FROM product_media WHERE product_id = ANY($1) ORDER BY product_id, position`, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing media: %w", err)
+	}
+	media, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.ProductMedia, error) {
+		media, err := scanMedia(row)
+		if err != nil {
+			return domain.ProductMedia{}, err
+		}
+		return *media, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing media: %w", err)
+	}
+	return media, nil
+}
+
+func (r *postgresMediaRepository) UpdateAltText(ctx context.Context, productID, mediaID uuid.UUID, altText string) (*domain.ProductMedia, error) {
+
+	query := `UPDATE product_media m SET alt_text = $1 FROM products p
+		WHERE m.id = $2 AND m.product_id = $3 AND p.id = m.product_id AND p.deleted_at IS NULL
+		RETURNING m.id, m.product_id, m.content_type, m.size, m.alt_text, m.position, m.created_at, m.storage_key`
+	media, err := scanMedia(r.db.QueryRow(ctx, query, altText, mediaID, productID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when updating media: %w", domain.ErrMediaNotFound)
+		}
+		return nil, fmt.Errorf("Error when updating media: %w", domain.ErrToSaveMedia)
+	}
+	return media, nil
+}
+
+func (r *postgresMediaRepository) Reorder(ctx context.Context, productID uuid.UUID, mediaIDs []uuid.UUID) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if err := lockMediaProduct(ctx, tx, productID); err != nil {
+			return err
+		}
+		var count int
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM product_media WHERE product_id = $1`, productID).Scan(&count); err != nil {
+			return err
+		}
+		// WITH ORDINALITY numera os ids pela ordem pedida; todos têm de ser imagens do produto.
+		query := `UPDATE product_media m SET position = o.position - 1
+			FROM unnest($1::uuid[]) WITH ORDINALITY AS o(id, position)
+			WHERE m.id = o.id AND m.product_id = $2`
+		tag, err := tx.Exec(ctx, query, mediaIDs, productID)
+		if err != nil {
+			return err
+		}
+		if int(tag.RowsAffected()) != count || len(mediaIDs) != count {
+			return fmt.Errorf("%w: the order must list each of the %d images of the product once", domain.ErrInvalidMedia, count)
+		}
+		return nil
+	})
+	if err != nil {
+		return mediaSaveError("reordering", err)
+	}
+	return nil
+}
+
+func (r *postgresMediaRepository) Delete(ctx context.Context, productID, mediaID uuid.UUID) (string, error) {
+
+	var key string
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if err := lockMediaProduct(ctx, tx, productID); err != nil {
+			return err
+		}
+		var position int
+		err := tx.QueryRow(ctx, `DELETE FROM product_media WHERE id = $1 AND product_id = $2 RETURNING storage_key, position`, mediaID, productID).
+			Scan(&key, &position)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrMediaNotFound
+		}
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `UPDATE product_media SET position = position - 1 WHERE product_id = $1 AND position > $2`, productID, position)
+		return err
+	})
+	if err != nil {
+		return "", mediaSaveError("deleting", err)
+	}
+	return key, nil
+}
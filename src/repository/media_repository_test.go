@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/seeder"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Media", func() {
+	var productRepo ProductRepository
+	var mediaRepo MediaRepository
+	var ctx context.Context
+	var product *domain.Product
+
+	newMedia := func(productID uuid.UUID) *domain.ProductMedia {
+		id := uuid.New()
+		return &domain.ProductMedia{ID: id, ProductID: productID, ContentType: "image/png", Size: 10,
+			StorageKey: "products/" + productID.String() + "/" + id.String() + ".png", CreatedAt: time.Now().UTC()}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		mediaRepo = NewMedia(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+		product = stubs.NewProductStub().Get()
+		Expect(seeder.NewTestSeeder(db).InsertProduct(ctx, product)).To(Succeed())
+	})
+
+	It("should append media to the end of the gallery and reorder it", func() {
+		first, second := newMedia(product.ID), newMedia(product.ID)
+		Expect(mediaRepo.Create(ctx, first)).To(Succeed())
+		Expect(mediaRepo.Create(ctx, second)).To(Succeed())
+		Expect(second.Position).To(Equal(1))
+
+		Expect(mediaRepo.Reorder(ctx, product.ID, []uuid.UUID{second.ID, first.ID})).To(Succeed())
+
+		media, err := mediaRepo.ListByProducts(ctx, []uuid.UUID{product.ID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(media).To(HaveLen(2))
+		Expect(media[0].ID).To(Equal(second.ID))
+		Expect(media[0].Position).To(Equal(0))
+	})
+
+	It("should reject an order that does not list every image once", func() {
+		first, second := newMedia(product.ID), newMedia(product.ID)
+		Expect(mediaRepo.Create(ctx, first)).To(Succeed())
+		Expect(mediaRepo.Create(ctx, second)).To(Succeed())
+
+		err := mediaRepo.Reorder(ctx, product.ID, []uuid.UUID{first.ID})
+		Expect(errors.Is(err, domain.ErrInvalidMedia)).To(BeTrue())
+
+		err = mediaRepo.Reorder(ctx, product.ID, []uuid.UUID{first.ID, first.ID})
+		Expect(errors.Is(err, domain.ErrInvalidMedia)).To(BeTrue())
+	})
+
+	It("should not add media to a deleted product", func() {
+		Expect(productRepo.Delete(ctx, product.ID, 1)).To(Succeed())
+
+		err := mediaRepo.Create(ctx, newMedia(product.ID))
+
+		Expect(errors.Is(err, domain.ErrProductNotFound)).To(BeTrue())
+	})
+
+	It("should return the files of the media purged with the product", func() {
+		media := newMedia(product.ID)
+		Expect(mediaRepo.Create(ctx, media)).To(Succeed())
+		Expect(productRepo.Delete(ctx, product.ID, 1)).To(Succeed())
+
+		purged, keys, err := productRepo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(Equal(int64(1)))
+		Expect(keys).To(Equal([]string{media.StorageKey}))
+		var count int
+		Expect(db.QueryRow(ctx, `SELECT COUNT(*) FROM product_media WHERE product_id = $1`, product.ID).Scan(&count)).To(Succeed())
+		Expect(count).To(BeZero())
+	})
+})
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PriceListRepository interface {
+	CreatePriceList(ctx context.Context, priceList *domain.PriceList) error
+	GetPriceList(ctx context.Context, id uuid.UUID) (*domain.PriceList, error)
+	ListPriceLists(ctx context.Context) ([]*domain.PriceList, error)
+	DeletePriceList(ctx context.Context, id uuid.UUID) error
+	// SetProductTiers substitui os escalões do produto na tabela de preços; sem escalões, o produto sai da tabela.
+	SetProductTiers(ctx context.Context, priceListID, productID uuid.UUID, tiers []domain.PriceTier) error
+	ListProductTiers(ctx context.Context, priceListID, productID uuid.UUID) ([]domain.PriceTier, error)
+	// FindTier devolve o escalão de maior quantidade mínima que se aplica à quantidade,
+	// ou nil quando o grupo não tem preço para o produto na moeda indicada.
+	FindTier(ctx context.Context, customerGroup string, productID uuid.UUID, quantity int, currency string) (*domain.PriceList, *domain.PriceTier, error)
+}
+
+type postgresPriceListRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPriceList(db *pgxpool.Pool) PriceListRepository {
+	return &postgresPriceListRepository{db: db}
+}
+
+const priceListColumns = `l.id, l.name, l.customer_group, l.created_at, l.updated_at`
+
+func scanPriceList(row pgx.Row) (*domain.PriceList, error) {
+	priceList := &domain.PriceList{}
+	err := row.Scan(&priceList.ID, &priceList.Name, &priceList.CustomerGroup, &priceList.CreatedAt, &priceList.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return priceList, nil
+}
+
+func (r *postgresPriceListRepository) CreatePriceList(ctx context.Context, priceList *domain.PriceList) error {
+
+	query := `INSERT INTO price_lists (id, name, customer_group, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := r.db.Exec(ctx, query, priceList.ID, priceList.Name, priceList.CustomerGroup, priceList.CreatedAt, priceList.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("Error creating price list: %w", domain.ErrPriceListGroupTaken)
+		}
+		return fmt.Errorf("Error creating price list: %w", domain.ErrToSavePriceList)
+	}
+	return nil
+}
+
+func (r *postgresPriceListRepository) GetPriceList(ctx context.Context, id uuid.UUID) (*domain.PriceList, error) {
+
+	priceList, err := scanPriceList(r.db.QueryRow(ctx, `SELECT `+priceListColumns+` FROM price_lists l WHERE l.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when searching for price list by ID: %w", domain.ErrPriceListNotFound)
+		}
+		return nil, fmt.Errorf("Error when searching for price list by ID: %w", err)
+	}
+	return priceList, nil
+}
+
+func (r *postgresPriceListRepository) ListPriceLists(ctx context.Context) ([]*domain.PriceList, error) {
+
+	rows, err := r.db.Query(ctx, `SELECT `+priceListColumns+` FROM price_lists l ORDER BY l.customer_group`)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing price lists: %w", err)
+	}
+	defer rows.Close()
+
+	priceLists := make([]*domain.PriceList, 0)
+	for rows.Next() {
+		priceList, err := scanPriceList(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning price list row: %w", err)
+		}
+		priceLists = append(priceLists, priceList)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when listing price lists: %w", err)
+	}
+	return priceLists, nil
+}
+
+func (r *postgresPriceListRepository) DeletePriceList(ctx context.Context, id uuid.UUID) error {
+
+	result, err := r.db.Exec(ctx, `DELETE FROM price_lists WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("Error deleting price list: %w", domain.ErrToSavePriceList)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("Error deleting price list: %w", domain.ErrPriceListNotFound)
+	}
+	return nil
+}
+
+func (r *postgresPriceListRepository) SetProductTiers(ctx context.Context, priceListID, productID uuid.UUID, tiers []domain.PriceTier) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM price_lists WHERE id = $1)`, priceListID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrPriceListNotFound
+		}
+
+		// Os escalões são definidos na moeda do produto, tal como os agendamentos de preço.
+		var currency string
+		err := tx.QueryRow(ctx, `SELECT currency FROM products WHERE id = $1 AND deleted_at IS NULL FOR SHARE`, productID).Scan(&currency)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+		for _, tier := range tiers {
+			if tier.UnitPrice.Currency != currency {
+				return fmt.Errorf("%w: tier prices must be in %s", domain.ErrCurrencyMismatch, currency)
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM price_list_tiers WHERE price_list_id = $1 AND product_id = $2`, priceListID, productID); err != nil {
+			return err
+		}
+		for _, tier := range tiers {
+			query := `INSERT INTO price_list_tiers (price_list_id, product_id, min_quantity, price, currency) VALUES ($1, $2, $3, $4::text::numeric, $5)`
+			if _, err := tx.Exec(ctx, query, priceListID, productID, tier.MinQuantity, tier.UnitPrice.Amount(), tier.UnitPrice.Currency); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE price_lists SET updated_at = NOW() WHERE id = $1`, priceListID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrPriceListNotFound) || errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrCurrencyMismatch) {
+			return fmt.Errorf("Error setting price tiers: %w", err)
+		}
+		return fmt.Errorf("Error setting price tiers: %w", domain.ErrToSavePriceList)
+	}
+	return nil
+}
+
+func (r *postgresPriceListRepository) ListProductTiers(ctx context.Context, priceListID, productID uuid.UUID) ([]domain.PriceTier, error) {
+
+	query := `SELECT min_quantity, price::text, currency FROM price_list_tiers WHERE price_list_id = $1 AND product_id = $2 ORDER BY min_quantity`
+	rows, err := r.db.Query(ctx, query, priceListID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing price tiers: %w", err)
+	}
+	defer rows.Close()
+
+	tiers := make([]domain.PriceTier, 0)
+	for rows.Next() {
+		var tier domain.PriceTier
+		var amount, currency string
+		if err := rows.Scan(&tier.MinQuantity, &amount, &currency); err != nil {
+			return nil, fmt.Errorf("error scanning price tier row: %w", err)
+		}
+		if tier.UnitPrice, err = domain.ParseMoney(amount, currency); err != nil {
+			return nil, fmt.Errorf("error scanning price tier row: %w", err)
+		}
+		tiers = append(tiers, tier)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when listing price tiers: %w", err)
+	}
+	return tiers, nil
+}
+
+func (r *postgresPriceListRepository) FindTier(ctx context.Context, customerGroup string, productID uuid.UUID, quantity int, currency string) (*domain.PriceList, *domain.PriceTier, error) {
+
+	query := `SELECT ` + priceListColumns + `, t.min_quantity, t.price::text, t.currency
+		FROM price_lists l JOIN price_list_tiers t ON t.price_list_id = l.id
+		WHERE l.customer_group = $1 AND t.product_id = $2 AND t.min_quantity <= $3 AND t.currency = $4
+		ORDER BY t.min_quantity DESC LIMIT 1`
+
+	priceList := &domain.PriceList{}
+	tier := &domain.PriceTier{}
+	var amount, tierCurrency string
+	err := r.db.QueryRow(ctx, query, customerGroup, productID, quantity, currency).Scan(&priceList.ID, &priceList.Name, &priceList.CustomerGroup,
+		&priceList.CreatedAt, &priceList.UpdatedAt, &tier.MinQuantity, &amount, &tierCurrency)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("Error when searching for price tier: %w", domain.ErrToQuotePrice)
+	}
+	if tier.UnitPrice, err = domain.ParseMoney(amount, tierCurrency); err != nil {
+		return nil, nil, fmt.Errorf("Error when searching for price tier: %w", err)
+	}
+	return priceList, tier, nil
+}
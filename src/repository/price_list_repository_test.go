@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Price lists", func() {
+	var productRepo ProductRepository
+	var priceListRepo PriceListRepository
+	var ctx context.Context
+	var product *domain.Product
+	var priceList *domain.PriceList
+
+	tier := func(minQuantity int, amount string) domain.PriceTier {
+		price, err := domain.ParseMoney(amount, "BRL")
+		Expect(err).NotTo(HaveOccurred())
+		return domain.PriceTier{MinQuantity: minQuantity, UnitPrice: price}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		priceListRepo = NewPriceList(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products, price_lists RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+
+		product = stubs.NewProductStub().WithPrice("10.00").Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		now := time.Now().UTC()
+		priceList = &domain.PriceList{ID: uuid.New(), Name: "Grossistas", CustomerGroup: "grossista", CreatedAt: now, UpdatedAt: now}
+		Expect(priceListRepo.CreatePriceList(ctx, priceList)).To(Succeed())
+	})
+
+	It("should find the tier with the highest minimum quantity reached", func() {
+		// Arrange: escalões a partir de 10 e de 100 unidades
+		Expect(priceListRepo.SetProductTiers(ctx, priceList.ID, product.ID, []domain.PriceTier{tier(10, "9.00"), tier(100, "8.00")})).To(Succeed())
+
+		// Act & Assert
+		_, found, err := priceListRepo.FindTier(ctx, "grossista", product.ID, 5, "BRL")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeNil())
+
+		list, found, err := priceListRepo.FindTier(ctx, "grossista", product.ID, 99, "BRL")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.ID).To(Equal(priceList.ID))
+		Expect(found.MinQuantity).To(Equal(10))
+		Expect(found.UnitPrice.Amount()).To(Equal("9.00"))
+
+		_, found, err = priceListRepo.FindTier(ctx, "grossista", product.ID, 100, "BRL")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.UnitPrice.Amount()).To(Equal("8.00"))
+	})
+
+	It("should replace the tiers of the product", func() {
+		Expect(priceListRepo.SetProductTiers(ctx, priceList.ID, product.ID, []domain.PriceTier{tier(10, "9.00"), tier(100, "8.00")})).To(Succeed())
+
+		Expect(priceListRepo.SetProductTiers(ctx, priceList.ID, product.ID, []domain.PriceTier{tier(50, "8.50")})).To(Succeed())
+
+		tiers, err := priceListRepo.ListProductTiers(ctx, priceList.ID, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tiers).To(HaveLen(1))
+		Expect(tiers[0].MinQuantity).To(Equal(50))
+	})
+
+	It("should reject tiers in a currency other than the product's", func() {
+		price := domain.Money{Minor: 900, Currency: "USD"}
+
+		err := priceListRepo.SetProductTiers(ctx, priceList.ID, product.ID, []domain.PriceTier{{MinQuantity: 10, UnitPrice: price}})
+
+		Expect(errors.Is(err, domain.ErrCurrencyMismatch)).To(BeTrue())
+	})
+
+	It("should reject a second price list for the same customer group", func() {
+		now := time.Now().UTC()
+		err := priceListRepo.CreatePriceList(ctx, &domain.PriceList{ID: uuid.New(), Name: "Outra", CustomerGroup: "grossista", CreatedAt: now, UpdatedAt: now})
+
+		Expect(errors.Is(err, domain.ErrPriceListGroupTaken)).To(BeTrue())
+	})
+})
@@ -0,0 +1,382 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PriceRepository interface {
+	ListPriceHistory(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.PriceChangePage, error)
+	CreateSchedule(ctx context.Context, schedule *domain.PriceSchedule) error
+	ListSchedules(ctx context.Context, productID uuid.UUID) ([]*domain.PriceSchedule, error)
+	// CancelSchedule cancela um agendamento pendente ou, se já estiver aplicado, repõe o preço anterior.
+	CancelSchedule(ctx context.Context, productID, scheduleID uuid.UUID) (*domain.PriceSchedule, error)
+	// ApplyDueSchedules repõe os preços dos agendamentos que terminaram até now e
+	// aplica os que começaram, devolvendo quantos agendamentos mudaram de estado.
+	ApplyDueSchedules(ctx context.Context, now time.Time) (int, error)
+}
+
+type postgresPriceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPrice(db *pgxpool.Pool) PriceRepository {
+	return &postgresPriceRepository{db: db}
+}
+
+// nullableMoney monta um valor opcional a partir de colunas de preço e moeda que podem ser NULL.
+func nullableMoney(amount, currency *string) (*domain.Money, error) {
+	if amount == nil || currency == nil {
+		return nil, nil
+	}
+	money, err := domain.ParseMoney(*amount, *currency)
+	if err != nil {
+		return nil, err
+	}
+	return &money, nil
+}
+
+// moneyColumns devolve o valor e a moeda como argumentos de uma consulta, ou NULL para nil.
+func moneyColumns(money *domain.Money) (*string, *string) {
+	if money == nil {
+		return nil, nil
+	}
+	amount := money.Amount()
+	return &amount, &money.Currency
+}
+
+// lockProductPrice bloqueia a linha do produto e devolve o preço atual. Um
+// produto removido não tem preço a alterar.
+func lockProductPrice(ctx context.Context, tx dbtx, productID uuid.UUID) (domain.Money, error) {
+	var amount, currency string
+	err := tx.QueryRow(ctx, `SELECT price::text, currency FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, productID).Scan(&amount, &currency)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Money{}, domain.ErrProductNotFound
+		}
+		return domain.Money{}, err
+	}
+	return domain.ParseMoney(amount, currency)
+}
+
+// recordPriceChange regista o novo preço do produto, exceto quando é igual ao anterior.
+func recordPriceChange(ctx context.Context, tx dbtx, productID uuid.UUID, previous *domain.Money, price domain.Money, reason domain.PriceChangeReason, scheduleID *uuid.UUID) error {
+	if previous != nil && *previous == price {
+		return nil
+	}
+
+	previousAmount, previousCurrency := moneyColumns(previous)
+	query := `INSERT INTO price_history (id, product_id, price, currency, previous_price, previous_currency, reason, schedule_id, actor_id, correlation_id, created_at)
+		VALUES ($1, $2, $3::text::numeric, $4, $5::text::numeric, $6, $7, $8, NULLIF($9, ''), NULLIF($10, ''), $11)`
+	_, err := tx.Exec(ctx, query, uuid.New(), productID, price.Amount(), price.Currency, previousAmount, previousCurrency, reason, scheduleID,
+		domain.UserIDFromContext(ctx), domain.CorrelationIDFromContext(ctx), time.Now().UTC())
+	return err
+}
+
+// setProductPrice muda o preço do produto e regista a alteração no histórico.
+func setProductPrice(ctx context.Context, tx dbtx, productID uuid.UUID, previous, price domain.Money, reason domain.PriceChangeReason, scheduleID *uuid.UUID) error {
+	query := `UPDATE products SET price = $1::text::numeric, currency = $2, updated_at = NOW(), version = version + 1 WHERE id = $3`
+	if _, err := tx.Exec(ctx, query, price.Amount(), price.Currency, productID); err != nil {
+		return err
+	}
+	return recordPriceChange(ctx, tx, productID, &previous, price, reason, scheduleID)
+}
+
+func (r *postgresPriceRepository) ListPriceHistory(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.PriceChangePage, error) {
+
+	query := `SELECT id, product_id, price::text, currency, previous_price::text, previous_currency, reason, schedule_id,
+		COALESCE(actor_id, ''), COALESCE(correlation_id, ''), created_at FROM price_history WHERE product_id = $1`
+	args := []any{productID}
+	if cursor != "" {
+		createdAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND (created_at, id) < ($2, $3)`
+		args = append(args, createdAt, id)
+	}
+	// Busca um registo a mais para saber se existe uma próxima página.
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT %d`, limit+1)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing price history: %w", domain.ErrToListPriceHistory)
+	}
+	defer rows.Close()
+
+	page := &domain.PriceChangePage{Items: make([]*domain.PriceChange, 0, limit)}
+	for rows.Next() {
+		change := &domain.PriceChange{}
+		var amount, currency string
+		var previousAmount, previousCurrency *string
+		err := rows.Scan(&change.ID, &change.ProductID, &amount, &currency, &previousAmount, &previousCurrency, &change.Reason, &change.ScheduleID,
+			&change.ActorID, &change.CorrelationID, &change.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning price history row: %w", err)
+		}
+		if change.Price, err = domain.ParseMoney(amount, currency); err != nil {
+			return nil, fmt.Errorf("error scanning price history row: %w", err)
+		}
+		if change.PreviousPrice, err = nullableMoney(previousAmount, previousCurrency); err != nil {
+			return nil, fmt.Errorf("error scanning price history row: %w", err)
+		}
+		page.Items = append(page.Items, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when listing price history: %w", domain.ErrToListPriceHistory)
+	}
+
+	if len(page.Items) > limit {
+		last := page.Items[limit-1]
+		page.Items = page.Items[:limit]
+		page.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+	return page, nil
+}
+
+const scheduleColumns = `id, product_id, price::text, currency, effective_from, effective_to, status,
+	previous_price::text, previous_currency, created_at, updated_at`
+
+func scanSchedule(row pgx.Row) (*domain.PriceSchedule, error) {
+	schedule := &domain.PriceSchedule{}
+	var amount, currency string
+	var previousAmount, previousCurrency *string
+	err := row.Scan(&schedule.ID, &schedule.ProductID, &amount, &currency, &schedule.EffectiveFrom, &schedule.EffectiveTo, &schedule.Status,
+		&previousAmount, &previousCurrency, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Price, err = domain.ParseMoney(amount, currency); err != nil {
+		return nil, err
+	}
+	if schedule.PreviousPrice, err = nullableMoney(previousAmount, previousCurrency); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func collectSchedules(rows pgx.Rows) ([]*domain.PriceSchedule, error) {
+	defer rows.Close()
+
+	schedules := make([]*domain.PriceSchedule, 0)
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (r *postgresPriceRepository) CreateSchedule(ctx context.Context, schedule *domain.PriceSchedule) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		// Bloqueia o produto para que dois agendamentos sobrepostos não sejam criados em simultâneo.
+		var currency string
+		err := tx.QueryRow(ctx, `SELECT currency FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, schedule.ProductID).Scan(&currency)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+		if schedule.Price.Currency != currency {
+			return fmt.Errorf("%w: scheduled price must be in %s", domain.ErrCurrencyMismatch, currency)
+		}
+
+		var overlaps bool
+		query := `SELECT EXISTS (SELECT 1 FROM price_schedules WHERE product_id = $1 AND status IN ('pending', 'active')
+			AND tstzrange(effective_from, effective_to) && tstzrange($2, $3))`
+		if err := tx.QueryRow(ctx, query, schedule.ProductID, schedule.EffectiveFrom, schedule.EffectiveTo).Scan(&overlaps); err != nil {
+			return err
+		}
+		if overlaps {
+			return domain.ErrPriceScheduleOverlap
+		}
+
+		query = `INSERT INTO price_schedules (id, product_id, price, currency, effective_from, effective_to, status, created_at, updated_at)
+			VALUES ($1, $2, $3::text::numeric, $4, $5, $6, $7, $8, $9)`
+		_, err = tx.Exec(ctx, query, schedule.ID, schedule.ProductID, schedule.Price.Amount(), schedule.Price.Currency, schedule.EffectiveFrom, schedule.EffectiveTo,
+			schedule.Status, schedule.CreatedAt, schedule.UpdatedAt)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrCurrencyMismatch) || errors.Is(err, domain.ErrPriceScheduleOverlap) {
+			return fmt.Errorf("Error creating price schedule: %w", err)
+		}
+		return fmt.Errorf("Error creating price schedule: %w", domain.ErrToSavePriceSchedule)
+	}
+	return nil
+}
+
+func (r *postgresPriceRepository) ListSchedules(ctx context.Context, productID uuid.UUID) ([]*domain.PriceSchedule, error) {
+
+	rows, err := r.db.Query(ctx, `SELECT `+scheduleColumns+` FROM price_schedules WHERE product_id = $1 ORDER BY effective_from DESC`, productID)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing price schedules: %w", err)
+	}
+	schedules, err := collectSchedules(rows)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing price schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// startSchedule aplica o preço agendado, guardando o preço substituído para o repor no fim.
+func startSchedule(ctx context.Context, tx dbtx, schedule *domain.PriceSchedule) error {
+	previous, err := lockProductPrice(ctx, tx, schedule.ProductID)
+	if err != nil {
+		return err
+	}
+	if err := setProductPrice(ctx, tx, schedule.ProductID, previous, schedule.Price, domain.PriceChangeScheduleStart, &schedule.ID); err != nil {
+		return err
+	}
+
+	// Sem fim, não há preço a repor e o agendamento fica concluído.
+	status := domain.ScheduleActive
+	if schedule.EffectiveTo == nil {
+		status = domain.ScheduleCompleted
+	}
+	previousAmount, previousCurrency := moneyColumns(&previous)
+	query := `UPDATE price_schedules SET status = $1, previous_price = $2::text::numeric, previous_currency = $3, updated_at = NOW() WHERE id = $4`
+	_, err = tx.Exec(ctx, query, status, previousAmount, previousCurrency, schedule.ID)
+	return err
+}
+
+// endSchedule repõe o preço anterior a um agendamento aplicado. Se o preço foi
+// alterado entretanto, a alteração manual prevalece e o preço não é reposto.
+func endSchedule(ctx context.Context, tx dbtx, schedule *domain.PriceSchedule, reason domain.PriceChangeReason, status domain.PriceScheduleStatus) error {
+	current, err := lockProductPrice(ctx, tx, schedule.ProductID)
+	if err != nil {
+		return err
+	}
+	if schedule.PreviousPrice != nil && current == schedule.Price {
+		if err := setProductPrice(ctx, tx, schedule.ProductID, current, *schedule.PreviousPrice, reason, &schedule.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE price_schedules SET status = $1, updated_at = NOW() WHERE id = $2`, status, schedule.ID)
+	return err
+}
+
+func (r *postgresPriceRepository) CancelSchedule(ctx context.Context, productID, scheduleID uuid.UUID) (*domain.PriceSchedule, error) {
+
+	var schedule *domain.PriceSchedule
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		query := `SELECT ` + scheduleColumns + ` FROM price_schedules WHERE id = $1 AND product_id = $2 FOR UPDATE`
+		schedule, err = scanSchedule(tx.QueryRow(ctx, query, scheduleID, productID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrPriceScheduleNotFound
+			}
+			return err
+		}
+
+		switch schedule.Status {
+		case domain.SchedulePending:
+			_, err = tx.Exec(ctx, `UPDATE price_schedules SET status = $1, updated_at = NOW() WHERE id = $2`, domain.ScheduleCancelled, scheduleID)
+		case domain.ScheduleActive:
+			err = endSchedule(ctx, tx, schedule, domain.PriceChangeScheduleCancel, domain.ScheduleCancelled)
+		default:
+			return domain.ErrPriceScheduleClosed
+		}
+		if err != nil {
+			return err
+		}
+
+		schedule, err = scanSchedule(tx.QueryRow(ctx, `SELECT `+scheduleColumns+` FROM price_schedules WHERE id = $1`, scheduleID))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrPriceScheduleNotFound) || errors.Is(err, domain.ErrPriceScheduleClosed) || errors.Is(err, domain.ErrProductNotFound) {
+			return nil, fmt.Errorf("Error cancelling price schedule: %w", err)
+		}
+		return nil, fmt.Errorf("Error cancelling price schedule: %w", domain.ErrToSavePriceSchedule)
+	}
+	return schedule, nil
+}
+
+func (r *postgresPriceRepository) ApplyDueSchedules(ctx context.Context, now time.Time) (int, error) {
+
+	// Os agendamentos que terminam são tratados primeiro, para que um
+	// agendamento que começa no mesmo instante substitua o preço reposto.
+	query := `SELECT id FROM (
+			SELECT id, 0 AS phase, effective_to AS due FROM price_schedules WHERE status = 'active' AND effective_to <= $1
+			UNION ALL
+			SELECT id, 1, effective_from FROM price_schedules WHERE status = 'pending' AND effective_from <= $1
+		) due ORDER BY phase, due`
+	rows, err := r.db.Query(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("Error when applying scheduled prices: %w", domain.ErrToApplyScheduledPrices)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return 0, fmt.Errorf("Error when applying scheduled prices: %w", domain.ErrToApplyScheduledPrices)
+	}
+
+	// Cada agendamento tem a sua transação, para que a falha de um não impeça os restantes.
+	applied, failed := 0, 0
+	for _, id := range ids {
+		changed, err := r.applySchedule(ctx, id, now)
+		switch {
+		case err != nil:
+			failed++
+		case changed:
+			applied++
+		}
+	}
+	if failed > 0 {
+		return applied, fmt.Errorf("Error when applying scheduled prices: %d schedule(s) failed: %w", failed, domain.ErrToApplyScheduledPrices)
+	}
+	return applied, nil
+}
+
+// applySchedule aplica ou repõe o agendamento que chegou ao seu início ou fim.
+// Devolve false se outra instância já o tratou. Os agendamentos de produtos
+// removidos são cancelados sem alterar o preço.
+func (r *postgresPriceRepository) applySchedule(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+
+	var changed bool
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		schedule, err := scanSchedule(tx.QueryRow(ctx, `SELECT `+scheduleColumns+` FROM price_schedules WHERE id = $1 FOR UPDATE SKIP LOCKED`, id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case schedule.Status == domain.ScheduleActive && schedule.EffectiveTo != nil && !schedule.EffectiveTo.After(now):
+			err = endSchedule(ctx, tx, schedule, domain.PriceChangeScheduleEnd, domain.ScheduleCompleted)
+		case schedule.Status == domain.SchedulePending && !schedule.EffectiveFrom.After(now):
+			// Um agendamento cuja janela já passou (ex: serviço parado) não chega a ser aplicado.
+			if schedule.EffectiveTo != nil && !schedule.EffectiveTo.After(now) {
+				_, err = tx.Exec(ctx, `UPDATE price_schedules SET status = $1, updated_at = NOW() WHERE id = $2`, domain.ScheduleCompleted, schedule.ID)
+			} else {
+				err = startSchedule(ctx, tx, schedule)
+			}
+		default:
+			return nil
+		}
+		if errors.Is(err, domain.ErrProductNotFound) {
+			_, err = tx.Exec(ctx, `UPDATE price_schedules SET status = $1, updated_at = NOW() WHERE id = $2`, domain.ScheduleCancelled, schedule.ID)
+		}
+		if err != nil {
+			return err
+		}
+		changed = true
+		return nil
+	})
+	return changed, err
+}
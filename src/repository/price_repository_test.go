@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prices", func() {
+	var productRepo ProductRepository
+	var priceRepo PriceRepository
+	var ctx context.Context
+	var product *domain.Product
+
+	newSchedule := func(amount string, from time.Time, to *time.Time) *domain.PriceSchedule {
+		price, err := domain.ParseMoney(amount, "BRL")
+		Expect(err).NotTo(HaveOccurred())
+		now := time.Now().UTC()
+		return &domain.PriceSchedule{ID: uuid.New(), ProductID: product.ID, Price: price, EffectiveFrom: from, EffectiveTo: to,
+			Status: domain.SchedulePending, CreatedAt: now, UpdatedAt: now}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		priceRepo = NewPrice(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+
+		product = stubs.NewProductStub().WithPrice("100.00").Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+	})
+
+	It("should record every price change in the history", func() {
+		// Arrange: Altera o preço e depois só o nome
+		product.Price, _ = domain.ParseMoney("90.00", "BRL")
+		Expect(productRepo.Update(ctx, product)).To(Succeed())
+		product.Name = "Outro nome"
+		Expect(productRepo.Update(ctx, product)).To(Succeed())
+
+		// Act: Lista o histórico de preços
+		page, err := priceRepo.ListPriceHistory(ctx, product.ID, 10, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Assert: Só as alterações de preço ficam registadas
+		Expect(page.Items).To(HaveLen(2))
+		Expect(page.Items[0].Reason).To(Equal(domain.PriceChangeUpdate))
+		Expect(page.Items[0].Price.Amount()).To(Equal("90.00"))
+		Expect(page.Items[0].PreviousPrice.Amount()).To(Equal("100.00"))
+		Expect(page.Items[1].Reason).To(Equal(domain.PriceChangeInitial))
+	})
+
+	It("should keep the price history and the revisions after the product is purged", func() {
+		// Arrange: Altera o preço e remove o produto
+		product.Price, _ = domain.ParseMoney("90.00", "BRL")
+		Expect(productRepo.Update(ctx, product)).To(Succeed())
+		Expect(productRepo.Delete(ctx, product.ID, product.Version)).To(Succeed())
+
+		// Act: Purga o produto
+		purged, _, err := productRepo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(Equal(int64(1)))
+
+		// Assert: O histórico continua lá e não pode ser apagado
+		page, err := priceRepo.ListPriceHistory(ctx, product.ID, 10, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items).To(HaveLen(2))
+		revisions, err := productRepo.ListRevisions(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revisions).To(HaveLen(3))
+		_, err = db.Exec(ctx, `DELETE FROM price_history WHERE product_id = $1`, product.ID)
+		Expect(err).To(HaveOccurred())
+		_, err = db.Exec(ctx, `DELETE FROM product_revisions WHERE product_id = $1`, product.ID)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should apply a scheduled price at its start and revert it at its end", func() {
+		// Arrange: Agenda uma promoção de um dia
+		start := time.Now().UTC().Add(time.Hour)
+		end := start.Add(24 * time.Hour)
+		Expect(priceRepo.CreateSchedule(ctx, newSchedule("79.90", start, &end))).To(Succeed())
+
+		// Act & Assert: Antes do início nada muda
+		applied, err := priceRepo.ApplyDueSchedules(ctx, start.Add(-time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(Equal(0))
+
+		// Act & Assert: No início o preço promocional é aplicado
+		applied, err = priceRepo.ApplyDueSchedules(ctx, start)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(Equal(1))
+		current, err := productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current.Price.Amount()).To(Equal("79.90"))
+
+		// Act & Assert: No fim o preço anterior é reposto
+		_, err = priceRepo.ApplyDueSchedules(ctx, end)
+		Expect(err).NotTo(HaveOccurred())
+		current, err = productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current.Price.Amount()).To(Equal("100.00"))
+
+		schedules, err := priceRepo.ListSchedules(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schedules[0].Status).To(Equal(domain.ScheduleCompleted))
+		page, err := priceRepo.ListPriceHistory(ctx, product.ID, 10, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items[0].Reason).To(Equal(domain.PriceChangeScheduleEnd))
+		Expect(page.Items[1].Reason).To(Equal(domain.PriceChangeScheduleStart))
+	})
+
+	It("should cancel the schedules of deleted products and still apply the others", func() {
+		// Arrange: Agenda um preço para dois produtos e remove o primeiro
+		start := time.Now().UTC().Add(time.Hour)
+		deletedSchedule := newSchedule("79.90", start, nil)
+		Expect(priceRepo.CreateSchedule(ctx, deletedSchedule)).To(Succeed())
+		other := stubs.NewProductStub().WithPrice("50.00").Get()
+		Expect(productRepo.Create(ctx, other)).To(Succeed())
+		otherSchedule := newSchedule("45.00", start, nil)
+		otherSchedule.ProductID = other.ID
+		Expect(priceRepo.CreateSchedule(ctx, otherSchedule)).To(Succeed())
+		Expect(productRepo.Delete(ctx, product.ID, 1)).To(Succeed())
+
+		// Act
+		applied, err := priceRepo.ApplyDueSchedules(ctx, start)
+
+		// Assert: O agendamento do produto removido é cancelado sem mexer no preço
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(Equal(2))
+		schedules, err := priceRepo.ListSchedules(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schedules[0].Status).To(Equal(domain.ScheduleCancelled))
+		var price string
+		Expect(db.QueryRow(ctx, `SELECT price::text FROM products WHERE id = $1`, product.ID).Scan(&price)).To(Succeed())
+		Expect(price).To(HavePrefix("100.00"))
+		current, err := productRepo.GetProductByID(ctx, other.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current.Price.Amount()).To(Equal("45.00"))
+	})
+
+	It("should reject overlapping schedules and cancel pending ones", func() {
+		// Arrange: Agenda uma promoção
+		start := time.Now().UTC().Add(time.Hour)
+		end := start.Add(24 * time.Hour)
+		schedule := newSchedule("79.90", start, &end)
+		Expect(priceRepo.CreateSchedule(ctx, schedule)).To(Succeed())
+
+		// Act: Tenta agendar outra mudança permanente durante a promoção
+		err := priceRepo.CreateSchedule(ctx, newSchedule("89.90", start.Add(time.Hour), nil))
+
+		// Assert: A sobreposição é rejeitada e a promoção pendente pode ser cancelada uma vez
+		Expect(errors.Is(err, domain.ErrPriceScheduleOverlap)).To(BeTrue())
+		cancelled, err := priceRepo.CancelSchedule(ctx, product.ID, schedule.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cancelled.Status).To(Equal(domain.ScheduleCancelled))
+		_, err = priceRepo.CancelSchedule(ctx, product.ID, schedule.ID)
+		Expect(errors.Is(err, domain.ErrPriceScheduleClosed)).To(BeTrue())
+	})
+})
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// facetableAttributesQuery devolve os atributos com faceta: os de tipo número,
+// booleano ou lista das categorias acima e abaixo da categoria pedida, ou de
+// todas as categorias. Os atributos de texto livre não se agrupam em valores.
+func facetableAttributesQuery(query domain.ProductQuery, arg func(value any) string) string {
+	if query.CategoryID == nil {
+		return `SELECT name FROM category_attributes WHERE type <> 'text'`
+	}
+	category := arg(*query.CategoryID)
+	return `WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM categories WHERE id = ` + category + `
+			UNION
+			SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+		), descendants AS (
+			SELECT id FROM categories WHERE id = ` + category + `
+			UNION
+			SELECT c.id FROM categories c JOIN descendants d ON c.parent_id = d.id
+		)
+		SELECT name FROM category_attributes WHERE type <> 'text'
+			AND (category_id IN (SELECT id FROM ancestors) OR category_id IN (SELECT id FROM descendants))`
+}
+
+// productFacets conta os produtos da listagem por valor de atributo e por
+// faixa de preço numa só consulta. Os produtos que respeitam os restantes
+// filtros são lidos uma vez, com uma coluna por filtro de atributo e outra
+// para o intervalo de preço, e cada faceta ignora o seu próprio filtro.
+func (r *postgresProductRepository) productFacets(ctx context.Context, query domain.ProductQuery) (*domain.ProductFacets, error) {
+	args := make([]any, 0)
+	arg := argAppender(&args)
+
+	base := query
+	base.MinPrice, base.MaxPrice, base.Attributes = nil, nil, nil
+	where := productFilters(base, &args)
+
+	price, currency := "p.price", "p.currency"
+	if query.Currency != "" {
+		price = priceInCurrencyExpr(arg(query.Currency), query.Currency)
+		currency = arg(query.Currency) + "::text"
+	}
+	inPriceRange := "TRUE"
+	if conditions := priceRangeConditions(query, arg); len(conditions) > 0 {
+		inPriceRange = strings.Join(conditions, " AND ")
+	}
+
+	names := sortedAttributeNames(query.Attributes)
+	columns := make([]string, 0, len(names))
+	for i, name := range names {
+		columns = append(columns, fmt.Sprintf(", %s AS f%d", attributeCondition(name, query.Attributes[name], arg), i))
+	}
+	// matches devolve a condição dos filtros de atributo, exceto o de skip.
+	matches := func(skip int) string {
+		conditions := []string{"TRUE"}
+		for i := range names {
+			if i != skip {
+				conditions = append(conditions, fmt.Sprintf("f.f%d", i))
+			}
+		}
+		return strings.Join(conditions, " AND ")
+	}
+	attributeMatches := "f.in_price_range AND " + matches(-1)
+	if len(names) > 0 {
+		cases := make([]string, 0, len(names))
+		for i, name := range names {
+			cases = append(cases, fmt.Sprintf("WHEN %s THEN %s", arg(name), matches(i)))
+		}
+		attributeMatches = fmt.Sprintf("f.in_price_range AND CASE a.key %s ELSE %s END", strings.Join(cases, " "), matches(-1))
+	}
+
+	sql := fmt.Sprintf(`WITH filtered AS (
+			SELECT p.attributes, %s AS price, %s AS currency, %s AS in_price_range%s FROM products p WHERE %s
+		)
+		SELECT 'attribute' AS kind, a.key, a.value, COUNT(*) FROM filtered f CROSS JOIN LATERAL jsonb_each_text(f.attributes) a
+		WHERE a.value IS NOT NULL AND a.key IN (%s) AND %s
+		GROUP BY a.key, a.value
+		UNION ALL
+		SELECT 'price', f.currency, FLOOR(f.price / %s::integer)::bigint::text, COUNT(*) FROM filtered f
+		WHERE f.price IS NOT NULL AND %s
+		GROUP BY 2, 3
+		ORDER BY 1, 2, 4 DESC, 3`,
+		price, currency, inPriceRange, strings.Join(columns, ""), where,
+		facetableAttributesQuery(query, arg), attributeMatches, arg(query.PriceInterval), matches(-1))
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facets := &domain.ProductFacets{Attributes: []domain.AttributeFacet{}, Prices: []domain.PriceFacet{}}
+	for rows.Next() {
+		var kind, key, value string
+		var count int64
+		if err := rows.Scan(&kind, &key, &value, &count); err != nil {
+			return nil, err
+		}
+		if kind == "attribute" {
+			if last := len(facets.Attributes) - 1; last < 0 || facets.Attributes[last].Name != key {
+				facets.Attributes = append(facets.Attributes, domain.AttributeFacet{Name: key})
+			}
+			last := &facets.Attributes[len(facets.Attributes)-1]
+			last.Values = append(last.Values, domain.FacetValue{Value: value, Count: count})
+			continue
+		}
+
+		bucket, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		interval := int64(query.PriceInterval)
+		min, err := domain.ParseMoney(strconv.FormatInt(bucket*interval, 10), key)
+		if err != nil {
+			return nil, err
+		}
+		max, err := domain.ParseMoney(strconv.FormatInt((bucket+1)*interval, 10), key)
+		if err != nil {
+			return nil, err
+		}
+		facets.Prices = append(facets.Prices, domain.PriceFacet{Min: min, Max: max, Count: count})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// As faixas de preço vêm ordenadas por contagem; a loja mostra-as por valor.
+	sort.SliceStable(facets.Prices, func(i, j int) bool {
+		if facets.Prices[i].Min.Currency != facets.Prices[j].Min.Currency {
+			return facets.Prices[i].Min.Currency < facets.Prices[j].Min.Currency
+		}
+		return facets.Prices[i].Min.Minor < facets.Prices[j].Min.Minor
+	})
+	return facets, nil
+}
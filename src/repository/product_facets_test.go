@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Product facets", func() {
+	var productRepo ProductRepository
+	var ctx context.Context
+	var category *domain.Category
+
+	create := func(price, brand, colour string) {
+		product := stubs.NewProductStub().WithPrice(price).Get()
+		product.Attributes = domain.Attributes{"marca": brand, "cor": colour, "descricao": "caneca " + colour}
+		Expect(productRepo.Create(ctx, product, category.ID)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		categoryRepo := NewCategory(db)
+		attributeRepo := NewAttribute(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products, categories RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+
+		now := time.Now().UTC()
+		category = &domain.Category{ID: uuid.New(), Name: "Canecas", Slug: "canecas", CreatedAt: now, UpdatedAt: now}
+		Expect(categoryRepo.Create(ctx, category)).To(Succeed())
+		for _, definition := range []domain.AttributeDefinition{
+			{CategoryID: category.ID, Name: "marca", Type: domain.AttributeEnum, AllowedValues: []string{"Acme", "Globex"}, UpdatedAt: now},
+			{CategoryID: category.ID, Name: "cor", Type: domain.AttributeEnum, AllowedValues: []string{"vermelho", "azul"}, UpdatedAt: now},
+			{CategoryID: category.ID, Name: "descricao", Type: domain.AttributeText, UpdatedAt: now},
+		} {
+			Expect(attributeRepo.SetDefinition(ctx, &definition)).To(Succeed())
+		}
+
+		create("10.00", "Acme", "vermelho")
+		create("20.00", "Acme", "azul")
+		create("60.00", "Acme", "vermelho")
+		create("30.00", "Globex", "vermelho")
+	})
+
+	It("should count every value of an attribute ignoring its own filter", func() {
+		page, err := productRepo.ListProducts(ctx, domain.ProductQuery{
+			CategoryID: &category.ID, SortBy: domain.SortByCreatedAt, Limit: 10,
+			Attributes: map[string][]string{"marca": {"Acme"}}, IncludeFacets: true, PriceInterval: 50,
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items).To(HaveLen(3))
+		Expect(page.Facets.Attributes).To(Equal([]domain.AttributeFacet{
+			{Name: "cor", Values: []domain.FacetValue{{Value: "vermelho", Count: 2}, {Value: "azul", Count: 1}}},
+			{Name: "marca", Values: []domain.FacetValue{{Value: "Acme", Count: 3}, {Value: "Globex", Count: 1}}},
+		}))
+		Expect(page.Facets.Prices).To(HaveLen(2))
+		Expect(page.Facets.Prices[0].Min.Amount()).To(Equal("0.00"))
+		Expect(page.Facets.Prices[0].Max.Amount()).To(Equal("50.00"))
+		Expect(page.Facets.Prices[0].Count).To(Equal(int64(2)))
+		Expect(page.Facets.Prices[1].Count).To(Equal(int64(1)))
+	})
+
+	It("should apply the price range to the attribute counts but not to the price buckets", func() {
+		maxPrice := domain.Money{Minor: 2500, Currency: "BRL"}
+		page, err := productRepo.ListProducts(ctx, domain.ProductQuery{
+			CategoryID: &category.ID, SortBy: domain.SortByCreatedAt, Limit: 10,
+			MaxPrice: &maxPrice, IncludeFacets: true, PriceInterval: 50,
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items).To(HaveLen(2))
+		Expect(page.Facets.Attributes[1]).To(Equal(domain.AttributeFacet{Name: "marca", Values: []domain.FacetValue{{Value: "Acme", Count: 2}}}))
+		Expect(page.Facets.Prices).To(HaveLen(2))
+		Expect(page.Facets.Prices[0].Count).To(Equal(int64(3)))
+	})
+})
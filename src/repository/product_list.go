@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+// productSortKey descreve como ordenar e paginar por um campo: a coluna, o
+// tipo para converter o valor do cursor e o valor desse campo num produto.
+type productSortKey struct {
+	column string
+	cast   string
+	value  func(p *domain.Product) string
+}
+
+var productSortKeys = map[domain.ProductSortField]productSortKey{
+	domain.SortByCreatedAt: {"p.created_at", "timestamptz", func(p *domain.Product) string { return p.CreatedAt.UTC().Format(time.RFC3339Nano) }},
+	domain.SortByUpdatedAt: {"p.updated_at", "timestamptz", func(p *domain.Product) string { return p.UpdatedAt.UTC().Format(time.RFC3339Nano) }},
+	domain.SortByPrice:     {"p.price", "numeric", func(p *domain.Product) string { return p.Price.Amount() }},
+	domain.SortByName:      {"p.name", "text", func(p *domain.Product) string { return p.Name }},
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// argAppender devolve uma função que acrescenta um valor a args e devolve o seu marcador.
+func argAppender(args *[]any) func(value any) string {
+	return func(value any) string {
+		*args = append(*args, value)
+		return fmt.Sprintf("$%d", len(*args))
+	}
+}
+
+// productFilters monta a cláusula WHERE dos filtros da listagem, acrescentando os valores a args.
+func productFilters(query domain.ProductQuery, args *[]any) string {
+	arg := argAppender(args)
+
+	conditions := []string{"p.deleted_at IS NULL"}
+	if query.Deleted {
+		conditions[0] = "p.deleted_at IS NOT NULL"
+	}
+	if query.Currency != "" {
+		conditions = append(conditions, priceInCurrencyExpr(arg(query.Currency), query.Currency)+" IS NOT NULL")
+	}
+	conditions = append(conditions, priceRangeConditions(query, arg)...)
+	for _, name := range sortedAttributeNames(query.Attributes) {
+		conditions = append(conditions, attributeCondition(name, query.Attributes[name], arg))
+	}
+	if query.OnSaleOnly {
+		conditions = append(conditions, onSaleExpr)
+	}
+	if query.InStockOnly {
+		conditions = append(conditions, availableStockExpr+" > 0")
+	}
+	if query.CreatedAfter != nil {
+		conditions = append(conditions, "p.created_at > "+arg(*query.CreatedAfter))
+	}
+	if query.UpdatedAfter != nil {
+		conditions = append(conditions, "p.updated_at > "+arg(*query.UpdatedAfter))
+	}
+	if query.NameContains != "" {
+		conditions = append(conditions, "p.name ILIKE '%' || "+arg(likeEscaper.Replace(query.NameContains))+" || '%'")
+	}
+	if query.Status != "" {
+		conditions = append(conditions, "p.status = "+arg(query.Status))
+	}
+	if query.CategoryID != nil {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM product_categories pc WHERE pc.product_id = p.id AND pc.category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = `+arg(*query.CategoryID)+`
+				UNION ALL
+				SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+			)
+			SELECT id FROM tree))`)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// priceRangeConditions devolve as condições do intervalo de preço da listagem.
+func priceRangeConditions(query domain.ProductQuery, arg func(value any) string) []string {
+	var conditions []string
+	if query.Currency != "" {
+		// O intervalo de preço compara o preço já na moeda pedida.
+		price := priceInCurrencyExpr(arg(query.Currency), query.Currency)
+		if query.MinPrice != nil {
+			conditions = append(conditions, price+" >= "+arg(query.MinPrice.Amount())+"::text::numeric")
+		}
+		if query.MaxPrice != nil {
+			conditions = append(conditions, price+" <= "+arg(query.MaxPrice.Amount())+"::text::numeric")
+		}
+		return conditions
+	}
+	// Sem moeda pedida, um intervalo de preço só inclui produtos na moeda do intervalo.
+	if query.MinPrice != nil {
+		conditions = append(conditions, "p.price >= "+arg(query.MinPrice.Amount())+"::text::numeric", "p.currency = "+arg(query.MinPrice.Currency))
+	}
+	if query.MaxPrice != nil {
+		conditions = append(conditions, "p.price <= "+arg(query.MaxPrice.Amount())+"::text::numeric", "p.currency = "+arg(query.MaxPrice.Currency))
+	}
+	return conditions
+}
+
+// attributeCondition aceita os produtos com um dos valores indicados no
+// atributo. Os valores comparam-se como texto, pelo que 220 e true
+// correspondem ao número 220 e ao booleano true.
+func attributeCondition(name string, values []string, arg func(value any) string) string {
+	return "p.attributes ->> " + arg(name) + " = ANY(" + arg(values) + "::text[])"
+}
+
+func sortedAttributeNames(attributes map[string][]string) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *postgresProductRepository) ListProducts(ctx context.Context, query domain.ProductQuery) (*domain.ProductPage, error) {
+
+	key, ok := productSortKeys[query.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("Error when searching for all products: %w", domain.ErrInvalidSortField)
+	}
+	direction, comparison := "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	args := make([]any, 0)
+	where := productFilters(query, &args)
+	page := &domain.ProductPage{Items: make([]*domain.Product, 0, query.Limit)}
+
+	if query.IncludeTotal {
+		var total int64
+		err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM products p WHERE `+where, args...).Scan(&total)
+		if err != nil {
+			return nil, fmt.Errorf("Error when counting products: %w", domain.ErrNotFoundProducts)
+		}
+		page.Total = &total
+	}
+	if query.IncludeFacets {
+		facets, err := r.productFacets(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("Error when counting product facets: %w", domain.ErrNotFoundProducts)
+		}
+		page.Facets = facets
+	}
+
+	if query.SortBy == domain.SortByPrice && query.Currency != "" {
+		args = append(args, query.Currency)
+		key.column = priceInCurrencyExpr(fmt.Sprintf("$%d", len(args)), query.Currency)
+	}
+	if query.Cursor != "" {
+		value, id, err := decodeProductCursor(query.Cursor, query.SortBy, query.Descending)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value, id)
+		where += fmt.Sprintf(" AND (%s, p.id) %s ($%d::text::%s, $%d)", key.column, comparison, len(args)-1, key.cast, len(args))
+	}
+	// Busca um registo a mais para saber se existe uma próxima página.
+	sql := fmt.Sprintf(`SELECT %s FROM products p WHERE %s ORDER BY %s %s, p.id %s LIMIT %d`,
+		productColumns, where, key.column, direction, direction, query.Limit+1)
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Error when searching for all products: %w", domain.ErrNotFoundProducts)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning product row: %w", err)
+		}
+		page.Items = append(page.Items, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when searching for all products: %w", domain.ErrNotFoundProducts)
+	}
+	// A conversão antecede o cursor, que guarda o preço já na moeda pedida.
+	if query.Currency != "" {
+		if err := convertProductPrices(ctx, r.db, query.Currency, page.Items); err != nil {
+			return nil, fmt.Errorf("Error when searching for all products: %w", domain.ErrToConvertPrices)
+		}
+	}
+
+	if len(page.Items) > query.Limit {
+		last := page.Items[query.Limit-1]
+		page.Items = page.Items[:query.Limit]
+		page.NextCursor = encodeProductCursor(query.SortBy, query.Descending, key.value(last), last.ID)
+	}
+	return page, nil
+}
@@ -1,49 +1,303 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"product-service/src/domain"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type ProductRepository interface {
-	Create(ctx context.Context, product *domain.Product) error
+	// Create grava o produto e atribui-lhe as categorias indicadas na mesma transação.
+	Create(ctx context.Context, product *domain.Product, categoryIDs ...uuid.UUID) error
 	GetProductByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
-	ListProducts(ctx context.Context) ([]*domain.Product, error)
-	ReduceStock(ctx context.Context, id uuid.UUID, quantity int) error
+	GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error)
+	// GetProductByBarcode compara os códigos como GTIN-14, pelo que um UPC-A encontra o EAN-13 equivalente.
+	GetProductByBarcode(ctx context.Context, barcode string) (*domain.Product, error)
+	ListProducts(ctx context.Context, query domain.ProductQuery) (*domain.ProductPage, error)
+	SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (*domain.ProductSearchPage, error)
+	// ReduceStock retira stock do produto ou, com variantID, apenas dessa variante.
+	ReduceStock(ctx context.Context, id, warehouseID, variantID uuid.UUID, quantity int) error
+	ReduceStockBatch(ctx context.Context, items []domain.StockItem) error
+	IncreaseStock(ctx context.Context, id, warehouseID uuid.UUID, quantity int, reference string) (int, error)
+	SetStock(ctx context.Context, id, warehouseID uuid.UUID, stock int, reason string) (int, error)
+	ListStockMovements(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.StockMovementPage, error)
+	// SetStatus muda o estado do produto se a transição for permitida, devolvendo o produto atualizado.
+	SetStatus(ctx context.Context, id uuid.UUID, status domain.ProductStatus) (*domain.Product, error)
+	// Update só é aplicado se product.Version for a versão atual; em caso de
+	// sucesso, product.Version passa a ter a nova versão.
 	Update(ctx context.Context, product *domain.Product) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Delete marca o produto como removido; a linha só é apagada por PurgeDeletedBefore.
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+	Restore(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+	// PurgeDeletedBefore apaga os produtos removidos antes de before, com as suas imagens, e
+	// devolve quantos apagou e as chaves dos ficheiros das imagens, a remover depois do commit.
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, []string, error)
+	// ListRevisions devolve as revisões do produto da mais recente para a mais antiga.
+	ListRevisions(ctx context.Context, productID uuid.UUID) ([]*domain.ProductRevision, error)
+	GetRevision(ctx context.Context, productID uuid.UUID, number int) (*domain.ProductRevision, error)
+	RevertToRevision(ctx context.Context, productID uuid.UUID, number int, version int64) (*domain.Product, error)
+	// SetSale define a promoção do produto ou, com sale a nil, remove-a.
+	SetSale(ctx context.Context, id uuid.UUID, sale *domain.Sale) (*domain.Product, error)
+	// ConvertPrices mostra os preços dos produtos na moeda indicada, pelo preço explícito ou pela taxa de câmbio.
+	ConvertPrices(ctx context.Context, currency string, products ...*domain.Product) error
+}
+
+// availableStockExpr calcula o stock disponível descontando as reservas ativas e não expiradas.
+// Num kit é o número de unidades que o stock disponível de cada componente
+// permite montar; um componente removido ou arquivado não permite montar nenhuma.
+const availableStockExpr = `(CASE WHEN p.type = 'bundle' THEN COALESCE((SELECT MIN(CASE WHEN c.deleted_at IS NULL AND c.status <> 'archived'
+		THEN GREATEST(c.stock - COALESCE((SELECT SUM(r.quantity) FROM stock_reservations r WHERE r.product_id = c.id AND r.status = 'active' AND r.expires_at > NOW()), 0), 0) / bc.quantity
+		ELSE 0 END) FROM bundle_components bc JOIN products c ON c.id = bc.component_id WHERE bc.bundle_id = p.id), 0)
+	ELSE p.stock - COALESCE((SELECT SUM(r.quantity) FROM stock_reservations r WHERE r.product_id = p.id AND r.status = 'active' AND r.expires_at > NOW()), 0) END)`
+
+// onSaleExpr indica se a promoção do produto está em vigor. Uma promoção que
+// deixou de ser inferior ao preço normal (ex: depois de um preço agendado) é ignorada.
+const onSaleExpr = `(p.sale_price IS NOT NULL AND p.sale_price < p.price
+	AND (p.sale_starts_at IS NULL OR p.sale_starts_at <= NOW()) AND (p.sale_ends_at IS NULL OR p.sale_ends_at > NOW()))`
+
+// O preço é lido como texto para não passar por vírgula flutuante.
+const productColumns = `p.id, p.sku, COALESCE(p.barcode, ''), p.name, p.description, p.price::text, p.currency, p.stock, ` + availableStockExpr + `,
+	p.created_at, p.updated_at, p.version, p.status, p.deleted_at, p.sale_price::text, p.sale_starts_at, p.sale_ends_at, ` + onSaleExpr + `, p.type, p.attributes`
+
+// productRow recebe as colunas de productColumns e monta o produto, juntando
+// o valor e a moeda do preço.
+type productRow struct {
+	product      *domain.Product
+	price        string
+	currency     string
+	salePrice    *string
+	saleStartsAt *time.Time
+	saleEndsAt   *time.Time
+}
+
+func newProductRow() *productRow {
+	return &productRow{product: &domain.Product{}}
+}
+
+// targets devolve os destinos de Scan pela ordem de productColumns.
+func (r *productRow) targets() []any {
+	p := r.product
+	return []any{&p.ID, &p.SKU, &p.Barcode, &p.Name, &p.Description, &r.price, &r.currency, &p.Stock, &p.AvailableStock, &p.CreatedAt, &p.UpdatedAt, &p.Version, &p.Status, &p.DeletedAt,
+		&r.salePrice, &r.saleStartsAt, &r.saleEndsAt, &p.OnSale, &p.Type, &p.Attributes}
+}
+
+func (r *productRow) finish() (*domain.Product, error) {
+	price, err := domain.ParseMoney(r.price, r.currency)
+	if err != nil {
+		return nil, err
+	}
+	r.product.Price = price
+	r.product.EffectivePrice = price
+
+	// O preço promocional está sempre na moeda do produto.
+	if r.salePrice != nil {
+		salePrice, err := domain.ParseMoney(*r.salePrice, r.currency)
+		if err != nil {
+			return nil, err
+		}
+		r.product.Sale = &domain.Sale{Price: salePrice, StartsAt: r.saleStartsAt, EndsAt: r.saleEndsAt}
+		if r.product.OnSale {
+			r.product.EffectivePrice = salePrice
+		}
+	}
+	return r.product, nil
+}
+
+// Produtos e variantes partilham o mesmo espaço de SKUs, do qual saem os
+// produtos removidos e as suas variantes. O índice único dos produtos só cobre
+// os produtos; claimSKU verifica as variantes com um advisory lock no SKU, que
+// serializa as escritas concorrentes do mesmo SKU nas duas tabelas.
+const (
+	skuUsedByProduct = `SELECT EXISTS (SELECT 1 FROM products WHERE sku = $1 AND deleted_at IS NULL)`
+	skuUsedByVariant = `SELECT EXISTS (SELECT 1 FROM product_variants v JOIN products p ON p.id = v.product_id WHERE v.sku = $1 AND p.deleted_at IS NULL)`
+	skuUsed          = `SELECT (` + skuUsedByProduct + `) OR (` + skuUsedByVariant + `)`
+)
+
+func claimSKU(ctx context.Context, tx dbtx, sku, usedBy string) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('sku:' || $1, 0))`, sku); err != nil {
+		return err
+	}
+	var taken bool
+	if err := tx.QueryRow(ctx, usedBy, sku).Scan(&taken); err != nil {
+		return err
+	}
+	if taken {
+		return domain.ErrSKUTaken
+	}
+	return nil
+}
+
+// productSaveError traduz as violações dos índices únicos de SKU e código de barras.
+func productSaveError(err error, fallback error) error {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, domain.ErrSKUTaken):
+		return err
+	case isUniqueViolation(err) && errors.As(err, &pgErr) && pgErr.ConstraintName == "products_barcode_key":
+		return domain.ErrBarcodeTaken
+	case isUniqueViolation(err):
+		return domain.ErrSKUTaken
+	default:
+		return fallback
+	}
+}
+
+// attributesValue grava um produto sem atributos como um objeto vazio e não como null.
+func attributesValue(attributes domain.Attributes) domain.Attributes {
+	if attributes == nil {
+		return domain.Attributes{}
+	}
+	return attributes
+}
+
+func scanProduct(row pgx.Row) (*domain.Product, error) {
+	scanned := newProductRow()
+	if err := row.Scan(scanned.targets()...); err != nil {
+		return nil, err
+	}
+	return scanned.finish()
+}
+
+// lockProductVersion bloqueia a linha do produto e confirma que a versão
+// continua a ser a lida pelo cliente, devolvendo o stock atual.
+func lockProductVersion(ctx context.Context, tx dbtx, id uuid.UUID, version int64) (int, error) {
+	var stock int
+	var current int64
+	err := tx.QueryRow(ctx, `SELECT stock, version FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&stock, &current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrProductNotFound
+		}
+		return 0, err
+	}
+	if current != version {
+		return 0, &domain.VersionConflictError{ProductID: id, Expected: version, Current: current}
+	}
+	return stock, nil
+}
+
+// lockAvailableStock bloqueia a linha do produto até ao fim da transação e
+// devolve o stock em mão menos as reservas ativas. Produtos arquivados não
+// podem ser vendidos nem reservados e os kits não têm stock próprio.
+func lockAvailableStock(ctx context.Context, tx dbtx, id uuid.UUID) (int, error) {
+	var stock int
+	var status domain.ProductStatus
+	var productType domain.ProductType
+	err := tx.QueryRow(ctx, `SELECT stock, status, type FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&stock, &status, &productType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrProductNotFound
+		}
+		return 0, err
+	}
+	if status == domain.StatusArchived {
+		return 0, domain.ErrProductArchived
+	}
+	if productType == domain.ProductBundle {
+		return 0, domain.ErrBundleStock
+	}
+
+	var reserved int
+	query := `SELECT COALESCE(SUM(quantity), 0) FROM stock_reservations WHERE product_id = $1 AND status = 'active' AND expires_at > NOW()`
+	if err := tx.QueryRow(ctx, query, id).Scan(&reserved); err != nil {
+		return 0, err
+	}
+	return stock - reserved, nil
+}
+
+// applyStockDelta soma delta ao stock do produto e regista o movimento correspondente na mesma transação.
+func applyStockDelta(ctx context.Context, tx dbtx, id uuid.UUID, delta int, reason domain.MovementReason, reference string) (int, error) {
+	return updateProductStock(ctx, tx, id, nil, delta, reason, reference)
+}
+
+func updateProductStock(ctx context.Context, tx dbtx, id uuid.UUID, warehouseID *uuid.UUID, delta int, reason domain.MovementReason, reference string) (int, error) {
+	var balance int
+	var productType domain.ProductType
+	err := tx.QueryRow(ctx, `UPDATE products SET stock = stock + $1, updated_at = NOW(), version = version + 1 WHERE id = $2 RETURNING stock, type`, delta, id).Scan(&balance, &productType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrProductNotFound
+		}
+		return 0, err
+	}
+	if productType == domain.ProductBundle {
+		return 0, domain.ErrBundleStock
+	}
+
+	movement := domain.NewStockMovement(ctx, id, delta, balance, reason, reference)
+	movement.WarehouseID = warehouseID
+	if err := insertStockMovement(ctx, tx, movement); err != nil {
+		return 0, err
+	}
+	return balance, nil
 }
 
 type postgresProductRepository struct {
-	db *pgxpool.Pool
+	db         *pgxpool.Pool
+	allocation domain.AllocationStrategy
 }
 
-func NewProduct(db *pgxpool.Pool) ProductRepository {
-	return &postgresProductRepository{db: db}
+func NewProduct(db *pgxpool.Pool, allocation domain.AllocationStrategy) ProductRepository {
+	return &postgresProductRepository{db: db, allocation: allocation}
 }
 
-func (r *postgresProductRepository) Create(ctx context.Context, product *domain.Product) error {
+func (r *postgresProductRepository) Create(ctx context.Context, product *domain.Product, categoryIDs ...uuid.UUID) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if err := claimSKU(ctx, tx, product.SKU, skuUsedByVariant); err != nil {
+			return err
+		}
+		query := `INSERT INTO products (id, sku, barcode, name, description, status, price, currency, stock, created_at, updated_at, version, attributes)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7::text::numeric, $8, $9, $10, $11, 1, $12)`
+		_, err := tx.Exec(ctx, query, product.ID, product.SKU, product.Barcode, product.Name, product.Description, product.Status,
+			product.Price.Amount(), product.Price.Currency, product.Stock, product.CreatedAt, product.UpdatedAt, attributesValue(product.Attributes))
+		if err != nil {
+			return err
+		}
+
+		if len(categoryIDs) > 0 {
+			query := `INSERT INTO product_categories (product_id, category_id) SELECT $1, unnest($2::uuid[]) ON CONFLICT DO NOTHING`
+			if _, err := tx.Exec(ctx, query, product.ID, categoryIDs); err != nil {
+				if isForeignKeyViolation(err) {
+					return domain.ErrCategoryNotFound
+				}
+				return err
+			}
+		}
+		if err := validateProductAttributes(ctx, tx, product.ID, product.Attributes); err != nil {
+			return err
+		}
 
-	query := `INSERT INTO products (id, name, description, price, stock, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
-	_, err := r.db.Exec(ctx, query, product.ID, product.Name, product.Description, product.Price, product.Stock, product.CreatedAt, product.UpdatedAt)
+		if err := insertStockMovement(ctx, tx, domain.NewStockMovement(ctx, product.ID, product.Stock, product.Stock, domain.MovementCreate, "")); err != nil {
+			return err
+		}
+		if err := recordPriceChange(ctx, tx, product.ID, nil, product.Price, domain.PriceChangeInitial, nil); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, product.ID, domain.RevisionCreate)
+	})
 	if err != nil {
-		return fmt.Errorf("Error creating product: %w", domain.ErrFailedCreatingProduct)
+		if errors.Is(err, domain.ErrCategoryNotFound) || errors.Is(err, domain.ErrInvalidAttributes) {
+			return fmt.Errorf("Error creating product: %w", err)
+		}
+		return fmt.Errorf("Error creating product: %w", productSaveError(err, domain.ErrFailedCreatingProduct))
 	}
 	return nil
 }
 
 func (r *postgresProductRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
 
-	query := `SELECT id, name, description, price, stock, created_at, updated_at FROM products WHERE id = $1`
-	product := &domain.Product{}
-	err := r.db.QueryRow(ctx, query, id).Scan(&product.ID, &product.Name, &product.Description, &product.Price, &product.Stock, &product.CreatedAt, &product.UpdatedAt)
+	query := `SELECT ` + productColumns + ` FROM products p WHERE p.id = $1 AND p.deleted_at IS NULL`
+	product, err := scanProduct(r.db.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("Error when searching for product by ID: %w", domain.ErrProductNotFound)
@@ -53,54 +307,585 @@ func (r *postgresProductRepository) GetProductByID(ctx context.Context, id uuid.
 	return product, nil
 }
 
-func (r *postgresProductRepository) ListProducts(ctx context.Context) ([]*domain.Product, error) {
+func (r *postgresProductRepository) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
 
-	query := `SELECT id, name, description, price, stock, created_at, updated_at FROM products`
-	rows, err := r.db.Query(ctx, query)
+	query := `SELECT ` + productColumns + ` FROM products p WHERE p.sku = $1 AND p.deleted_at IS NULL`
+	product, err := scanProduct(r.db.QueryRow(ctx, query, sku))
+	if errors.Is(err, pgx.ErrNoRows) {
+		// O SKU pode ser de uma variante: devolve o produto com a variante encontrada.
+		query = `SELECT ` + variantColumns + ` FROM product_variants v JOIN products p ON p.id = v.product_id
+			WHERE v.sku = $1 AND p.deleted_at IS NULL`
+		variant, variantErr := scanVariant(r.db.QueryRow(ctx, query, sku))
+		if errors.Is(variantErr, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when searching for product by SKU: %w", domain.ErrProductNotFound)
+		}
+		if variantErr != nil {
+			return nil, fmt.Errorf("Error when searching for product by SKU: %w", variantErr)
+		}
+		query = `SELECT ` + productColumns + ` FROM products p WHERE p.id = $1 AND p.deleted_at IS NULL`
+		if product, err = scanProduct(r.db.QueryRow(ctx, query, variant.ProductID)); err == nil {
+			product.Variant = variant
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("Error when searching for all products: %w", domain.ErrNotFoundProducts)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when searching for product by SKU: %w", domain.ErrProductNotFound)
+		}
+		return nil, fmt.Errorf("Error when searching for product by SKU: %w", err)
 	}
-	defer rows.Close()
+	return product, nil
+}
 
-	products := make([]*domain.Product, 0)
-	for rows.Next() {
-		product := &domain.Product{}
-		err := rows.Scan(&product.ID, &product.Name, &product.Description, &product.Price, &product.Stock, &product.CreatedAt, &product.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning product row: %w", err)
+func (r *postgresProductRepository) GetProductByBarcode(ctx context.Context, barcode string) (*domain.Product, error) {
+
+	// A expressão é a mesma do índice products_barcode_key para que este seja usado.
+	query := `SELECT ` + productColumns + ` FROM products p WHERE LPAD(p.barcode, 14, '0') = $1 AND p.barcode IS NOT NULL AND p.deleted_at IS NULL`
+	product, err := scanProduct(r.db.QueryRow(ctx, query, domain.GTIN14(barcode)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when searching for product by barcode: %w", domain.ErrProductNotFound)
 		}
-		products = append(products, product)
+		return nil, fmt.Errorf("Error when searching for product by barcode: %w", err)
 	}
-
-	return products, nil
+	return product, nil
 }
 
-func (r *postgresProductRepository) ReduceStock(ctx context.Context, id uuid.UUID, quantity int) error {
+func (r *postgresProductRepository) ReduceStock(ctx context.Context, id, warehouseID, variantID uuid.UUID, quantity int) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if variantID != uuid.Nil {
+			return reduceVariantStock(ctx, tx, id, variantID, quantity, domain.MovementReduce, "")
+		}
+
+		available, err := lockAvailableStock(ctx, tx, id)
+		if errors.Is(err, domain.ErrBundleStock) {
+			return reduceBundleStock(ctx, tx, id, warehouseID, quantity, r.allocation, domain.MovementReduce)
+		}
+		if err != nil {
+			return err
+		}
+		if available < quantity {
+			return &domain.InsufficientStockError{ProductID: id, Requested: quantity, Available: available}
+		}
 
-	query := `UPDATE products SET stock = stock - $1, updated_at = NOW() WHERE id = $3`
-	_, err := r.db.Exec(ctx, query, quantity, time.Now(), id)
+		return reduceProductStock(ctx, tx, id, warehouseID, quantity, r.allocation, domain.MovementReduce, "")
+	})
 	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrInsufficientStock) || errors.Is(err, domain.ErrVariantNotFound) ||
+			errors.Is(err, domain.ErrProductArchived) || errors.Is(err, domain.ErrBundleStock) ||
+			errors.Is(err, domain.ErrWarehouseNotFound) || errors.Is(err, domain.ErrWarehouseInactive) {
+			return fmt.Errorf("Error when reducing stock: %w", err)
+		}
 		return fmt.Errorf("Error when reducing stock: %w", domain.ErrToReduceStock)
 	}
 	return nil
 }
 
+func (r *postgresProductRepository) ReduceStockBatch(ctx context.Context, items []domain.StockItem) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		bundles, err := lockBundles(ctx, tx, items)
+		if err != nil {
+			return err
+		}
+
+		// As linhas de kits são substituídas pelas linhas dos seus componentes.
+		parts := make([][]stockPart, len(items))
+		locked := make([]stockPart, 0, len(items))
+		for i, item := range items {
+			parts[i] = stockParts(i, item, bundles)
+			locked = append(locked, parts[i]...)
+		}
+
+		// Bloqueia os produtos sempre pela mesma ordem para evitar deadlocks entre lotes concorrentes.
+		slices.SortFunc(locked, func(a, b stockPart) int {
+			return bytes.Compare(a.ProductID[:], b.ProductID[:])
+		})
+
+		available := make(map[uuid.UUID]int, len(locked))
+		archived := make(map[uuid.UUID]bool)
+		for _, part := range locked {
+			quantity, err := lockAvailableStock(ctx, tx, part.ProductID)
+			switch {
+			case err == nil:
+				available[part.ProductID] = quantity
+			case errors.Is(err, domain.ErrProductArchived):
+				archived[part.ProductID] = true
+			case !errors.Is(err, domain.ErrProductNotFound):
+				return err
+			}
+		}
+
+		// Linhas do mesmo produto em armazéns diferentes, ou de kits com componentes
+		// em comum, partilham o stock disponível.
+		lineErrors := make([]domain.StockLineError, 0)
+		for i, item := range items {
+			if lineError, ok := checkStockParts(item, parts[i], available, archived); !ok {
+				lineErrors = append(lineErrors, lineError)
+				continue
+			}
+			for _, part := range parts[i] {
+				available[part.ProductID] -= part.Quantity
+			}
+		}
+		if len(lineErrors) > 0 {
+			return &domain.BatchStockError{Lines: lineErrors}
+		}
+
+		// Com armazéns, o stock de cada armazém só é conhecido ao aplicar a linha.
+		// Cada parte corre num savepoint para que as restantes linhas sejam
+		// verificadas mesmo depois de uma falha.
+		failed := make(map[int]domain.StockLineError)
+		for _, part := range locked {
+			if _, ok := failed[part.line]; ok {
+				continue
+			}
+			savepoint, err := tx.Begin(ctx)
+			if err != nil {
+				return err
+			}
+			err = reduceProductStock(ctx, savepoint, part.ProductID, part.WarehouseID, part.Quantity, r.allocation, domain.MovementReduce, part.reference())
+			if err == nil {
+				err = savepoint.Commit(ctx)
+			}
+			if err != nil {
+				savepoint.Rollback(ctx)
+				line, ok := stockLineError(part.item, err)
+				if !ok {
+					return err
+				}
+				failed[part.line] = line
+			}
+		}
+		if len(failed) > 0 {
+			for i := range items {
+				if line, ok := failed[i]; ok {
+					lineErrors = append(lineErrors, line)
+				}
+			}
+			return &domain.BatchStockError{Lines: lineErrors}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrStockBatchRejected) {
+			return fmt.Errorf("Error when reducing stock in batch: %w", err)
+		}
+		return fmt.Errorf("Error when reducing stock in batch: %w", domain.ErrToReduceStock)
+	}
+	return nil
+}
+
+// stockPart é a parte de uma linha do lote que retira stock de um produto: a
+// própria linha ou, num kit, a linha de cada componente.
+type stockPart struct {
+	domain.StockItem
+	item    domain.StockItem
+	line    int
+	perUnit int
+}
+
+func stockParts(line int, item domain.StockItem, bundles map[uuid.UUID][]domain.BundleComponent) []stockPart {
+	components, isBundle := bundles[item.ProductID]
+	if !isBundle {
+		return []stockPart{{StockItem: item, item: item, line: line, perUnit: 1}}
+	}
+
+	parts := make([]stockPart, 0, len(components))
+	for _, component := range components {
+		parts = append(parts, stockPart{StockItem: domain.StockItem{ProductID: component.ProductID, WarehouseID: item.WarehouseID, Quantity: item.Quantity * component.Quantity},
+			item: item, line: line, perUnit: component.Quantity})
+	}
+	return parts
+}
+
+// reference liga os movimentos dos componentes ao kit vendido.
+func (p stockPart) reference() string {
+	if p.ProductID == p.item.ProductID {
+		return ""
+	}
+	return "bundle:" + p.item.ProductID.String()
+}
+
+// checkStockParts confirma que há stock disponível para todas as partes de uma
+// linha. Num kit, Available é o número de kits que é possível montar e um
+// componente arquivado não permite montar nenhum.
+func checkStockParts(item domain.StockItem, parts []stockPart, available map[uuid.UUID]int, archived map[uuid.UUID]bool) (domain.StockLineError, bool) {
+	line := domain.StockLineError{ProductID: item.ProductID, Requested: item.Quantity}
+
+	buildable := 0
+	for i, part := range parts {
+		quantity, found := available[part.ProductID]
+		switch {
+		case archived[part.ProductID] && part.ProductID == item.ProductID:
+			line.Code, line.Message = "PRODUCT_ARCHIVED", domain.ErrProductArchived.Error()
+			return line, false
+		case archived[part.ProductID]:
+			quantity = 0
+		case !found:
+			line.Code, line.Message = "PRODUCT_NOT_FOUND", domain.ErrProductNotFound.Error()
+			return line, false
+		}
+		if units := quantity / part.perUnit; i == 0 || units < buildable {
+			buildable = units
+		}
+	}
+	if buildable < item.Quantity {
+		line.Available = buildable
+		line.Code, line.Message = "INSUFFICIENT_STOCK", domain.ErrInsufficientStock.Error()
+		return line, false
+	}
+	return line, true
+}
+
+// stockLineError converte uma falha ao aplicar uma linha num relatório por
+// linha; devolve false para as falhas que não dizem respeito à linha.
+func stockLineError(item domain.StockItem, err error) (domain.StockLineError, bool) {
+	line := domain.StockLineError{ProductID: item.ProductID, Requested: item.Quantity, Message: err.Error()}
+
+	var stockErr *domain.InsufficientStockError
+	switch {
+	case errors.As(err, &stockErr):
+		line.Code = "INSUFFICIENT_STOCK"
+		line.Available = stockErr.Available
+	case errors.Is(err, domain.ErrWarehouseNotFound):
+		line.Code = "WAREHOUSE_NOT_FOUND"
+	case errors.Is(err, domain.ErrWarehouseInactive):
+		line.Code = "WAREHOUSE_INACTIVE"
+	default:
+		return line, false
+	}
+	return line, true
+}
+
+func (r *postgresProductRepository) IncreaseStock(ctx context.Context, id, warehouseID uuid.UUID, quantity int, reference string) (int, error) {
+
+	var balance int
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		if warehouseID != uuid.Nil {
+			balance, err = applyLocationStockDelta(ctx, tx, id, warehouseID, quantity, domain.MovementRestock, reference)
+			return err
+		}
+		balance, err = applyStockDelta(ctx, tx, id, quantity, domain.MovementRestock, reference)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrBundleStock) ||
+			errors.Is(err, domain.ErrWarehouseNotFound) || errors.Is(err, domain.ErrWarehouseInactive) {
+			return 0, fmt.Errorf("Error when increasing stock: %w", err)
+		}
+		return 0, fmt.Errorf("Error when increasing stock: %w", domain.ErrToAdjustStock)
+	}
+	return balance, nil
+}
+
+// SetStock define o stock contado fisicamente, no total ou num armazém; a
+// diferença para o valor atual é aplicada como um delta para não perder
+// reduções concorrentes já confirmadas.
+func (r *postgresProductRepository) SetStock(ctx context.Context, id, warehouseID uuid.UUID, stock int, reason string) (int, error) {
+
+	var balance int
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		unassigned, err := lockUnassignedStock(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if warehouseID != uuid.Nil {
+			current, err := lockLocationStock(ctx, tx, id, warehouseID)
+			if err != nil {
+				return err
+			}
+			balance, err = applyLocationStockDelta(ctx, tx, id, warehouseID, stock-current, domain.MovementAdjustment, reason)
+			return err
+		}
+
+		// Sem armazém, apenas o stock não atribuído pode ser ajustado.
+		var current int
+		if err := tx.QueryRow(ctx, `SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL`, id).Scan(&current); err != nil {
+			return err
+		}
+		if stock < current-unassigned {
+			return domain.ErrStockBelowAllocated
+		}
+		balance, err = applyStockDelta(ctx, tx, id, stock-current, domain.MovementAdjustment, reason)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrStockBelowAllocated) || errors.Is(err, domain.ErrBundleStock) ||
+			errors.Is(err, domain.ErrWarehouseNotFound) || errors.Is(err, domain.ErrWarehouseInactive) {
+			return 0, fmt.Errorf("Error when adjusting stock: %w", err)
+		}
+		return 0, fmt.Errorf("Error when adjusting stock: %w", domain.ErrToAdjustStock)
+	}
+	return balance, nil
+}
+
+func (r *postgresProductRepository) SetStatus(ctx context.Context, id uuid.UUID, status domain.ProductStatus) (*domain.Product, error) {
+
+	var product *domain.Product
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var current domain.ProductStatus
+		if err := tx.QueryRow(ctx, `SELECT status FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&current); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+		if !current.CanTransitionTo(status) {
+			return &domain.StatusTransitionError{From: current, To: status}
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE products SET status = $1, updated_at = NOW(), version = version + 1 WHERE id = $2`, status, id); err != nil {
+			return err
+		}
+
+		var err error
+		product, err = scanProduct(tx.QueryRow(ctx, `SELECT `+productColumns+` FROM products p WHERE p.id = $1 AND p.deleted_at IS NULL`, id))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrInvalidStatusTransition) {
+			return nil, fmt.Errorf("Error when changing product status: %w", err)
+		}
+		return nil, fmt.Errorf("Error when changing product status: %w", domain.ErrToChangeProductStatus)
+	}
+	return product, nil
+}
+
 func (r *postgresProductRepository) Update(ctx context.Context, product *domain.Product) error {
 
-	query := `UPDATE products SET name = $1, description = $2, price = $3, stock = $4, updated_at = $5 WHERE id = $6`
-	_, err := r.db.Exec(ctx, query, product.Name, product.Description, product.Price, product.Stock, time.Now(), product.ID)
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		previousStock, err := lockProductVersion(ctx, tx, product.ID, product.Version)
+		if err != nil {
+			return err
+		}
+		// Como em SetStock sem armazém, apenas o stock não atribuído pode mudar.
+		unassigned, err := lockUnassignedStock(ctx, tx, product.ID)
+		if err != nil {
+			return err
+		}
+		if product.Stock < previousStock-unassigned {
+			return domain.ErrStockBelowAllocated
+		}
+		previousPrice, err := lockProductPrice(ctx, tx, product.ID)
+		if err != nil {
+			return err
+		}
+		if err := validateProductAttributes(ctx, tx, product.ID, product.Attributes); err != nil {
+			return err
+		}
+		if err := claimSKU(ctx, tx, product.SKU, skuUsedByVariant); err != nil {
+			return err
+		}
+
+		query := `UPDATE products SET sku = $1, barcode = NULLIF($2, ''), name = $3, description = $4, price = $5::text::numeric, currency = $6, stock = $7,
+			attributes = $8, updated_at = $9, version = version + 1 WHERE id = $10 RETURNING version, type`
+		err = tx.QueryRow(ctx, query, product.SKU, product.Barcode, product.Name, product.Description, product.Price.Amount(), product.Price.Currency, product.Stock,
+			attributesValue(product.Attributes), time.Now(), product.ID).Scan(&product.Version, &product.Type)
+		if err != nil {
+			return err
+		}
+		if product.Type == domain.ProductBundle && product.Stock != 0 {
+			return domain.ErrBundleStock
+		}
+
+		if delta := product.Stock - previousStock; delta != 0 {
+			if err := insertStockMovement(ctx, tx, domain.NewStockMovement(ctx, product.ID, delta, product.Stock, domain.MovementUpdate, "")); err != nil {
+				return err
+			}
+		}
+		if err := recordPriceChange(ctx, tx, product.ID, &previousPrice, product.Price, domain.PriceChangeUpdate, nil); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, product.ID, domain.RevisionUpdate)
+	})
 	if err != nil {
-		return fmt.Errorf("Error when updating product: %w", domain.ErrToUpdateProduct)
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrVersionConflict) || errors.Is(err, domain.ErrBundleStock) ||
+			errors.Is(err, domain.ErrInvalidAttributes) || errors.Is(err, domain.ErrStockBelowAllocated) {
+			return fmt.Errorf("Error when updating product: %w", err)
+		}
+		return fmt.Errorf("Error when updating product: %w", productSaveError(err, domain.ErrToUpdateProduct))
 	}
 	return nil
 }
 
-func (r *postgresProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *postgresProductRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := lockProductVersion(ctx, tx, id, version); err != nil {
+			return err
+		}
+
+		// Remover um componente deixaria os kits que o usam sem stock.
+		var inBundle bool
+		query := `SELECT EXISTS (SELECT 1 FROM bundle_components bc JOIN products b ON b.id = bc.bundle_id WHERE bc.component_id = $1 AND b.deleted_at IS NULL)`
+		if err := tx.QueryRow(ctx, query, id).Scan(&inBundle); err != nil {
+			return err
+		}
+		if inBundle {
+			return domain.ErrProductInBundle
+		}
 
-	query := `DELETE FROM products WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id)
+		if _, err := tx.Exec(ctx, `UPDATE products SET deleted_at = NOW(), updated_at = NOW(), version = version + 1 WHERE id = $1`, id); err != nil {
+			return err
+		}
+		return recordRevision(ctx, tx, id, domain.RevisionDelete)
+	})
 	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrVersionConflict) || errors.Is(err, domain.ErrProductInBundle) {
+			return fmt.Errorf("Error when deleting product: %w", err)
+		}
 		return fmt.Errorf("Error when deleting product: %w", domain.ErrToDeletegProduct)
 	}
 	return nil
 }
+
+func (r *postgresProductRepository) Restore(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+
+	var product *domain.Product
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		// O SKU e o código de barras podem ter sido reutilizados enquanto o
+		// produto esteve removido: os índices únicos e claimSKU recusam o
+		// restauro. Os SKUs das variantes são reclamados enquanto o produto
+		// ainda está removido, para não colidirem com elas próprias.
+		rows, err := tx.Query(ctx, `SELECT sku FROM product_variants WHERE product_id = $1 ORDER BY sku`, id)
+		if err != nil {
+			return err
+		}
+		variantSKUs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		for _, variantSKU := range variantSKUs {
+			if err := claimSKU(ctx, tx, variantSKU, skuUsed); err != nil {
+				return err
+			}
+		}
+
+		var sku string
+		err = tx.QueryRow(ctx, `UPDATE products SET deleted_at = NULL, updated_at = NOW(), version = version + 1
+			WHERE id = $1 AND deleted_at IS NOT NULL RETURNING sku`, id).Scan(&sku)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrProductNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := claimSKU(ctx, tx, sku, skuUsedByVariant); err != nil {
+			return err
+		}
+		if err := recordRevision(ctx, tx, id, domain.RevisionRestore); err != nil {
+			return err
+		}
+
+		product, err = scanProduct(tx.QueryRow(ctx, `SELECT `+productColumns+` FROM products p WHERE p.id = $1`, id))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			return nil, fmt.Errorf("Error when restoring product: %w", err)
+		}
+		return nil, fmt.Errorf("Error when restoring product: %w", productSaveError(err, domain.ErrToRestoreProduct))
+	}
+	return product, nil
+}
+
+func (r *postgresProductRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, []string, error) {
+
+	var purged int64
+	var keys []string
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		// Um componente só é purgado depois dos kits removidos que o usam. O
+		// bloqueio deixa de fora os produtos restaurados entretanto.
+		rows, err := tx.Query(ctx, `SELECT p.id FROM products p WHERE p.deleted_at < $1
+			AND NOT EXISTS (SELECT 1 FROM bundle_components bc WHERE bc.component_id = p.id) FOR UPDATE`, before)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		rows, err = tx.Query(ctx, `DELETE FROM product_media WHERE product_id = ANY($1) RETURNING storage_key`, ids)
+		if err != nil {
+			return err
+		}
+		if keys, err = pgx.CollectRows(rows, pgx.RowTo[string]); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, `DELETE FROM products WHERE id = ANY($1)`, ids)
+		purged = tag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("Error when purging deleted products: %w", err)
+	}
+	return purged, keys, nil
+}
+
+func (r *postgresProductRepository) SetSale(ctx context.Context, id uuid.UUID, sale *domain.Sale) (*domain.Product, error) {
+
+	var product *domain.Product
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		price, err := lockProductPrice(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		var salePrice *string
+		var startsAt, endsAt *time.Time
+		if sale != nil {
+			if sale.Price.Currency != price.Currency {
+				return fmt.Errorf("%w: sale price must be in %s", domain.ErrCurrencyMismatch, price.Currency)
+			}
+			// Com o preço bloqueado, nem uma atualização nem um preço agendado se cruzam com a comparação.
+			cmp, err := sale.Price.Compare(price)
+			if err != nil {
+				return err
+			}
+			if cmp >= 0 {
+				return fmt.Errorf("%w: sale price must be lower than the regular price %s", domain.ErrInvalidSalePrice, price)
+			}
+			amount := sale.Price.Amount()
+			salePrice, startsAt, endsAt = &amount, sale.StartsAt, sale.EndsAt
+		}
+
+		query := `UPDATE products SET sale_price = $1::text::numeric, sale_starts_at = $2, sale_ends_at = $3, updated_at = NOW(), version = version + 1
+			WHERE id = $4 AND deleted_at IS NULL`
+		tag, err := tx.Exec(ctx, query, salePrice, startsAt, endsAt, id)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrProductNotFound
+		}
+		if err := recordRevision(ctx, tx, id, domain.RevisionUpdate); err != nil {
+			return err
+		}
+
+		product, err = scanProduct(tx.QueryRow(ctx, `SELECT `+productColumns+` FROM products p WHERE p.id = $1`, id))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrCurrencyMismatch) || errors.Is(err, domain.ErrInvalidSalePrice) {
+			return nil, fmt.Errorf("Error when setting sale price: %w", err)
+		}
+		return nil, fmt.Errorf("Error when setting sale price: %w", domain.ErrToSetSale)
+	}
+	return product, nil
+}
+
+func (r *postgresProductRepository) ConvertPrices(ctx context.Context, currency string, products ...*domain.Product) error {
+
+	if err := convertProductPrices(ctx, r.db, currency, products); err != nil {
+		if errors.Is(err, domain.ErrPriceNotAvailable) {
+			return fmt.Errorf("Error converting prices: %w", err)
+		}
+		return fmt.Errorf("Error converting prices: %w", domain.ErrToConvertPrices)
+	}
+	return nil
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"os"
+	"path/filepath"
 	"product-service/src/domain"
 	"product-service/test_artefacts/seeder"
 	"product-service/test_artefacts/stubs"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	. "github.com/onsi/ginkgo/v2"
@@ -64,10 +67,14 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	migration, err := os.ReadFile("../../database/000001_create_products_table.up.sql")
-	Expect(err).NotTo(HaveOccurred())
-	_, err = db.Exec(context.Background(), string(migration))
+	migrations, err := filepath.Glob("../../database/*.up.sql")
 	Expect(err).NotTo(HaveOccurred())
+	for _, file := range migrations {
+		migration, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(context.Background(), string(migration))
+		Expect(err).NotTo(HaveOccurred())
+	}
 })
 
 var _ = AfterSuite(func() {
@@ -81,10 +88,10 @@ var _ = Describe("ProductRepository", func() {
 
 	BeforeEach(func() {
 		ctx = context.Background()
-		productRepo = NewProduct(db)
+		productRepo = NewProduct(db, domain.AllocationPriority)
 		testSeeder = seeder.NewTestSeeder(db)
 
-		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY")
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -125,7 +132,7 @@ var _ = Describe("ProductRepository", func() {
 		Context("with an invalid price", func() {
 			It("should return an invalid price error", func() {
 				// Arrange: Cria um produto com preço negativo
-				product := stubs.NewProductStub().WithPrice(-10.0).Get()
+				product := stubs.NewProductStub().WithPrice("-10.00").Get()
 
 				// Act: Tenta criar o produto no banco de dados
 				err := productRepo.Create(ctx, product)
@@ -137,6 +144,35 @@ var _ = Describe("ProductRepository", func() {
 		})
 	})
 
+	Describe("Looking up a product by SKU or barcode", func() {
+		It("should find the product by SKU and by an equivalent barcode", func() {
+			// Arrange: Cria um produto com um UPC-A
+			product := stubs.NewProductStub().WithSKU("CAN-AZ-01").WithBarcode("789123456789").Get()
+			Expect(productRepo.Create(ctx, product)).To(Succeed())
+
+			// Act: Procura pelo SKU e pelo mesmo código escrito como EAN-13
+			bySKU, err := productRepo.GetProductBySKU(ctx, "CAN-AZ-01")
+			Expect(err).NotTo(HaveOccurred())
+			byBarcode, err := productRepo.GetProductByBarcode(ctx, "0789123456789")
+
+			// Assert
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bySKU.ID).To(Equal(product.ID))
+			Expect(byBarcode.ID).To(Equal(product.ID))
+			Expect(byBarcode.Barcode).To(Equal("789123456789"))
+		})
+
+		It("should reject a repeated SKU or barcode", func() {
+			Expect(productRepo.Create(ctx, stubs.NewProductStub().WithSKU("CAN-AZ-01").WithBarcode("789123456789").Get())).To(Succeed())
+
+			err := productRepo.Create(ctx, stubs.NewProductStub().WithSKU("CAN-AZ-01").Get())
+			Expect(errors.Is(err, domain.ErrSKUTaken)).To(BeTrue())
+
+			err = productRepo.Create(ctx, stubs.NewProductStub().WithBarcode("0789123456789").Get())
+			Expect(errors.Is(err, domain.ErrBarcodeTaken)).To(BeTrue())
+		})
+	})
+
 	Describe("Getting a product by ID", func() {
 		Context("when the product exists", func() {
 			It("should return the correct product", func() {
@@ -172,15 +208,18 @@ var _ = Describe("ProductRepository", func() {
 	})
 
 	Describe("Listing all products", func() {
+		listQuery := domain.ProductQuery{Limit: 50, SortBy: domain.SortByCreatedAt}
+
 		Context("when there are no products", func() {
-			It("should return an empty slice", func() {
+			It("should return an empty page", func() {
 				// Act: Lista todos os produtos
-				products, err := productRepo.ListProducts(ctx)
+				page, err := productRepo.ListProducts(ctx, listQuery)
 
 				// Assert: Verifica se não ocorreu nenhum erro e se a lista está vazia
 				Expect(err).NotTo(HaveOccurred())
-				Expect(products).NotTo(BeNil())
-				Expect(products).To(BeEmpty())
+				Expect(page.Items).NotTo(BeNil())
+				Expect(page.Items).To(BeEmpty())
+				Expect(page.NextCursor).To(BeEmpty())
 			})
 		})
 
@@ -192,12 +231,178 @@ var _ = Describe("ProductRepository", func() {
 				Expect(testSeeder.InsertProduct(ctx, stubs.NewProductStub().Get())).To(Succeed())
 
 				// Act: Lista todos os produtos
-				products, err := productRepo.ListProducts(ctx)
+				page, err := productRepo.ListProducts(ctx, listQuery)
 
 				// Assert: Verifica se não ocorreu nenhum erro e se a lista contém 3 produtos
 				Expect(err).NotTo(HaveOccurred())
-				Expect(products).To(HaveLen(3))
+				Expect(page.Items).To(HaveLen(3))
+			})
+
+			It("should page through the products sorted by price", func() {
+				// Arrange: Insere 3 produtos com preços diferentes
+				for _, price := range []string{"30.00", "10.00", "20.00"} {
+					Expect(testSeeder.InsertProduct(ctx, stubs.NewProductStub().WithPrice(price).Get())).To(Succeed())
+				}
+				query := domain.ProductQuery{Limit: 2, SortBy: domain.SortByPrice, Descending: true, IncludeTotal: true}
+
+				// Act: Lê a primeira página e usa o cursor para a seguinte
+				first, err := productRepo.ListProducts(ctx, query)
+				Expect(err).NotTo(HaveOccurred())
+				query.Cursor = first.NextCursor
+				second, err := productRepo.ListProducts(ctx, query)
+				Expect(err).NotTo(HaveOccurred())
+
+				// Assert: Os produtos seguem a ordem pedida sem repetições
+				Expect(first.Items).To(HaveLen(2))
+				Expect(first.Items[0].Price.Amount()).To(Equal("30.00"))
+				Expect(first.Items[1].Price.Amount()).To(Equal("20.00"))
+				Expect(*first.Total).To(Equal(int64(3)))
+				Expect(second.Items).To(HaveLen(1))
+				Expect(second.Items[0].Price.Amount()).To(Equal("10.00"))
+				Expect(second.NextCursor).To(BeEmpty())
 			})
+
+			It("should apply the price, stock and name filters", func() {
+				// Arrange: Só a caneca azul cumpre todos os filtros
+				Expect(testSeeder.InsertProduct(ctx, stubs.NewProductStub().WithName("Caneca azul").WithPrice("15.00").WithStock(3).Get())).To(Succeed())
+				Expect(testSeeder.InsertProduct(ctx, stubs.NewProductStub().WithName("Caneca 100% verde").WithPrice("15.00").WithStock(0).Get())).To(Succeed())
+				Expect(testSeeder.InsertProduct(ctx, stubs.NewProductStub().WithName("Caneca grande").WithPrice("50.00").WithStock(3).Get())).To(Succeed())
+				Expect(testSeeder.InsertProduct(ctx, stubs.NewProductStub().WithName("Prato").WithPrice("15.00").WithStock(3).Get())).To(Succeed())
+				minPrice, maxPrice := domain.Money{Minor: 1000, Currency: "BRL"}, domain.Money{Minor: 2000, Currency: "BRL"}
+				query := domain.ProductQuery{Limit: 50, SortBy: domain.SortByName, MinPrice: &minPrice, MaxPrice: &maxPrice, InStockOnly: true, NameContains: "caneca"}
+
+				// Act: Lista com os filtros
+				page, err := productRepo.ListProducts(ctx, query)
+
+				// Assert: Verifica que apenas o produto esperado é devolvido
+				Expect(err).NotTo(HaveOccurred())
+				Expect(page.Items).To(HaveLen(1))
+				Expect(page.Items[0].Name).To(Equal("Caneca azul"))
+			})
+
+			It("should reject a cursor created for a different sort", func() {
+				Expect(testSeeder.InsertProduct(ctx, stubs.NewProductStub().Get())).To(Succeed())
+				Expect(testSeeder.InsertProduct(ctx, stubs.NewProductStub().Get())).To(Succeed())
+				first, err := productRepo.ListProducts(ctx, domain.ProductQuery{Limit: 1, SortBy: domain.SortByName})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = productRepo.ListProducts(ctx, domain.ProductQuery{Limit: 1, SortBy: domain.SortByPrice, Cursor: first.NextCursor})
+
+				Expect(errors.Is(err, domain.ErrInvalidCursor)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("Reducing stock", func() {
+		It("should decrement the stock when there is enough available", func() {
+			// Arrange: Insere um produto com 10 unidades
+			product := stubs.NewProductStub().WithStock(10).Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+
+			// Act: Reduz 4 unidades
+			err := productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 4)
+
+			// Assert: Verifica se o stock foi reduzido
+			Expect(err).NotTo(HaveOccurred())
+			foundProduct, err := productRepo.GetProductByID(ctx, product.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundProduct.Stock).To(Equal(6))
+		})
+
+		It("should report the available quantity when stock is insufficient", func() {
+			// Arrange: Insere um produto com 3 unidades
+			product := stubs.NewProductStub().WithStock(3).Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+
+			// Act: Tenta reduzir mais do que o disponível
+			err := productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 5)
+
+			// Assert: Verifica se o erro traz as quantidades pedida e disponível
+			var stockErr *domain.InsufficientStockError
+			Expect(errors.As(err, &stockErr)).To(BeTrue())
+			Expect(stockErr.Requested).To(Equal(5))
+			Expect(stockErr.Available).To(Equal(3))
+		})
+
+		It("should return a product not found error for unknown products", func() {
+			// Act: Tenta reduzir o stock de um produto inexistente
+			err := productRepo.ReduceStock(ctx, stubs.NewProductStub().Get().ID, uuid.Nil, uuid.Nil, 1)
+
+			// Assert: Verifica se o erro é o esperado
+			Expect(errors.Is(err, domain.ErrProductNotFound)).To(BeTrue())
+		})
+	})
+
+	Describe("Changing the product status", func() {
+		It("should follow the allowed transitions and block stock on archived products", func() {
+			// Arrange: Cria um produto em rascunho
+			product := stubs.NewProductStub().WithStatus(domain.StatusDraft).WithStock(5).Get()
+			Expect(productRepo.Create(ctx, product)).To(Succeed())
+
+			// Act: Publica e arquiva o produto
+			active, err := productRepo.SetStatus(ctx, product.ID, domain.StatusActive)
+			Expect(err).NotTo(HaveOccurred())
+			archived, err := productRepo.SetStatus(ctx, product.ID, domain.StatusArchived)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert: Cada transição incrementa a versão e o produto arquivado não vende
+			Expect(active.Status).To(Equal(domain.StatusActive))
+			Expect(archived.Status).To(Equal(domain.StatusArchived))
+			Expect(archived.Version).To(Equal(active.Version + 1))
+			err = productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 1)
+			Expect(errors.Is(err, domain.ErrProductArchived)).To(BeTrue())
+			_, err = productRepo.SetStatus(ctx, product.ID, domain.StatusDraft)
+			Expect(errors.Is(err, domain.ErrInvalidStatusTransition)).To(BeTrue())
+		})
+	})
+
+	Describe("Reducing stock in batch", func() {
+		It("should decrement every product when all lines have stock", func() {
+			// Arrange: Insere dois produtos
+			first := stubs.NewProductStub().WithStock(10).Get()
+			second := stubs.NewProductStub().WithStock(5).Get()
+			Expect(testSeeder.InsertProduct(ctx, first)).To(Succeed())
+			Expect(testSeeder.InsertProduct(ctx, second)).To(Succeed())
+
+			// Act: Reduz o stock dos dois produtos no mesmo lote
+			err := productRepo.ReduceStockBatch(ctx, []domain.StockItem{
+				{ProductID: first.ID, Quantity: 3},
+				{ProductID: second.ID, Quantity: 5},
+			})
+
+			// Assert: Verifica se ambos os produtos foram atualizados
+			Expect(err).NotTo(HaveOccurred())
+			foundFirst, err := productRepo.GetProductByID(ctx, first.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundFirst.Stock).To(Equal(7))
+			foundSecond, err := productRepo.GetProductByID(ctx, second.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundSecond.Stock).To(Equal(0))
+		})
+
+		It("should not apply any line when one of them fails", func() {
+			// Arrange: Insere um produto com stock e gera um ID inexistente
+			product := stubs.NewProductStub().WithStock(10).Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+			missingID := stubs.NewProductStub().Get().ID
+
+			// Act: Tenta reduzir um lote com uma linha inválida
+			err := productRepo.ReduceStockBatch(ctx, []domain.StockItem{
+				{ProductID: product.ID, Quantity: 3},
+				{ProductID: missingID, Quantity: 1},
+			})
+
+			// Assert: Verifica o relatório por linha
+			var batchErr *domain.BatchStockError
+			Expect(errors.As(err, &batchErr)).To(BeTrue())
+			Expect(batchErr.Lines).To(HaveLen(1))
+			Expect(batchErr.Lines[0].ProductID).To(Equal(missingID))
+			Expect(batchErr.Lines[0].Code).To(Equal("PRODUCT_NOT_FOUND"))
+
+			// Verify: O stock do produto válido não foi alterado
+			foundProduct, err := productRepo.GetProductByID(ctx, product.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundProduct.Stock).To(Equal(10))
 		})
 	})
 
@@ -210,7 +415,7 @@ var _ = Describe("ProductRepository", func() {
 			// Modifica os dados do produto
 			updatedProduct := originalProduct
 			updatedProduct.Name = "Nome Atualizado"
-			updatedProduct.Price = 199.99
+			updatedProduct.Price = domain.Money{Minor: 19999, Currency: "BRL"}
 			updatedProduct.Stock = 50
 
 			// Act: Tenta atualizar o produto no banco de dados
@@ -223,8 +428,30 @@ var _ = Describe("ProductRepository", func() {
 			foundProduct, err := productRepo.GetProductByID(ctx, originalProduct.ID)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(foundProduct.Name).To(Equal("Nome Atualizado"))
-			Expect(foundProduct.Price).To(BeNumerically("==", 199.99))
+			Expect(foundProduct.Price).To(Equal(domain.Money{Minor: 19999, Currency: "BRL"}))
 			Expect(foundProduct.Stock).To(Equal(50))
+			Expect(foundProduct.Version).To(Equal(int64(2)))
+		})
+
+		It("should reject an update based on a stale version", func() {
+			// Arrange: Insere um produto e altera o stock, avançando a versão
+			originalProduct := stubs.NewProductStub().WithStock(10).Get()
+			Expect(testSeeder.InsertProduct(ctx, originalProduct)).To(Succeed())
+			Expect(productRepo.ReduceStock(ctx, originalProduct.ID, uuid.Nil, uuid.Nil, 1)).To(Succeed())
+
+			// Act: Tenta atualizar com a versão lida antes da redução
+			staleProduct := *originalProduct
+			staleProduct.Name = "Nome Atualizado"
+			err := productRepo.Update(ctx, &staleProduct)
+
+			// Assert: Verifica que o conflito indica a versão atual
+			var versionErr *domain.VersionConflictError
+			Expect(errors.As(err, &versionErr)).To(BeTrue())
+			Expect(versionErr.Current).To(Equal(int64(2)))
+
+			foundProduct, err := productRepo.GetProductByID(ctx, originalProduct.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundProduct.Name).To(Equal(originalProduct.Name))
 		})
 	})
 
@@ -235,7 +462,7 @@ var _ = Describe("ProductRepository", func() {
 			Expect(testSeeder.InsertProduct(ctx, productToDelete)).To(Succeed())
 
 			// Act: Tenta deletar o produto
-			err := productRepo.Delete(ctx, productToDelete.ID)
+			err := productRepo.Delete(ctx, productToDelete.ID, 1)
 
 			// Assert: Verifica se não ocorreu nenhum erro
 			Expect(err).NotTo(HaveOccurred())
@@ -245,5 +472,74 @@ var _ = Describe("ProductRepository", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(errors.Is(err, domain.ErrProductNotFound)).To(BeTrue())
 		})
+
+		It("should not delete a product when the version is stale", func() {
+			productToDelete := stubs.NewProductStub().Get()
+			Expect(testSeeder.InsertProduct(ctx, productToDelete)).To(Succeed())
+
+			err := productRepo.Delete(ctx, productToDelete.ID, 7)
+
+			Expect(errors.Is(err, domain.ErrVersionConflict)).To(BeTrue())
+			_, err = productRepo.GetProductByID(ctx, productToDelete.ID)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should keep the deleted product until it is restored or purged", func() {
+			// Arrange: Remove um produto
+			product := stubs.NewProductStub().Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+			Expect(productRepo.Delete(ctx, product.ID, 1)).To(Succeed())
+
+			// Act: Lista os produtos removidos e restaura o produto
+			deleted, err := productRepo.ListProducts(ctx, domain.ProductQuery{Deleted: true, Limit: 10})
+			Expect(err).NotTo(HaveOccurred())
+			restored, err := productRepo.Restore(ctx, product.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert: O produto removido aparece na listagem e volta a ser visível
+			Expect(deleted.Items).To(HaveLen(1))
+			Expect(deleted.Items[0].DeletedAt).NotTo(BeNil())
+			Expect(restored.DeletedAt).To(BeNil())
+			Expect(restored.Version).To(Equal(int64(3)))
+			_, err = productRepo.GetProductByID(ctx, product.ID)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should free the SKU and barcode of a deleted product and refuse to restore it once reused", func() {
+			// Arrange: Remove um produto e cria outro com o mesmo SKU e código de barras
+			product := stubs.NewProductStub().WithBarcode("7891234567895").Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+			Expect(productRepo.Delete(ctx, product.ID, 1)).To(Succeed())
+			reused := stubs.NewProductStub().WithSKU(product.SKU).WithBarcode("7891234567895").Get()
+			Expect(productRepo.Create(ctx, reused)).To(Succeed())
+
+			// Act
+			_, err := productRepo.Restore(ctx, product.ID)
+
+			// Assert: O restauro é recusado e o produto continua removido
+			Expect(errors.Is(err, domain.ErrSKUTaken)).To(BeTrue())
+			deleted, err := productRepo.ListProducts(ctx, domain.ProductQuery{Deleted: true, Limit: 10})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted.Items).To(HaveLen(1))
+		})
+
+		It("should only purge products deleted before the cutoff", func() {
+			// Arrange: Remove um produto
+			product := stubs.NewProductStub().Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+			Expect(productRepo.Delete(ctx, product.ID, 1)).To(Succeed())
+
+			// Act: Purga antes e depois da data da remoção
+			early, _, err := productRepo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			late, _, err := productRepo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+
+			// Assert: Só a segunda purga apaga o produto, que deixa de poder ser restaurado
+			Expect(early).To(Equal(int64(0)))
+			Expect(late).To(Equal(int64(1)))
+			_, err = productRepo.Restore(ctx, product.ID)
+			Expect(errors.Is(err, domain.ErrProductNotFound)).To(BeTrue())
+		})
 	})
 })
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const revisionColumns = `id, product_id, revision, action, snapshot, COALESCE(actor_id, ''), COALESCE(correlation_id, ''), created_at`
+
+func scanRevision(row pgx.Row) (*domain.ProductRevision, error) {
+	revision := &domain.ProductRevision{}
+	err := row.Scan(&revision.ID, &revision.ProductID, &revision.Revision, &revision.Action, &revision.Snapshot,
+		&revision.ActorID, &revision.CorrelationID, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// recordRevision guarda o estado atual do produto como uma nova revisão. A
+// linha do produto tem de estar bloqueada pela transação, para que o número da
+// revisão seja sequencial.
+func recordRevision(ctx context.Context, tx dbtx, productID uuid.UUID, action domain.RevisionAction) error {
+	snapshot, err := scanProduct(tx.QueryRow(ctx, `SELECT `+productColumns+` FROM products p WHERE p.id = $1`, productID))
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO product_revisions (id, product_id, revision, action, snapshot, actor_id, correlation_id, created_at)
+		SELECT $1, $2, COALESCE(MAX(revision), 0) + 1, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7 FROM product_revisions WHERE product_id = $2`
+	_, err = tx.Exec(ctx, query, uuid.New(), productID, action, snapshot,
+		domain.UserIDFromContext(ctx), domain.CorrelationIDFromContext(ctx), time.Now().UTC())
+	return err
+}
+
+func (r *postgresProductRepository) ListRevisions(ctx context.Context, productID uuid.UUID) ([]*domain.ProductRevision, error) {
+
+	query := `SELECT ` + revisionColumns + ` FROM product_revisions WHERE product_id = $1 ORDER BY revision DESC`
+	rows, err := r.db.Query(ctx, query, productID)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing product revisions: %w", domain.ErrToListRevisions)
+	}
+	defer rows.Close()
+
+	revisions := make([]*domain.ProductRevision, 0)
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning product revision row: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when listing product revisions: %w", domain.ErrToListRevisions)
+	}
+	return revisions, nil
+}
+
+func getRevision(ctx context.Context, db dbtx, productID uuid.UUID, number int) (*domain.ProductRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM product_revisions WHERE product_id = $1 AND revision = $2`
+	revision, err := scanRevision(db.QueryRow(ctx, query, productID, number))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrRevisionNotFound
+	}
+	return revision, err
+}
+
+func (r *postgresProductRepository) GetRevision(ctx context.Context, productID uuid.UUID, number int) (*domain.ProductRevision, error) {
+
+	revision, err := getRevision(ctx, r.db, productID, number)
+	if err != nil {
+		return nil, fmt.Errorf("Error when searching for product revision: %w", err)
+	}
+	return revision, nil
+}
+
+// RevertToRevision repõe o SKU, o código de barras, o nome, a descrição e o
+// preço da revisão indicada. O stock e o estado não são revertidos, por
+// dependerem de operações posteriores à revisão.
+func (r *postgresProductRepository) RevertToRevision(ctx context.Context, productID uuid.UUID, number int, version int64) (*domain.Product, error) {
+
+	var product *domain.Product
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := lockProductVersion(ctx, tx, productID, version); err != nil {
+			return err
+		}
+		previousPrice, err := lockProductPrice(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+		revision, err := getRevision(ctx, tx, productID, number)
+		if err != nil {
+			return err
+		}
+
+		snapshot := revision.Snapshot
+		if err := claimSKU(ctx, tx, snapshot.SKU, skuUsedByVariant); err != nil {
+			return err
+		}
+		query := `UPDATE products SET sku = $1, barcode = NULLIF($2, ''), name = $3, description = $4, price = $5::text::numeric, currency = $6,
+			updated_at = NOW(), version = version + 1 WHERE id = $7`
+		_, err = tx.Exec(ctx, query, snapshot.SKU, snapshot.Barcode, snapshot.Name, snapshot.Description, snapshot.Price.Amount(), snapshot.Price.Currency, productID)
+		if err != nil {
+			return err
+		}
+		if err := recordPriceChange(ctx, tx, productID, &previousPrice, snapshot.Price, domain.PriceChangeRevert, nil); err != nil {
+			return err
+		}
+		if err := recordRevision(ctx, tx, productID, domain.RevisionRevert); err != nil {
+			return err
+		}
+
+		product, err = scanProduct(tx.QueryRow(ctx, `SELECT `+productColumns+` FROM products p WHERE p.id = $1`, productID))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrVersionConflict) || errors.Is(err, domain.ErrRevisionNotFound) {
+			return nil, fmt.Errorf("Error when reverting product: %w", err)
+		}
+		return nil, fmt.Errorf("Error when reverting product: %w", productSaveError(err, domain.ErrToRevertProduct))
+	}
+	return product, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Product revisions", func() {
+	var productRepo ProductRepository
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.WithValue(context.Background(), domain.UserIDContextKey, "user-42")
+		productRepo = NewProduct(db, domain.AllocationPriority)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should record a snapshot for every create, update and delete", func() {
+		// Arrange: Cria e altera um produto
+		product := stubs.NewProductStub().WithName("Caneca azul").Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		product.Name = "Caneca verde"
+		Expect(productRepo.Update(ctx, product)).To(Succeed())
+
+		// Act: Remove o produto e lista as revisões
+		Expect(productRepo.Delete(ctx, product.ID, product.Version)).To(Succeed())
+		revisions, err := productRepo.ListRevisions(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Assert: As revisões vêm da mais recente para a mais antiga, com o autor
+		Expect(revisions).To(HaveLen(3))
+		Expect(revisions[0].Revision).To(Equal(3))
+		Expect(revisions[0].Action).To(Equal(domain.RevisionDelete))
+		Expect(revisions[0].Snapshot.DeletedAt).NotTo(BeNil())
+		Expect(revisions[1].Snapshot.Name).To(Equal("Caneca verde"))
+		Expect(revisions[2].Action).To(Equal(domain.RevisionCreate))
+		Expect(revisions[2].Snapshot.Name).To(Equal("Caneca azul"))
+		Expect(revisions[2].ActorID).To(Equal("user-42"))
+	})
+
+	It("should revert the product details but keep the current stock", func() {
+		// Arrange: Cria o produto e altera o nome e o stock
+		product := stubs.NewProductStub().WithName("Caneca azul").WithStock(10).Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		product.Name = "Caneca verde"
+		product.Stock = 4
+		Expect(productRepo.Update(ctx, product)).To(Succeed())
+
+		// Act: Reverte para a primeira revisão
+		reverted, err := productRepo.RevertToRevision(ctx, product.ID, 1, product.Version)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Assert: O nome volta ao original, o stock mantém-se e a reversão fica registada
+		Expect(reverted.Name).To(Equal("Caneca azul"))
+		Expect(reverted.Stock).To(Equal(4))
+		latest, err := productRepo.GetRevision(ctx, product.ID, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(latest.Action).To(Equal(domain.RevisionRevert))
+
+		_, err = productRepo.RevertToRevision(ctx, product.ID, 9, reverted.Version)
+		Expect(errors.Is(err, domain.ErrRevisionNotFound)).To(BeTrue())
+	})
+})
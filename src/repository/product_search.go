@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"product-service/src/domain"
+	"strconv"
+	"strings"
+)
+
+// Os termos encontrados são marcados com delimitadores próprios e só depois
+// convertidos em <mark>, para que o resto do texto possa ser escapado.
+const (
+	highlightStart = "{{mark}}"
+	highlightStop  = "{{/mark}}"
+)
+
+const (
+	nameHeadlineOptions    = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", HighlightAll=true`
+	snippetHeadlineOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxFragments=2, MaxWords=25, MinWords=8, FragmentDelimiter=" … "`
+)
+
+// searchCursorSort identifica os cursores da pesquisa, ordenada por relevância.
+const searchCursorSort = domain.ProductSortField("rank")
+
+var highlightReplacer = strings.NewReplacer(html.EscapeString(highlightStart), "<mark>", html.EscapeString(highlightStop), "</mark>")
+
+func renderHighlight(raw string) string {
+	return highlightReplacer.Replace(html.EscapeString(raw))
+}
+
+func (r *postgresProductRepository) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (*domain.ProductSearchPage, error) {
+
+	// A pesquisa é pública, por isso só devolve produtos ativos.
+	// $1 é a configuração de texto e $2 o texto pesquisado, na sintaxe de motores de busca (aspas, OR, -termo).
+	rank := `ts_rank_cd(p.search_vector, q.query)`
+	sql := `WITH q AS (SELECT websearch_to_tsquery($1::regconfig, $2) AS query)
+		SELECT ` + productColumns + `, ` + rank + `,
+			ts_headline($1::regconfig, p.name, q.query, '` + nameHeadlineOptions + `'),
+			ts_headline($1::regconfig, COALESCE(p.description, ''), q.query, '` + snippetHeadlineOptions + `')
+		FROM products p, q WHERE p.search_vector @@ q.query AND p.status = 'active' AND p.deleted_at IS NULL`
+	args := []any{string(query.Language), query.Text}
+
+	if query.Cursor != "" {
+		value, id, err := decodeProductCursor(query.Cursor, searchCursorSort, true)
+		if err != nil {
+			return nil, err
+		}
+		sql += ` AND (` + rank + `, p.id) < ($3::text::real, $4)`
+		args = append(args, value, id)
+	}
+	// Busca um registo a mais para saber se existe uma próxima página.
+	sql += fmt.Sprintf(` ORDER BY %s DESC, p.id DESC LIMIT %d`, rank, query.Limit+1)
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Error when searching products: %w", domain.ErrToSearchProducts)
+	}
+	defer rows.Close()
+
+	page := &domain.ProductSearchPage{Items: make([]*domain.ProductSearchResult, 0, query.Limit)}
+	for rows.Next() {
+		scanned := newProductRow()
+		result := &domain.ProductSearchResult{}
+		targets := append(scanned.targets(), &result.Rank, &result.NameHighlight, &result.DescriptionSnippet)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("error scanning product search row: %w", err)
+		}
+		product, err := scanned.finish()
+		if err != nil {
+			return nil, fmt.Errorf("error scanning product search row: %w", err)
+		}
+		result.Product = product
+		result.NameHighlight = renderHighlight(result.NameHighlight)
+		result.DescriptionSnippet = renderHighlight(result.DescriptionSnippet)
+		page.Items = append(page.Items, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when searching products: %w", domain.ErrToSearchProducts)
+	}
+
+	if len(page.Items) > query.Limit {
+		last := page.Items[query.Limit-1]
+		page.Items = page.Items[:query.Limit]
+		page.NextCursor = encodeProductCursor(searchCursorSort, true, strconv.FormatFloat(float64(last.Rank), 'g', -1, 32), last.ID)
+	}
+	return page, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Searching products", func() {
+	var productRepo ProductRepository
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should rank matches in the name above matches in the description", func() {
+		// Arrange: "cadeiras" aparece no nome de um produto e só na descrição do outro
+		inName := stubs.NewProductStub().WithName("Cadeiras de jardim").Get()
+		inName.Description = "Conjunto resistente ao sol"
+		inDescription := stubs.NewProductStub().WithName("Mesa de jardim").Get()
+		inDescription.Description = "Combina com as cadeiras da mesma coleção"
+		Expect(productRepo.Create(ctx, inDescription)).To(Succeed())
+		Expect(productRepo.Create(ctx, inName)).To(Succeed())
+
+		// Act: Pesquisa pelo singular, que partilha o radical em português
+		page, err := productRepo.SearchProducts(ctx, domain.ProductSearchQuery{Text: "cadeira", Language: domain.SearchPortuguese, Limit: 10})
+
+		// Assert: Os dois produtos são encontrados, com o nome a pesar mais
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items).To(HaveLen(2))
+		Expect(page.Items[0].ID).To(Equal(inName.ID))
+		Expect(page.Items[0].NameHighlight).To(Equal("<mark>Cadeiras</mark> de jardim"))
+		Expect(page.Items[1].DescriptionSnippet).To(ContainSubstring("<mark>cadeiras</mark>"))
+	})
+
+	It("should reflect updates to the product name", func() {
+		product := stubs.NewProductStub().WithName("Candeeiro de mesa").Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		product.Name = "Lamp"
+		Expect(productRepo.Update(ctx, product)).To(Succeed())
+
+		page, err := productRepo.SearchProducts(ctx, domain.ProductSearchQuery{Text: "lamps", Language: domain.SearchEnglish, Limit: 10})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items).To(HaveLen(1))
+		Expect(page.Items[0].ID).To(Equal(product.ID))
+	})
+
+	It("should escape the product text around the highlighted terms", func() {
+		product := stubs.NewProductStub().WithName("<b>Caneca</b> & pires").Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+
+		page, err := productRepo.SearchProducts(ctx, domain.ProductSearchQuery{Text: "caneca", Language: domain.SearchPortuguese, Limit: 10})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items).To(HaveLen(1))
+		Expect(page.Items[0].NameHighlight).NotTo(ContainSubstring("<b>"))
+		Expect(page.Items[0].NameHighlight).To(ContainSubstring("<mark>Caneca</mark>"))
+	})
+
+	It("should page through the results by relevance", func() {
+		for _, name := range []string{"Caneca azul", "Caneca verde", "Caneca branca"} {
+			Expect(productRepo.Create(ctx, stubs.NewProductStub().WithName(name).Get())).To(Succeed())
+		}
+		query := domain.ProductSearchQuery{Text: "caneca", Language: domain.SearchPortuguese, Limit: 2}
+
+		first, err := productRepo.SearchProducts(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+		query.Cursor = first.NextCursor
+		second, err := productRepo.SearchProducts(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(first.Items).To(HaveLen(2))
+		Expect(second.Items).To(HaveLen(1))
+		Expect(second.NextCursor).To(BeEmpty())
+		Expect(second.Items[0].ID).NotTo(BeElementOf(first.Items[0].ID, first.Items[1].ID))
+	})
+})
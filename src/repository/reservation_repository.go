@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReservationRepository interface {
+	Reserve(ctx context.Context, reservation *domain.Reservation) error
+	GetReservationByID(ctx context.Context, id uuid.UUID) (*domain.Reservation, error)
+	Commit(ctx context.Context, id uuid.UUID) error
+	Release(ctx context.Context, id uuid.UUID) error
+	ExpireReservations(ctx context.Context) (int64, error)
+}
+
+type postgresReservationRepository struct {
+	db         *pgxpool.Pool
+	allocation domain.AllocationStrategy
+}
+
+func NewReservation(db *pgxpool.Pool, allocation domain.AllocationStrategy) ReservationRepository {
+	return &postgresReservationRepository{db: db, allocation: allocation}
+}
+
+func (r *postgresReservationRepository) Reserve(ctx context.Context, reservation *domain.Reservation) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		available, err := lockAvailableStock(ctx, tx, reservation.ProductID)
+		// Um kit é reservado nos componentes, como reduceBundleStock os reduz.
+		var components []domain.BundleComponent
+		if errors.Is(err, domain.ErrBundleStock) {
+			if components, err = listBundleComponents(ctx, tx, reservation.ProductID); err != nil {
+				return err
+			}
+			available, err = lockBuildableStock(ctx, tx, components)
+		}
+		if err != nil {
+			return err
+		}
+
+		if available < reservation.Quantity {
+			return &domain.InsufficientStockError{ProductID: reservation.ProductID, Requested: reservation.Quantity, Available: available}
+		}
+
+		query := `INSERT INTO stock_reservations (id, product_id, order_reference, quantity, status, expires_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		_, err = tx.Exec(ctx, query, reservation.ID, reservation.ProductID, reservation.OrderReference, reservation.Quantity, reservation.Status, reservation.ExpiresAt, reservation.CreatedAt, reservation.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		// As reservas dos componentes seguem a do kit e são as que descontam stock disponível.
+		query = `INSERT INTO stock_reservations (id, product_id, order_reference, quantity, status, expires_at, created_at, updated_at, bundle_reservation_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+		for _, component := range components {
+			_, err = tx.Exec(ctx, query, uuid.New(), component.ProductID, reservation.OrderReference, reservation.Quantity*component.Quantity, reservation.Status,
+				reservation.ExpiresAt, reservation.CreatedAt, reservation.UpdatedAt, reservation.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrInsufficientStock) || errors.Is(err, domain.ErrProductArchived) {
+			return fmt.Errorf("Error when reserving stock: %w", err)
+		}
+		return fmt.Errorf("Error when reserving stock: %w", domain.ErrToReserveStock)
+	}
+	return nil
+}
+
+func (r *postgresReservationRepository) GetReservationByID(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+
+	query := `SELECT id, product_id, order_reference, quantity, status, expires_at, created_at, updated_at FROM stock_reservations WHERE id = $1 AND bundle_reservation_id IS NULL`
+	reservation := &domain.Reservation{}
+	err := r.db.QueryRow(ctx, query, id).Scan(&reservation.ID, &reservation.ProductID, &reservation.OrderReference, &reservation.Quantity, &reservation.Status, &reservation.ExpiresAt, &reservation.CreatedAt, &reservation.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when searching for reservation by ID: %w", domain.ErrReservationNotFound)
+		}
+		return nil, fmt.Errorf("Error when searching for reservation by ID: %w", err)
+	}
+	return reservation, nil
+}
+
+func (r *postgresReservationRepository) Commit(ctx context.Context, id uuid.UUID) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var productID uuid.UUID
+		var quantity int
+		var status domain.ReservationStatus
+		var expiresAt time.Time
+
+		query := `SELECT product_id, quantity, status, expires_at FROM stock_reservations WHERE id = $1 AND bundle_reservation_id IS NULL FOR UPDATE`
+		err := tx.QueryRow(ctx, query, id).Scan(&productID, &quantity, &status, &expiresAt)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrReservationNotFound
+			}
+			return err
+		}
+		if status != domain.ReservationActive {
+			return domain.ErrReservationNotActive
+		}
+		if !expiresAt.After(time.Now()) {
+			return domain.ErrReservationExpired
+		}
+
+		// A reserva de um kit desconta as quantidades reservadas em cada componente.
+		rows, err := tx.Query(ctx, `SELECT product_id, quantity FROM stock_reservations WHERE bundle_reservation_id = $1 ORDER BY product_id`, id)
+		if err != nil {
+			return err
+		}
+		parts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.BundleComponent, error) {
+			var part domain.BundleComponent
+			err := row.Scan(&part.ProductID, &part.Quantity)
+			return part, err
+		})
+		if err != nil {
+			return err
+		}
+		if len(parts) == 0 {
+			parts = []domain.BundleComponent{{ProductID: productID, Quantity: quantity}}
+		}
+		for _, part := range parts {
+			err = reduceProductStock(ctx, tx, part.ProductID, uuid.Nil, part.Quantity, r.allocation, domain.MovementReservationCommit, id.String())
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE stock_reservations SET status = $1, updated_at = NOW() WHERE id = $2 OR bundle_reservation_id = $2`, domain.ReservationCommitted, id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrReservationNotFound) || errors.Is(err, domain.ErrReservationNotActive) || errors.Is(err, domain.ErrReservationExpired) ||
+			errors.Is(err, domain.ErrInsufficientStock) {
+			return fmt.Errorf("Error when committing reservation: %w", err)
+		}
+		return fmt.Errorf("Error when committing reservation: %w", domain.ErrToReduceStock)
+	}
+	return nil
+}
+
+func (r *postgresReservationRepository) Release(ctx context.Context, id uuid.UUID) error {
+
+	query := `UPDATE stock_reservations SET status = $1, updated_at = NOW()
+		WHERE ((id = $2 AND bundle_reservation_id IS NULL) OR bundle_reservation_id = $2) AND status = $3`
+	tag, err := r.db.Exec(ctx, query, domain.ReservationReleased, id, domain.ReservationActive)
+	if err != nil {
+		return fmt.Errorf("Error when releasing reservation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.GetReservationByID(ctx, id); err != nil {
+			return fmt.Errorf("Error when releasing reservation: %w", err)
+		}
+		return fmt.Errorf("Error when releasing reservation: %w", domain.ErrReservationNotActive)
+	}
+	return nil
+}
+
+func (r *postgresReservationRepository) ExpireReservations(ctx context.Context) (int64, error) {
+
+	// As reservas dos componentes de um kit expiram com a do kit, mas não entram na contagem.
+	query := `WITH expired AS (UPDATE stock_reservations SET status = $1, updated_at = NOW() WHERE status = $2 AND expires_at <= NOW()
+		RETURNING bundle_reservation_id) SELECT COUNT(*) FROM expired WHERE bundle_reservation_id IS NULL`
+	var expired int64
+	if err := r.db.QueryRow(ctx, query, domain.ReservationExpired, domain.ReservationActive).Scan(&expired); err != nil {
+		return 0, fmt.Errorf("Error when expiring reservations: %w", err)
+	}
+	return expired, nil
+}
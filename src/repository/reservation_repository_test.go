@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/seeder"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReservationRepository", func() {
+	var reservationRepo ReservationRepository
+	var productRepo ProductRepository
+	var testSeeder *seeder.TestSeeder
+	var ctx context.Context
+
+	newReservation := func(productID uuid.UUID, quantity int, ttl time.Duration) *domain.Reservation {
+		now := time.Now().UTC()
+		return &domain.Reservation{
+			ID:             uuid.New(),
+			ProductID:      productID,
+			OrderReference: "order-123",
+			Quantity:       quantity,
+			Status:         domain.ReservationActive,
+			ExpiresAt:      now.Add(ttl),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reservationRepo = NewReservation(db, domain.AllocationPriority)
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		testSeeder = seeder.NewTestSeeder(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Reserving stock", func() {
+		It("should reduce the available stock without touching the on-hand stock", func() {
+			// Arrange: Insere um produto com 10 unidades
+			product := stubs.NewProductStub().WithStock(10).Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+
+			// Act: Reserva 4 unidades
+			err := reservationRepo.Reserve(ctx, newReservation(product.ID, 4, time.Minute))
+
+			// Assert: O stock disponível reflete a reserva
+			Expect(err).NotTo(HaveOccurred())
+			foundProduct, err := productRepo.GetProductByID(ctx, product.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundProduct.Stock).To(Equal(10))
+			Expect(foundProduct.AvailableStock).To(Equal(6))
+		})
+
+		It("should reject a reservation larger than the available stock", func() {
+			// Arrange: Insere um produto e reserva parte do stock
+			product := stubs.NewProductStub().WithStock(5).Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+			Expect(reservationRepo.Reserve(ctx, newReservation(product.ID, 3, time.Minute))).To(Succeed())
+
+			// Act: Tenta reservar mais do que o disponível
+			err := reservationRepo.Reserve(ctx, newReservation(product.ID, 3, time.Minute))
+
+			// Assert: Verifica se o erro é de stock insuficiente
+			Expect(errors.Is(err, domain.ErrInsufficientStock)).To(BeTrue())
+		})
+	})
+
+	Describe("Committing a reservation", func() {
+		It("should decrement the on-hand stock", func() {
+			// Arrange: Insere um produto e cria uma reserva
+			product := stubs.NewProductStub().WithStock(10).Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+			reservation := newReservation(product.ID, 4, time.Minute)
+			Expect(reservationRepo.Reserve(ctx, reservation)).To(Succeed())
+
+			// Act: Confirma a reserva
+			err := reservationRepo.Commit(ctx, reservation.ID)
+
+			// Assert: O stock em mão foi reduzido e a reserva deixou de contar
+			Expect(err).NotTo(HaveOccurred())
+			foundProduct, err := productRepo.GetProductByID(ctx, product.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundProduct.Stock).To(Equal(6))
+			Expect(foundProduct.AvailableStock).To(Equal(6))
+
+			// Verify: Não é possível confirmar duas vezes
+			err = reservationRepo.Commit(ctx, reservation.ID)
+			Expect(errors.Is(err, domain.ErrReservationNotActive)).To(BeTrue())
+		})
+	})
+
+	Describe("Reserving a bundle", func() {
+		It("should reserve and commit the components of the bundle", func() {
+			// Arrange: um kit com 1 unidade do primeiro produto e 2 do segundo
+			bundle := stubs.NewProductStub().WithStock(0).Get()
+			first := stubs.NewProductStub().WithStock(5).Get()
+			second := stubs.NewProductStub().WithStock(7).Get()
+			for _, product := range []*domain.Product{bundle, first, second} {
+				Expect(productRepo.Create(ctx, product)).To(Succeed())
+			}
+			Expect(NewBundle(db).SetComponents(ctx, bundle.ID, []domain.BundleComponent{
+				{ProductID: first.ID, Quantity: 1},
+				{ProductID: second.ID, Quantity: 2},
+			})).To(Succeed())
+
+			// Act: reserva 2 dos 3 kits possíveis
+			reservation := newReservation(bundle.ID, 2, time.Minute)
+			Expect(reservationRepo.Reserve(ctx, reservation)).To(Succeed())
+
+			// Assert: os componentes reservados deixam de contar no stock disponível
+			foundSecond, err := productRepo.GetProductByID(ctx, second.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundSecond.AvailableStock).To(Equal(3))
+			foundBundle, err := productRepo.GetProductByID(ctx, bundle.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundBundle.AvailableStock).To(Equal(1))
+			var stockErr *domain.InsufficientStockError
+			Expect(errors.As(reservationRepo.Reserve(ctx, newReservation(bundle.ID, 2, time.Minute)), &stockErr)).To(BeTrue())
+			Expect(stockErr.Available).To(Equal(1))
+
+			// Act: confirma a reserva
+			Expect(reservationRepo.Commit(ctx, reservation.ID)).To(Succeed())
+
+			// Assert: o stock dos componentes é descontado e o disponível mantém-se
+			foundFirst, err := productRepo.GetProductByID(ctx, first.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundFirst.Stock).To(Equal(3))
+			Expect(foundFirst.AvailableStock).To(Equal(3))
+			foundSecond, err = productRepo.GetProductByID(ctx, second.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundSecond.Stock).To(Equal(3))
+			Expect(foundSecond.AvailableStock).To(Equal(3))
+		})
+
+		It("should return the components to the available stock on release", func() {
+			bundle := stubs.NewProductStub().WithStock(0).Get()
+			component := stubs.NewProductStub().WithStock(4).Get()
+			for _, product := range []*domain.Product{bundle, component} {
+				Expect(productRepo.Create(ctx, product)).To(Succeed())
+			}
+			Expect(NewBundle(db).SetComponents(ctx, bundle.ID, []domain.BundleComponent{{ProductID: component.ID, Quantity: 2}})).To(Succeed())
+			reservation := newReservation(bundle.ID, 2, time.Minute)
+			Expect(reservationRepo.Reserve(ctx, reservation)).To(Succeed())
+
+			// Act
+			Expect(reservationRepo.Release(ctx, reservation.ID)).To(Succeed())
+
+			// Assert
+			found, err := productRepo.GetProductByID(ctx, component.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.AvailableStock).To(Equal(4))
+		})
+	})
+
+	Describe("Expiring reservations", func() {
+		It("should return expired reservations to the available stock", func() {
+			// Arrange: Cria uma reserva já expirada
+			product := stubs.NewProductStub().WithStock(10).Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+			reservation := newReservation(product.ID, 4, -time.Minute)
+			Expect(reservationRepo.Reserve(ctx, reservation)).To(Succeed())
+
+			// Act: Executa a expiração
+			expired, err := reservationRepo.ExpireReservations(ctx)
+
+			// Assert: A reserva foi expirada e não pode ser confirmada
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expired).To(Equal(int64(1)))
+			err = reservationRepo.Commit(ctx, reservation.ID)
+			Expect(errors.Is(err, domain.ErrReservationNotActive)).To(BeTrue())
+		})
+	})
+})
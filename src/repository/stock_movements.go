@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+)
+
+func insertStockMovement(ctx context.Context, tx dbtx, movement *domain.StockMovement) error {
+
+	query := `INSERT INTO stock_movements (id, product_id, warehouse_id, variant_id, delta, balance, reason, reference, actor_id, correlation_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), $11)`
+	_, err := tx.Exec(ctx, query, movement.ID, movement.ProductID, movement.WarehouseID, movement.VariantID, movement.Delta, movement.Balance, movement.Reason,
+		movement.Reference, movement.ActorID, movement.CorrelationID, movement.CreatedAt)
+	return err
+}
+
+func (r *postgresProductRepository) ListStockMovements(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.StockMovementPage, error) {
+
+	query := `SELECT id, product_id, warehouse_id, variant_id, delta, balance, reason, COALESCE(reference, ''), COALESCE(actor_id, ''), COALESCE(correlation_id, ''), created_at
+		FROM stock_movements WHERE product_id = $1`
+	args := []any{productID}
+	if cursor != "" {
+		createdAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND (created_at, id) < ($2, $3)`
+		args = append(args, createdAt, id)
+	}
+	// Busca um registo a mais para saber se existe uma próxima página.
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT %d`, limit+1)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing stock movements: %w", domain.ErrToListStockMovements)
+	}
+	defer rows.Close()
+
+	page := &domain.StockMovementPage{Items: make([]*domain.StockMovement, 0, limit)}
+	for rows.Next() {
+		movement := &domain.StockMovement{}
+		err := rows.Scan(&movement.ID, &movement.ProductID, &movement.WarehouseID, &movement.VariantID, &movement.Delta, &movement.Balance, &movement.Reason,
+			&movement.Reference, &movement.ActorID, &movement.CorrelationID, &movement.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning stock movement row: %w", err)
+		}
+		page.Items = append(page.Items, movement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when listing stock movements: %w", domain.ErrToListStockMovements)
+	}
+
+	if len(page.Items) > limit {
+		last := page.Items[limit-1]
+		page.Items = page.Items[:limit]
+		page.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+	return page, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stock movements", func() {
+	var productRepo ProductRepository
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.WithValue(context.Background(), domain.UserIDContextKey, "user-42")
+		ctx = context.WithValue(ctx, domain.CorrelationIDContextKey, "corr-1")
+		productRepo = NewProduct(db, domain.AllocationPriority)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should record every stock change with its resulting balance", func() {
+		// Arrange: Cria um produto com 10 unidades
+		product := stubs.NewProductStub().WithStock(10).Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+
+		// Act: Reduz o stock duas vezes
+		Expect(productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 3)).To(Succeed())
+		Expect(productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 2)).To(Succeed())
+
+		// Assert: Os movimentos são devolvidos do mais recente para o mais antigo
+		page, err := productRepo.ListStockMovements(ctx, product.ID, 10, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items).To(HaveLen(3))
+		Expect(page.Items[0].Delta).To(Equal(-2))
+		Expect(page.Items[0].Balance).To(Equal(5))
+		Expect(page.Items[0].Reason).To(Equal(domain.MovementReduce))
+		Expect(page.Items[0].ActorID).To(Equal("user-42"))
+		Expect(page.Items[0].CorrelationID).To(Equal("corr-1"))
+		Expect(page.Items[2].Reason).To(Equal(domain.MovementCreate))
+		Expect(page.Items[2].Balance).To(Equal(10))
+	})
+
+	It("should page through the history using the cursor", func() {
+		// Arrange: Cria um produto e gera três movimentos
+		product := stubs.NewProductStub().WithStock(10).Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		Expect(productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 1)).To(Succeed())
+		Expect(productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 1)).To(Succeed())
+
+		// Act: Lê a primeira página com dois movimentos
+		first, err := productRepo.ListStockMovements(ctx, product.ID, 2, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.Items).To(HaveLen(2))
+		Expect(first.NextCursor).NotTo(BeEmpty())
+
+		// Assert: A segunda página contém o movimento restante
+		second, err := productRepo.ListStockMovements(ctx, product.ID, 2, first.NextCursor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.Items).To(HaveLen(1))
+		Expect(second.Items[0].Reason).To(Equal(domain.MovementCreate))
+		Expect(second.NextCursor).To(BeEmpty())
+	})
+
+	It("should apply restocks and adjustments as relative deltas", func() {
+		// Arrange: Cria um produto com 10 unidades e reduz 4 unidades
+		product := stubs.NewProductStub().WithStock(10).Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		Expect(productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 4)).To(Succeed())
+
+		// Act: Recebe uma entrega e depois corrige a contagem
+		balance, err := productRepo.IncreaseStock(ctx, product.ID, uuid.Nil, 20, "NF-1234")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(balance).To(Equal(26))
+		balance, err = productRepo.SetStock(ctx, product.ID, uuid.Nil, 25, "unidade danificada")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(balance).To(Equal(25))
+
+		// Assert: Os movimentos registam a reposição e o ajuste
+		page, err := productRepo.ListStockMovements(ctx, product.ID, 10, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items[0].Reason).To(Equal(domain.MovementAdjustment))
+		Expect(page.Items[0].Delta).To(Equal(-1))
+		Expect(page.Items[0].Reference).To(Equal("unidade danificada"))
+		Expect(page.Items[1].Reason).To(Equal(domain.MovementRestock))
+		Expect(page.Items[1].Delta).To(Equal(20))
+	})
+	It("should keep the history after the product is purged and refuse to delete it", func() {
+		// Arrange: Cria um produto com movimentos e remove-o
+		product := stubs.NewProductStub().WithStock(10).Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		Expect(productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 3)).To(Succeed())
+		Expect(productRepo.Delete(ctx, product.ID, 2)).To(Succeed())
+
+		// Act: Purga o produto
+		purged, _, err := productRepo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(Equal(int64(1)))
+
+		// Assert: Os movimentos continuam lá e não podem ser apagados
+		var count int
+		Expect(db.QueryRow(ctx, `SELECT COUNT(*) FROM stock_movements WHERE product_id = $1`, product.ID).Scan(&count)).To(Succeed())
+		Expect(count).To(Equal(2))
+		_, err = db.Exec(ctx, `DELETE FROM stock_movements WHERE product_id = $1`, product.ID)
+		Expect(err).To(HaveOccurred())
+	})
+})
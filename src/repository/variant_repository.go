@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type VariantRepository interface {
+	// SetProductOptions substitui as opções do produto; só é permitido enquanto o produto não tem variantes.
+	SetProductOptions(ctx context.Context, productID uuid.UUID, options []domain.ProductOption) error
+	// ListProductOptions e ListVariants só leem produtos no estado status; vazio lê em qualquer estado.
+	ListProductOptions(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]domain.ProductOption, error)
+	Create(ctx context.Context, variant *domain.Variant) error
+	ListVariants(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Variant, error)
+}
+
+type postgresVariantRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewVariant(db *pgxpool.Pool) VariantRepository {
+	return &postgresVariantRepository{db: db}
+}
+
+// Tal como nos produtos, os preços são lidos como texto para não passar por vírgula flutuante.
+const variantColumns = `v.id, v.product_id, v.sku, v.options, v.price::text, v.currency,
+	COALESCE(v.price, p.price)::text, COALESCE(v.currency, p.currency), v.stock, v.created_at, v.updated_at`
+
+func scanVariant(row pgx.Row) (*domain.Variant, error) {
+	variant := &domain.Variant{}
+	var price, currency *string
+	var effectivePrice, effectiveCurrency string
+	err := row.Scan(&variant.ID, &variant.ProductID, &variant.SKU, &variant.Options, &price, &currency,
+		&effectivePrice, &effectiveCurrency, &variant.Stock, &variant.CreatedAt, &variant.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if price != nil && currency != nil {
+		override, err := domain.ParseMoney(*price, *currency)
+		if err != nil {
+			return nil, err
+		}
+		variant.Price = &override
+	}
+	if variant.EffectivePrice, err = domain.ParseMoney(effectivePrice, effectiveCurrency); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+func listProductOptions(ctx context.Context, db dbtx, productID uuid.UUID) ([]domain.ProductOption, error) {
+	rows, err := db.Query(ctx, `SELECT name, option_values FROM product_options WHERE product_id = $1 ORDER BY position`, productID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[domain.ProductOption])
+}
+
+// variantSaveError traduz as violações de unicidade da variante para o erro de domínio correspondente.
+func variantSaveError(err error) error {
+	var pgErr *pgconn.PgError
+	switch {
+	case isUniqueViolation(err) && errors.As(err, &pgErr) && pgErr.ConstraintName == "product_variants_options_key":
+		return domain.ErrVariantOptionsTaken
+	case isUniqueViolation(err):
+		return domain.ErrSKUTaken
+	case errors.Is(err, domain.ErrSKUTaken), errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrInvalidVariantOptions), errors.Is(err, domain.ErrCurrencyMismatch),
+		errors.Is(err, domain.ErrBundleStock):
+		return err
+	default:
+		return domain.ErrToSaveVariant
+	}
+}
+
+func (r *postgresVariantRepository) SetProductOptions(ctx context.Context, productID uuid.UUID, options []domain.ProductOption) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var hasVariants bool
+		query := `SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = p.id) FROM products p WHERE p.id = $1 AND p.deleted_at IS NULL FOR UPDATE`
+		if err := tx.QueryRow(ctx, query, productID).Scan(&hasVariants); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+		if hasVariants {
+			return domain.ErrProductHasVariants
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM product_options WHERE product_id = $1`, productID); err != nil {
+			return err
+		}
+		for position, option := range options {
+			query := `INSERT INTO product_options (product_id, name, position, option_values) VALUES ($1, $2, $3, $4)`
+			if _, err := tx.Exec(ctx, query, productID, option.Name, position, option.Values); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrProductHasVariants) {
+			return fmt.Errorf("Error when setting product options: %w", err)
+		}
+		return fmt.Errorf("Error when setting product options: %w", domain.ErrToSaveProductOptions)
+	}
+	return nil
+}
+
+func (r *postgresVariantRepository) ListProductOptions(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]domain.ProductOption, error) {
+
+	query := `SELECT o.name, o.option_values FROM product_options o JOIN products p ON p.id = o.product_id
+		WHERE o.product_id = $1 AND p.deleted_at IS NULL AND ($2::text = '' OR p.status = $2) ORDER BY o.position`
+	rows, err := r.db.Query(ctx, query, productID, status)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing product options: %w", err)
+	}
+	options, err := pgx.CollectRows(rows, pgx.RowToStructByPos[domain.ProductOption])
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing product options: %w", err)
+	}
+	return options, nil
+}
+
+func (r *postgresVariantRepository) Create(ctx context.Context, variant *domain.Variant) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		// Bloqueia o produto contra alterações de opções enquanto a variante é validada.
+		var price, currency string
+		var productType domain.ProductType
+		query := `SELECT price::text, currency, type FROM products WHERE id = $1 AND deleted_at IS NULL FOR SHARE`
+		if err := tx.QueryRow(ctx, query, variant.ProductID).Scan(&price, &currency, &productType); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+		// As variantes têm stock próprio, que um kit não pode ter.
+		if productType == domain.ProductBundle {
+			return domain.ErrBundleStock
+		}
+
+		options, err := listProductOptions(ctx, tx, variant.ProductID)
+		if err != nil {
+			return err
+		}
+		if err := domain.MatchProductOptions(options, variant.Options); err != nil {
+			return err
+		}
+
+		var overrideAmount, overrideCurrency *string
+		if variant.Price != nil {
+			if variant.Price.Currency != currency {
+				return fmt.Errorf("%w: variant price must be in %s", domain.ErrCurrencyMismatch, currency)
+			}
+			amount := variant.Price.Amount()
+			overrideAmount, overrideCurrency = &amount, &variant.Price.Currency
+			variant.EffectivePrice = *variant.Price
+		} else if variant.EffectivePrice, err = domain.ParseMoney(price, currency); err != nil {
+			return err
+		}
+
+		if err := claimSKU(ctx, tx, variant.SKU, skuUsed); err != nil {
+			return err
+		}
+		query = `INSERT INTO product_variants (id, product_id, sku, options, price, currency, stock, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5::text::numeric, $6, $7, $8, $9)`
+		_, err = tx.Exec(ctx, query, variant.ID, variant.ProductID, variant.SKU, variant.Options, overrideAmount, overrideCurrency,
+			variant.Stock, variant.CreatedAt, variant.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		movement := domain.NewStockMovement(ctx, variant.ProductID, variant.Stock, variant.Stock, domain.MovementCreate, "")
+		movement.VariantID = &variant.ID
+		return insertStockMovement(ctx, tx, movement)
+	})
+	if err != nil {
+		return fmt.Errorf("Error creating variant: %w", variantSaveError(err))
+	}
+	return nil
+}
+
+func (r *postgresVariantRepository) ListVariants(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Variant, error) {
+
+	query := `SELECT ` + variantColumns + ` FROM product_variants v JOIN products p ON p.id = v.product_id
+		WHERE v.product_id = $1 AND p.deleted_at IS NULL AND ($2::text = '' OR p.status = $2) ORDER BY v.created_at, v.sku`
+	rows, err := r.db.Query(ctx, query, productID, status)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing variants: %w", err)
+	}
+	defer rows.Close()
+
+	variants := make([]*domain.Variant, 0)
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning variant row: %w", err)
+		}
+		variants = append(variants, variant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Error when listing variants: %w", err)
+	}
+	return variants, nil
+}
+
+// reduceVariantStock retira quantity unidades do stock próprio da variante e
+// regista o movimento no produto com a variante indicada.
+func reduceVariantStock(ctx context.Context, tx dbtx, productID, variantID uuid.UUID, quantity int, reason domain.MovementReason, reference string) error {
+	var stock int
+	var status domain.ProductStatus
+	query := `SELECT v.stock, p.status FROM product_variants v JOIN products p ON p.id = v.product_id
+		WHERE v.id = $1 AND v.product_id = $2 AND p.deleted_at IS NULL FOR UPDATE OF v`
+	if err := tx.QueryRow(ctx, query, variantID, productID).Scan(&stock, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrVariantNotFound
+		}
+		return err
+	}
+	if status == domain.StatusArchived {
+		return domain.ErrProductArchived
+	}
+	if stock < quantity {
+		return &domain.InsufficientStockError{ProductID: productID, Requested: quantity, Available: stock}
+	}
+
+	var balance int
+	query = `UPDATE product_variants SET stock = stock - $1, updated_at = NOW() WHERE id = $2 RETURNING stock`
+	if err := tx.QueryRow(ctx, query, quantity, variantID).Scan(&balance); err != nil {
+		return err
+	}
+
+	movement := domain.NewStockMovement(ctx, productID, -quantity, balance, reason, reference)
+	movement.VariantID = &variantID
+	return insertStockMovement(ctx, tx, movement)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Variants", func() {
+	var productRepo ProductRepository
+	var variantRepo VariantRepository
+	var ctx context.Context
+	var product *domain.Product
+
+	newVariant := func(sku string, options map[string]string, stock int) *domain.Variant {
+		now := time.Now().UTC()
+		return &domain.Variant{ID: uuid.New(), ProductID: product.ID, SKU: sku, Options: options, Stock: stock, CreatedAt: now, UpdatedAt: now}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		variantRepo = NewVariant(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+
+		product = stubs.NewProductStub().WithPrice("49.90").Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		options := []domain.ProductOption{{Name: "tamanho", Values: []string{"P", "M", "G"}}, {Name: "cor", Values: []string{"azul", "preto"}}}
+		Expect(variantRepo.SetProductOptions(ctx, product.ID, options)).To(Succeed())
+	})
+
+	It("should list the variants with the price inherited from the product or overridden", func() {
+		// Arrange: uma variante sem preço próprio e outra mais cara
+		Expect(variantRepo.Create(ctx, newVariant("CAM-AZ-M", map[string]string{"tamanho": "M", "cor": "azul"}, 3))).To(Succeed())
+		expensive := newVariant("CAM-PR-G", map[string]string{"tamanho": "G", "cor": "preto"}, 1)
+		expensive.Price = &domain.Money{Minor: 5990, Currency: "BRL"}
+		Expect(variantRepo.Create(ctx, expensive)).To(Succeed())
+
+		// Act
+		variants, err := variantRepo.ListVariants(ctx, product.ID, "")
+
+		// Assert
+		Expect(err).NotTo(HaveOccurred())
+		Expect(variants).To(HaveLen(2))
+		Expect(variants[0].Price).To(BeNil())
+		Expect(variants[0].EffectivePrice.Amount()).To(Equal("49.90"))
+		Expect(variants[1].EffectivePrice.Amount()).To(Equal("59.90"))
+		Expect(variants[1].Options).To(Equal(map[string]string{"tamanho": "G", "cor": "preto"}))
+	})
+
+	It("should hide the options and variants of a product that is not active from public reads", func() {
+		Expect(variantRepo.Create(ctx, newVariant("CAM-AZ-M", map[string]string{"tamanho": "M", "cor": "azul"}, 3))).To(Succeed())
+		_, err := productRepo.SetStatus(ctx, product.ID, domain.StatusArchived)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Act
+		publicVariants, err := variantRepo.ListVariants(ctx, product.ID, domain.StatusActive)
+		Expect(err).NotTo(HaveOccurred())
+		publicOptions, err := variantRepo.ListProductOptions(ctx, product.ID, domain.StatusActive)
+		Expect(err).NotTo(HaveOccurred())
+		adminVariants, err := variantRepo.ListVariants(ctx, product.ID, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Assert
+		Expect(publicVariants).To(BeEmpty())
+		Expect(publicOptions).To(BeEmpty())
+		Expect(adminVariants).To(HaveLen(1))
+	})
+
+	It("should reject a variant whose options do not match the product", func() {
+		err := variantRepo.Create(ctx, newVariant("CAM-AZ-XG", map[string]string{"tamanho": "XG", "cor": "azul"}, 1))
+
+		Expect(errors.Is(err, domain.ErrInvalidVariantOptions)).To(BeTrue())
+	})
+
+	It("should reject a repeated SKU or combination of options", func() {
+		Expect(variantRepo.Create(ctx, newVariant("CAM-AZ-M", map[string]string{"tamanho": "M", "cor": "azul"}, 1))).To(Succeed())
+
+		err := variantRepo.Create(ctx, newVariant("CAM-AZ-M", map[string]string{"tamanho": "P", "cor": "azul"}, 1))
+		Expect(errors.Is(err, domain.ErrSKUTaken)).To(BeTrue())
+
+		err = variantRepo.Create(ctx, newVariant("CAM-AZ-M-2", map[string]string{"cor": "azul", "tamanho": "M"}, 1))
+		Expect(errors.Is(err, domain.ErrVariantOptionsTaken)).To(BeTrue())
+	})
+
+	It("should share the SKU namespace with products and find the variant by its SKU", func() {
+		// Arrange: uma variante e outro produto
+		variant := newVariant("CAM-AZ-M", map[string]string{"tamanho": "M", "cor": "azul"}, 1)
+		Expect(variantRepo.Create(ctx, variant)).To(Succeed())
+		other := stubs.NewProductStub().Get()
+		Expect(productRepo.Create(ctx, other)).To(Succeed())
+
+		// Act & Assert: nenhum dos dois pode usar o SKU do outro
+		err := variantRepo.Create(ctx, newVariant(other.SKU, map[string]string{"tamanho": "P", "cor": "azul"}, 1))
+		Expect(errors.Is(err, domain.ErrSKUTaken)).To(BeTrue())
+		err = productRepo.Create(ctx, stubs.NewProductStub().WithSKU("CAM-AZ-M").Get())
+		Expect(errors.Is(err, domain.ErrSKUTaken)).To(BeTrue())
+
+		// Assert: o SKU da variante encontra o produto com a variante
+		found, err := productRepo.GetProductBySKU(ctx, "CAM-AZ-M")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.ID).To(Equal(product.ID))
+		Expect(found.Variant).NotTo(BeNil())
+		Expect(found.Variant.ID).To(Equal(variant.ID))
+	})
+
+	It("should free the variant SKUs of a deleted product and check them on restore", func() {
+		// Arrange: remove o produto com uma variante
+		Expect(variantRepo.Create(ctx, newVariant("CAM-AZ-M", map[string]string{"tamanho": "M", "cor": "azul"}, 1))).To(Succeed())
+		current, err := productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(productRepo.Delete(ctx, product.ID, current.Version)).To(Succeed())
+
+		// Act: outro produto passa a usar o SKU da variante
+		other := stubs.NewProductStub().WithSKU("CAM-AZ-M").Get()
+		Expect(productRepo.Create(ctx, other)).To(Succeed())
+
+		// Assert: o restauro é recusado e o produto continua removido
+		_, err = productRepo.Restore(ctx, product.ID)
+		Expect(errors.Is(err, domain.ErrSKUTaken)).To(BeTrue())
+		_, err = productRepo.GetProductByID(ctx, product.ID)
+		Expect(errors.Is(err, domain.ErrProductNotFound)).To(BeTrue())
+
+		// Act & Assert: sem o outro produto, o restauro devolve a variante
+		Expect(productRepo.Delete(ctx, other.ID, 1)).To(Succeed())
+		_, err = productRepo.Restore(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		found, err := productRepo.GetProductBySKU(ctx, "CAM-AZ-M")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.ID).To(Equal(product.ID))
+	})
+
+	It("should not change the options of a product with variants", func() {
+		Expect(variantRepo.Create(ctx, newVariant("CAM-AZ-M", map[string]string{"tamanho": "M", "cor": "azul"}, 1))).To(Succeed())
+
+		err := variantRepo.SetProductOptions(ctx, product.ID, []domain.ProductOption{{Name: "tamanho", Values: []string{"M"}}})
+
+		Expect(errors.Is(err, domain.ErrProductHasVariants)).To(BeTrue())
+	})
+
+	It("should reduce only the stock of the targeted variant", func() {
+		// Arrange
+		variant := newVariant("CAM-AZ-M", map[string]string{"tamanho": "M", "cor": "azul"}, 3)
+		Expect(variantRepo.Create(ctx, variant)).To(Succeed())
+
+		// Act
+		Expect(productRepo.ReduceStock(ctx, product.ID, uuid.Nil, variant.ID, 2)).To(Succeed())
+		err := productRepo.ReduceStock(ctx, product.ID, uuid.Nil, variant.ID, 2)
+
+		// Assert: a segunda redução excede o stock da variante e o produto não é afetado
+		var stockErr *domain.InsufficientStockError
+		Expect(errors.As(err, &stockErr)).To(BeTrue())
+		Expect(stockErr.Available).To(Equal(1))
+
+		found, err := productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Stock).To(Equal(product.Stock))
+
+		page, err := productRepo.ListStockMovements(ctx, product.ID, 1, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*page.Items[0].VariantID).To(Equal(variant.ID))
+		Expect(page.Items[0].Balance).To(Equal(1))
+	})
+})
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *domain.Warehouse) error
+	GetWarehouseByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	ListWarehouses(ctx context.Context) ([]*domain.Warehouse, error)
+	Update(ctx context.Context, warehouse *domain.Warehouse) error
+	Transfer(ctx context.Context, transfer *domain.StockTransfer) error
+	GetProductInventory(ctx context.Context, productID uuid.UUID) (*domain.ProductInventory, error)
+}
+
+type postgresWarehouseRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWarehouse(db *pgxpool.Pool) WarehouseRepository {
+	return &postgresWarehouseRepository{db: db}
+}
+
+func (r *postgresWarehouseRepository) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+
+	query := `INSERT INTO warehouses (id, code, name, priority, active, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.db.Exec(ctx, query, warehouse.ID, warehouse.Code, warehouse.Name, warehouse.Priority, warehouse.Active, warehouse.CreatedAt, warehouse.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("Error creating warehouse: %w", domain.ErrWarehouseCodeTaken)
+		}
+		return fmt.Errorf("Error creating warehouse: %w", domain.ErrToSaveWarehouse)
+	}
+	return nil
+}
+
+func (r *postgresWarehouseRepository) GetWarehouseByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+
+	query := `SELECT id, code, name, priority, active, created_at, updated_at FROM warehouses WHERE id = $1`
+	warehouse := &domain.Warehouse{}
+	err := r.db.QueryRow(ctx, query, id).Scan(&warehouse.ID, &warehouse.Code, &warehouse.Name, &warehouse.Priority, &warehouse.Active, &warehouse.CreatedAt, &warehouse.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when searching for warehouse by ID: %w", domain.ErrWarehouseNotFound)
+		}
+		return nil, fmt.Errorf("Error when searching for warehouse by ID: %w", err)
+	}
+	return warehouse, nil
+}
+
+func (r *postgresWarehouseRepository) ListWarehouses(ctx context.Context) ([]*domain.Warehouse, error) {
+
+	query := `SELECT id, code, name, priority, active, created_at, updated_at FROM warehouses ORDER BY priority, code`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing warehouses: %w", err)
+	}
+	defer rows.Close()
+
+	warehouses := make([]*domain.Warehouse, 0)
+	for rows.Next() {
+		warehouse := &domain.Warehouse{}
+		err := rows.Scan(&warehouse.ID, &warehouse.Code, &warehouse.Name, &warehouse.Priority, &warehouse.Active, &warehouse.CreatedAt, &warehouse.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning warehouse row: %w", err)
+		}
+		warehouses = append(warehouses, warehouse)
+	}
+
+	return warehouses, nil
+}
+
+func (r *postgresWarehouseRepository) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+
+	query := `UPDATE warehouses SET code = $1, name = $2, priority = $3, active = $4, updated_at = $5 WHERE id = $6`
+	tag, err := r.db.Exec(ctx, query, warehouse.Code, warehouse.Name, warehouse.Priority, warehouse.Active, warehouse.UpdatedAt, warehouse.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("Error when updating warehouse: %w", domain.ErrWarehouseCodeTaken)
+		}
+		return fmt.Errorf("Error when updating warehouse: %w", domain.ErrToSaveWarehouse)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("Error when updating warehouse: %w", domain.ErrWarehouseNotFound)
+	}
+	return nil
+}
+
+// Transfer move stock entre dois armazéns. O total do produto não muda; a
+// saída e a entrada ficam registadas como movimentos ligados pelo ID da transferência.
+func (r *postgresWarehouseRepository) Transfer(ctx context.Context, transfer *domain.StockTransfer) error {
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var balance int
+		err := tx.QueryRow(ctx, `SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, transfer.ProductID).Scan(&balance)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrProductNotFound
+			}
+			return err
+		}
+
+		// Bloqueia os dois locais sempre pela mesma ordem para evitar deadlocks.
+		first, second := transfer.FromWarehouseID, transfer.ToWarehouseID
+		if bytes.Compare(first[:], second[:]) > 0 {
+			first, second = second, first
+		}
+		quantities := make(map[uuid.UUID]int, 2)
+		for _, warehouseID := range []uuid.UUID{first, second} {
+			if err := lockWarehouse(ctx, tx, warehouseID); err != nil {
+				return err
+			}
+			quantity, err := lockLocationStock(ctx, tx, transfer.ProductID, warehouseID)
+			if err != nil {
+				return err
+			}
+			quantities[warehouseID] = quantity
+		}
+
+		if available := quantities[transfer.FromWarehouseID]; available < transfer.Quantity {
+			return &domain.InsufficientStockError{ProductID: transfer.ProductID, Requested: transfer.Quantity, Available: available}
+		}
+
+		query := `INSERT INTO warehouse_stock (warehouse_id, product_id, quantity, updated_at) VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity, updated_at = NOW()`
+		legs := []struct {
+			warehouseID uuid.UUID
+			delta       int
+			reason      domain.MovementReason
+		}{
+			{transfer.FromWarehouseID, -transfer.Quantity, domain.MovementTransferOut},
+			{transfer.ToWarehouseID, transfer.Quantity, domain.MovementTransferIn},
+		}
+		for _, leg := range legs {
+			if _, err := tx.Exec(ctx, query, leg.warehouseID, transfer.ProductID, quantities[leg.warehouseID]+leg.delta); err != nil {
+				return err
+			}
+
+			movement := domain.NewStockMovement(ctx, transfer.ProductID, leg.delta, balance, leg.reason, transfer.ID.String())
+			movement.WarehouseID = &leg.warehouseID
+			movement.CreatedAt = transfer.CreatedAt
+			if err := insertStockMovement(ctx, tx, movement); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) || errors.Is(err, domain.ErrInsufficientStock) ||
+			errors.Is(err, domain.ErrWarehouseNotFound) || errors.Is(err, domain.ErrWarehouseInactive) {
+			return fmt.Errorf("Error when transferring stock: %w", err)
+		}
+		return fmt.Errorf("Error when transferring stock: %w", domain.ErrToTransferStock)
+	}
+	return nil
+}
+
+func (r *postgresWarehouseRepository) GetProductInventory(ctx context.Context, productID uuid.UUID) (*domain.ProductInventory, error) {
+
+	query := `SELECT p.stock,
+		p.stock - COALESCE((SELECT SUM(r.quantity) FROM stock_reservations r WHERE r.product_id = p.id AND r.status = 'active' AND r.expires_at > NOW()), 0)
+		FROM products p WHERE p.id = $1 AND p.deleted_at IS NULL`
+	inventory := &domain.ProductInventory{ProductID: productID, Locations: make([]*domain.LocationStock, 0)}
+	err := r.db.QueryRow(ctx, query, productID).Scan(&inventory.Stock, &inventory.AvailableStock)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("Error when searching for product inventory: %w", domain.ErrProductNotFound)
+		}
+		return nil, fmt.Errorf("Error when searching for product inventory: %w", err)
+	}
+
+	query = `SELECT ws.warehouse_id, w.code, w.name, ws.quantity FROM warehouse_stock ws JOIN warehouses w ON w.id = ws.warehouse_id
+		WHERE ws.product_id = $1 ORDER BY w.priority, w.code`
+	rows, err := r.db.Query(ctx, query, productID)
+	if err != nil {
+		return nil, fmt.Errorf("Error when searching for product inventory: %w", err)
+	}
+	defer rows.Close()
+
+	allocated := 0
+	for rows.Next() {
+		location := &domain.LocationStock{}
+		if err := rows.Scan(&location.WarehouseID, &location.Code, &location.Name, &location.Quantity); err != nil {
+			return nil, fmt.Errorf("error scanning location stock row: %w", err)
+		}
+		allocated += location.Quantity
+		inventory.Locations = append(inventory.Locations, location)
+	}
+	inventory.Unassigned = inventory.Stock - allocated
+
+	return inventory, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/test_artefacts/stubs"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Warehouses", func() {
+	var productRepo ProductRepository
+	var warehouseRepo WarehouseRepository
+	var ctx context.Context
+
+	newWarehouse := func(code string, priority int) *domain.Warehouse {
+		now := time.Now().UTC()
+		warehouse := &domain.Warehouse{ID: uuid.New(), Code: code, Name: code, Priority: priority, Active: true, CreatedAt: now, UpdatedAt: now}
+		Expect(warehouseRepo.Create(ctx, warehouse)).To(Succeed())
+		return warehouse
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		productRepo = NewProduct(db, domain.AllocationPriority)
+		warehouseRepo = NewWarehouse(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products, warehouses RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject a duplicated warehouse code", func() {
+		newWarehouse("LIS", 1)
+
+		now := time.Now().UTC()
+		err := warehouseRepo.Create(ctx, &domain.Warehouse{ID: uuid.New(), Code: "LIS", Name: "Lisboa", Active: true, CreatedAt: now, UpdatedAt: now})
+		Expect(errors.Is(err, domain.ErrWarehouseCodeTaken)).To(BeTrue())
+	})
+
+	It("should allocate a reduction across locations by priority", func() {
+		// Arrange: 3 unidades em LIS (prioridade 1), 5 em OPO (prioridade 2) e 2 não atribuídas
+		product := stubs.NewProductStub().WithStock(2).Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		lis := newWarehouse("LIS", 1)
+		opo := newWarehouse("OPO", 2)
+		_, err := productRepo.IncreaseStock(ctx, product.ID, lis.ID, 3, "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = productRepo.IncreaseStock(ctx, product.ID, opo.ID, 5, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Act: Reduz 6 unidades sem indicar armazém
+		Expect(productRepo.ReduceStock(ctx, product.ID, uuid.Nil, uuid.Nil, 6)).To(Succeed())
+
+		// Assert: LIS esgota primeiro e o restante sai de OPO
+		inventory, err := warehouseRepo.GetProductInventory(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inventory.Stock).To(Equal(4))
+		Expect(inventory.Unassigned).To(Equal(2))
+		Expect(inventory.Locations).To(HaveLen(2))
+		Expect(inventory.Locations[0].Quantity).To(Equal(0))
+		Expect(inventory.Locations[1].Quantity).To(Equal(2))
+	})
+
+	It("should not let an update set the stock below the quantity allocated to warehouses", func() {
+		// Arrange: 2 unidades não atribuídas e 5 em LIS
+		product := stubs.NewProductStub().WithStock(2).Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		lis := newWarehouse("LIS", 1)
+		_, err := productRepo.IncreaseStock(ctx, product.ID, lis.ID, 5, "")
+		Expect(err).NotTo(HaveOccurred())
+		found, err := productRepo.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Act: a atualização tenta deixar 4 unidades
+		found.Stock = 4
+		err = productRepo.Update(ctx, found)
+
+		// Assert: é recusada, mas pode retirar as unidades não atribuídas
+		Expect(errors.Is(err, domain.ErrStockBelowAllocated)).To(BeTrue())
+		found.Stock = 5
+		Expect(productRepo.Update(ctx, found)).To(Succeed())
+		inventory, err := warehouseRepo.GetProductInventory(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inventory.Unassigned).To(Equal(0))
+		Expect(inventory.Locations[0].Quantity).To(Equal(5))
+	})
+
+	It("should reject a reduction larger than the stock of the chosen warehouse", func() {
+		product := stubs.NewProductStub().WithStock(10).Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		lis := newWarehouse("LIS", 1)
+		_, err := productRepo.IncreaseStock(ctx, product.ID, lis.ID, 2, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = productRepo.ReduceStock(ctx, product.ID, lis.ID, uuid.Nil, 3)
+
+		var stockErr *domain.InsufficientStockError
+		Expect(errors.As(err, &stockErr)).To(BeTrue())
+		Expect(stockErr.Available).To(Equal(2))
+	})
+
+	It("should report every line that the chosen warehouses cannot serve in a batch", func() {
+		// Arrange: 2 unidades de cada produto em LIS
+		first := stubs.NewProductStub().WithStock(10).Get()
+		second := stubs.NewProductStub().WithStock(10).Get()
+		third := stubs.NewProductStub().WithStock(10).Get()
+		lis := newWarehouse("LIS", 1)
+		for _, product := range []*domain.Product{first, second, third} {
+			Expect(productRepo.Create(ctx, product)).To(Succeed())
+			_, err := productRepo.IncreaseStock(ctx, product.ID, lis.ID, 2, "")
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		// Act: Só a linha do meio cabe no stock de LIS
+		err := productRepo.ReduceStockBatch(ctx, []domain.StockItem{
+			{ProductID: first.ID, WarehouseID: lis.ID, Quantity: 3},
+			{ProductID: second.ID, WarehouseID: lis.ID, Quantity: 1},
+			{ProductID: third.ID, WarehouseID: lis.ID, Quantity: 4},
+		})
+
+		// Assert: As duas linhas falhadas são relatadas pela ordem do lote e nada é aplicado
+		var batchErr *domain.BatchStockError
+		Expect(errors.As(err, &batchErr)).To(BeTrue())
+		Expect(batchErr.Lines).To(HaveLen(2))
+		Expect(batchErr.Lines[0].ProductID).To(Equal(first.ID))
+		Expect(batchErr.Lines[0].Code).To(Equal("INSUFFICIENT_STOCK"))
+		Expect(batchErr.Lines[0].Available).To(Equal(2))
+		Expect(batchErr.Lines[1].ProductID).To(Equal(third.ID))
+		inventory, err := warehouseRepo.GetProductInventory(ctx, second.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inventory.Locations[0].Quantity).To(Equal(2))
+	})
+
+	It("should transfer stock between warehouses without changing the total", func() {
+		// Arrange: 5 unidades em LIS
+		product := stubs.NewProductStub().WithStock(0).Get()
+		Expect(productRepo.Create(ctx, product)).To(Succeed())
+		lis := newWarehouse("LIS", 1)
+		opo := newWarehouse("OPO", 2)
+		_, err := productRepo.IncreaseStock(ctx, product.ID, lis.ID, 5, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Act: Transfere 2 unidades de LIS para OPO
+		transfer := &domain.StockTransfer{ID: uuid.New(), ProductID: product.ID, FromWarehouseID: lis.ID, ToWarehouseID: opo.ID, Quantity: 2, CreatedAt: time.Now().UTC()}
+		Expect(warehouseRepo.Transfer(ctx, transfer)).To(Succeed())
+
+		// Assert: O total mantém-se e a transferência fica no histórico
+		inventory, err := warehouseRepo.GetProductInventory(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inventory.Stock).To(Equal(5))
+		Expect(inventory.Locations[0].Quantity).To(Equal(3))
+		Expect(inventory.Locations[1].Quantity).To(Equal(2))
+
+		page, err := productRepo.ListStockMovements(ctx, product.ID, 10, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Items[0].Reference).To(Equal(transfer.ID.String()))
+		Expect(page.Items[1].Reference).To(Equal(transfer.ID.String()))
+	})
+})
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// lockWarehouse garante que o armazém existe e está ativo, bloqueando-o contra desativações concorrentes.
+func lockWarehouse(ctx context.Context, tx dbtx, warehouseID uuid.UUID) error {
+	var active bool
+	err := tx.QueryRow(ctx, `SELECT active FROM warehouses WHERE id = $1 FOR SHARE`, warehouseID).Scan(&active)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrWarehouseNotFound
+		}
+		return err
+	}
+	if !active {
+		return domain.ErrWarehouseInactive
+	}
+	return nil
+}
+
+// lockLocationStock bloqueia o stock do produto no armazém, devolvendo 0 quando ainda não existe.
+func lockLocationStock(ctx context.Context, tx dbtx, productID, warehouseID uuid.UUID) (int, error) {
+	var quantity int
+	query := `SELECT quantity FROM warehouse_stock WHERE warehouse_id = $1 AND product_id = $2 FOR UPDATE`
+	err := tx.QueryRow(ctx, query, warehouseID, productID).Scan(&quantity)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return quantity, nil
+}
+
+// applyLocationStockDelta altera o stock do produto num armazém e o total em
+// mão do produto, registando o movimento com o armazém.
+func applyLocationStockDelta(ctx context.Context, tx dbtx, productID, warehouseID uuid.UUID, delta int, reason domain.MovementReason, reference string) (int, error) {
+	if err := lockWarehouse(ctx, tx, warehouseID); err != nil {
+		return 0, err
+	}
+	current, err := lockLocationStock(ctx, tx, productID, warehouseID)
+	if err != nil {
+		return 0, err
+	}
+	if current+delta < 0 {
+		return 0, &domain.InsufficientStockError{ProductID: productID, Requested: -delta, Available: current}
+	}
+
+	query := `INSERT INTO warehouse_stock (warehouse_id, product_id, quantity, updated_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity, updated_at = NOW()`
+	if _, err := tx.Exec(ctx, query, warehouseID, productID, current+delta); err != nil {
+		return 0, err
+	}
+
+	return updateProductStock(ctx, tx, productID, &warehouseID, delta, reason, reference)
+}
+
+// lockUnassignedStock devolve o stock em mão do produto que não está atribuído a nenhum armazém.
+func lockUnassignedStock(ctx context.Context, tx dbtx, productID uuid.UUID) (int, error) {
+	var stock int
+	err := tx.QueryRow(ctx, `SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, productID).Scan(&stock)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrProductNotFound
+		}
+		return 0, err
+	}
+
+	var allocated int
+	err = tx.QueryRow(ctx, `SELECT COALESCE(SUM(quantity), 0) FROM warehouse_stock WHERE product_id = $1`, productID).Scan(&allocated)
+	if err != nil {
+		return 0, err
+	}
+	return stock - allocated, nil
+}
+
+// reduceProductStock retira quantity unidades do produto. Com um armazém, sai
+// apenas desse local; sem armazém, os locais ativos são consumidos pela ordem
+// da estratégia e o restante sai do stock não atribuído.
+func reduceProductStock(ctx context.Context, tx dbtx, productID, warehouseID uuid.UUID, quantity int, strategy domain.AllocationStrategy, reason domain.MovementReason, reference string) error {
+	if warehouseID != uuid.Nil {
+		_, err := applyLocationStockDelta(ctx, tx, productID, warehouseID, -quantity, reason, reference)
+		return err
+	}
+
+	order := `w.priority ASC, ws.quantity DESC`
+	if strategy == domain.AllocationMostStock {
+		order = `ws.quantity DESC, w.priority ASC`
+	}
+	query := `SELECT ws.warehouse_id, ws.quantity FROM warehouse_stock ws JOIN warehouses w ON w.id = ws.warehouse_id
+		WHERE ws.product_id = $1 AND ws.quantity > 0 AND w.active ORDER BY ` + order + `, w.code ASC FOR UPDATE OF ws`
+	rows, err := tx.Query(ctx, query, productID)
+	if err != nil {
+		return err
+	}
+	locations, err := pgx.CollectRows(rows, pgx.RowToStructByPos[struct {
+		WarehouseID uuid.UUID
+		Quantity    int
+	}])
+	if err != nil {
+		return err
+	}
+
+	remaining := quantity
+	for _, location := range locations {
+		if remaining == 0 {
+			break
+		}
+		take := min(remaining, location.Quantity)
+		if _, err := applyLocationStockDelta(ctx, tx, productID, location.WarehouseID, -take, reason, reference); err != nil {
+			return err
+		}
+		remaining -= take
+	}
+	if remaining == 0 {
+		return nil
+	}
+
+	unassigned, err := lockUnassignedStock(ctx, tx, productID)
+	if err != nil {
+		return err
+	}
+	if unassigned < remaining {
+		return &domain.InsufficientStockError{ProductID: productID, Requested: quantity, Available: quantity - remaining + unassigned}
+	}
+	_, err = applyStockDelta(ctx, tx, productID, -remaining, reason, reference)
+	return err
+}
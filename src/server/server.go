@@ -14,14 +14,36 @@ import (
 )
 
 type Server struct {
-	cfg     *config.Config
-	service service.ProductService
+	cfg                *config.Config
+	service            service.ProductService
+	reservationService service.ReservationService
+	idempotencyService service.IdempotencyService
+	warehouseService   service.WarehouseService
+	categoryService    service.CategoryService
+	variantService     service.VariantService
+	priceService       service.PriceService
+	priceListService   service.PriceListService
+	currencyService    service.CurrencyService
+	bundleService      service.BundleService
+	attributeService   service.AttributeService
+	mediaService       service.MediaService
 }
 
-func NewServer(cfg *config.Config, productService service.ProductService) *Server {
+func NewServer(cfg *config.Config, productService service.ProductService, reservationService service.ReservationService, idempotencyService service.IdempotencyService, warehouseService service.WarehouseService, categoryService service.CategoryService, variantService service.VariantService, priceService service.PriceService, priceListService service.PriceListService, currencyService service.CurrencyService, bundleService service.BundleService, attributeService service.AttributeService, mediaService service.MediaService) *Server {
 	return &Server{
-		cfg:     cfg,
-		service: productService,
+		cfg:                cfg,
+		service:            productService,
+		reservationService: reservationService,
+		idempotencyService: idempotencyService,
+		warehouseService:   warehouseService,
+		categoryService:    categoryService,
+		variantService:     variantService,
+		priceService:       priceService,
+		priceListService:   priceListService,
+		currencyService:    currencyService,
+		bundleService:      bundleService,
+		attributeService:   attributeService,
+		mediaService:       mediaService,
 	}
 }
 
@@ -35,8 +57,20 @@ func (s *Server) Run() {
 	router := chi.NewRouter()
 	router.Use(middleware.RequestLogger(&middleware.DefaultLogFormatter{Logger: logger, NoColor: true}))
 	router.Use(middleware.Recoverer)
+	router.Use(api.CorrelationIDMiddleware)
 
 	apiHandler := api.NewHandler(s.service, s.cfg)
+	reservationHandler := api.NewReservationHandler(s.reservationService)
+	idempotency := api.NewIdempotencyHandler(s.idempotencyService)
+	warehouseHandler := api.NewWarehouseHandler(s.warehouseService)
+	categoryHandler := api.NewCategoryHandler(s.categoryService)
+	variantHandler := api.NewVariantHandler(s.variantService)
+	priceHandler := api.NewPriceHandler(s.priceService)
+	priceListHandler := api.NewPriceListHandler(s.priceListService)
+	currencyHandler := api.NewCurrencyHandler(s.currencyService)
+	bundleHandler := api.NewBundleHandler(s.bundleService)
+	attributeHandler := api.NewAttributeHandler(s.attributeService)
+	mediaHandler := api.NewMediaHandler(s.mediaService, s.cfg)
 
 	// --- Configuração das Rotas ---
 	// Rotas Públicas
@@ -45,18 +79,113 @@ func (s *Server) Run() {
 	})
 	router.Get("/{id}", apiHandler.HandleGet)
 	router.Get("/list", apiHandler.HandleList)
+	router.Get("/search", apiHandler.HandleSearch)
+	router.Get("/products/sku/{sku}", apiHandler.HandleGetBySKU)
+	router.Get("/products/barcode/{barcode}", apiHandler.HandleGetByBarcode)
+	router.Get("/categories", categoryHandler.HandleGetTree)
+	router.Get("/categories/{id}", categoryHandler.HandleGet)
+	router.Get("/categories/{id}/products", apiHandler.HandleListByCategory)
+	router.Get("/categories/{id}/facets", apiHandler.HandleCategoryFacets)
+	router.Get("/{id}/categories", categoryHandler.HandleListProductCategories)
+	router.Get("/{id}/options", variantHandler.HandleListProductOptions)
+	router.Get("/{id}/variants", variantHandler.HandleList)
+	router.Get("/{id}/components", bundleHandler.HandleListComponents)
+	router.Get("/media/*", mediaHandler.HandleServe)
 
 	// Rotas Protegidas
 	router.Group(func(r chi.Router) {
 		r.Use(apiHandler.JWTAuthMiddleware)
-		r.Post("/create", apiHandler.HandleCreate)
+		r.With(idempotency.Middleware).Post("/create", apiHandler.HandleCreate)
+		r.Get("/products", apiHandler.HandleAdminList)
+		r.Get("/products/deleted", apiHandler.HandleListDeleted)
+		r.Post("/products/{id}/restore", apiHandler.HandleRestore)
+		r.Get("/products/{id}", apiHandler.HandleAdminGet)
+		r.Post("/products/{id}/status", apiHandler.HandleSetStatus)
+		r.Put("/products/{id}/sale", apiHandler.HandleSetSale)
+		r.Delete("/products/{id}/sale", apiHandler.HandleClearSale)
 		r.Put("/products/{id}", apiHandler.HandleUpdate)
 		r.Delete("/products/{id}", apiHandler.HandleDelete)
+		r.Get("/products/{id}/stock-movements", apiHandler.HandleListStockMovements)
+		r.Get("/products/{id}/revisions", apiHandler.HandleListRevisions)
+		r.Get("/products/{id}/revisions/diff", apiHandler.HandleDiffRevisions)
+		r.Get("/products/{id}/revisions/{revision}", apiHandler.HandleGetRevision)
+		r.Post("/products/{id}/revisions/{revision}/revert", apiHandler.HandleRevert)
+		r.With(idempotency.Middleware).Post("/products/{id}/restock", apiHandler.HandleRestock)
+		r.With(idempotency.Middleware).Post("/products/{id}/stock-adjustments", apiHandler.HandleAdjustStock)
+		r.Get("/products/{id}/inventory", warehouseHandler.HandleGetProductInventory)
+
+		// Armazéns
+		r.Post("/warehouses", warehouseHandler.HandleCreate)
+		r.Get("/warehouses", warehouseHandler.HandleList)
+		r.Get("/warehouses/{id}", warehouseHandler.HandleGet)
+		r.Put("/warehouses/{id}", warehouseHandler.HandleUpdate)
+		r.With(idempotency.Middleware).Post("/warehouses/transfers", warehouseHandler.HandleTransfer)
+
+		// Categorias
+		r.Post("/categories", categoryHandler.HandleCreate)
+		r.Put("/categories/{id}", categoryHandler.HandleUpdate)
+		r.Delete("/categories/{id}", categoryHandler.HandleDelete)
+		r.Get("/products/{id}/categories", categoryHandler.HandleAdminListProductCategories)
+		r.Put("/products/{id}/categories", categoryHandler.HandleSetProductCategories)
+		r.Get("/categories/{id}/attributes", attributeHandler.HandleList)
+		r.Put("/categories/{id}/attributes/{name}", attributeHandler.HandleSet)
+		r.Delete("/categories/{id}/attributes/{name}", attributeHandler.HandleDelete)
+
+		// Variantes
+		r.Get("/products/{id}/options", variantHandler.HandleAdminListProductOptions)
+		r.Put("/products/{id}/options", variantHandler.HandleSetProductOptions)
+		r.Get("/products/{id}/variants", variantHandler.HandleAdminList)
+		r.With(idempotency.Middleware).Post("/products/{id}/variants", variantHandler.HandleCreate)
+
+		// Kits
+		r.Get("/products/{id}/components", bundleHandler.HandleAdminListComponents)
+		r.Put("/products/{id}/components", bundleHandler.HandleSetComponents)
+
+		// Imagens
+		r.Post("/products/{id}/media", mediaHandler.HandleUpload)
+		r.Put("/products/{id}/media/order", mediaHandler.HandleReorder)
+		r.Put("/products/{id}/media/{mediaId}", mediaHandler.HandleUpdate)
+		r.Delete("/products/{id}/media/{mediaId}", mediaHandler.HandleDelete)
+
+		// Preços
+		r.Get("/products/{id}/price-history", priceHandler.HandleListHistory)
+		r.Get("/products/{id}/price-schedules", priceHandler.HandleListSchedules)
+		r.Post("/products/{id}/price-schedules", priceHandler.HandleCreateSchedule)
+		r.Delete("/products/{id}/price-schedules/{scheduleId}", priceHandler.HandleCancelSchedule)
+
+		// Tabelas de preços por grupo de clientes
+		r.Post("/price-lists", priceListHandler.HandleCreate)
+		r.Get("/price-lists", priceListHandler.HandleList)
+		r.Get("/price-lists/{id}", priceListHandler.HandleGet)
+		r.Delete("/price-lists/{id}", priceListHandler.HandleDelete)
+		r.Get("/price-lists/{id}/products/{productId}", priceListHandler.HandleListProductTiers)
+		r.Put("/price-lists/{id}/products/{productId}", priceListHandler.HandleSetProductTiers)
+
+		// Moedas
+		r.Get("/exchange-rates", currencyHandler.HandleListExchangeRates)
+		r.Put("/exchange-rates/{base}/{currency}", currencyHandler.HandleSetExchangeRate)
+		r.Delete("/exchange-rates/{base}/{currency}", currencyHandler.HandleDeleteExchangeRate)
+		r.Get("/products/{id}/prices", currencyHandler.HandleListProductPrices)
+		r.Put("/products/{id}/prices", currencyHandler.HandleSetProductPrice)
+		r.Delete("/products/{id}/prices/{currency}", currencyHandler.HandleDeleteProductPrice)
 	})
 
 	router.Group(func(r chi.Router) {
 		r.Use(apiHandler.APIKeyAuthMiddleware)
-		r.Put("/products/reduce-stock/{id}", apiHandler.HandleReduceStock)
+		r.Get("/products/reservations/{id}", reservationHandler.HandleGet)
+		r.Get("/products/{id}/quote", priceListHandler.HandleQuote)
+
+		// Rotas que alteram stock aceitam o cabeçalho Idempotency-Key
+		r.Group(func(r chi.Router) {
+			r.Use(idempotency.Middleware)
+			r.Put("/products/reduce-stock/{id}", apiHandler.HandleReduceStock)
+			r.Post("/products/reduce-stock/batch", apiHandler.HandleReduceStockBatch)
+
+			// Reservas de stock (consumidas pelo Serviço de Pedidos)
+			r.Post("/products/reservations", reservationHandler.HandleReserve)
+			r.Put("/products/reservations/{id}/commit", reservationHandler.HandleCommit)
+			r.Put("/products/reservations/{id}/release", reservationHandler.HandleRelease)
+		})
 	})
 
 	log.Printf("Servidor de Produtos iniciado em %s", s.cfg.ListenAddr)
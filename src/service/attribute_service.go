@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttributeService interface {
+	// SetDefinition cria ou substitui a definição; os produtos existentes só são validados na próxima atualização.
+	SetDefinition(ctx context.Context, definition *domain.AttributeDefinition) error
+	DeleteDefinition(ctx context.Context, categoryID uuid.UUID, name string) error
+	ListDefinitions(ctx context.Context, categoryID uuid.UUID) ([]domain.AttributeDefinition, error)
+}
+
+type attributeService struct {
+	attributeRepository repository.AttributeRepository
+}
+
+func NewAttributeService(attributeRepository repository.AttributeRepository) AttributeService {
+	return &attributeService{attributeRepository: attributeRepository}
+}
+
+func (s *attributeService) SetDefinition(ctx context.Context, definition *domain.AttributeDefinition) error {
+
+	if definition.CategoryID == uuid.Nil {
+		return fmt.Errorf("Error when setting attribute definition: %w", domain.ErrInvalidID)
+	}
+	if err := domain.NormalizeAttributeDefinition(definition); err != nil {
+		return fmt.Errorf("Error when setting attribute definition: %w", err)
+	}
+	definition.UpdatedAt = time.Now().UTC()
+
+	return s.attributeRepository.SetDefinition(ctx, definition)
+}
+
+func (s *attributeService) DeleteDefinition(ctx context.Context, categoryID uuid.UUID, name string) error {
+
+	if categoryID == uuid.Nil {
+		return fmt.Errorf("Error when deleting attribute definition: %w", domain.ErrInvalidID)
+	}
+
+	return s.attributeRepository.DeleteDefinition(ctx, categoryID, strings.ToLower(strings.TrimSpace(name)))
+}
+
+func (s *attributeService) ListDefinitions(ctx context.Context, categoryID uuid.UUID) ([]domain.AttributeDefinition, error) {
+
+	if categoryID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing attribute definitions: %w", domain.ErrInvalidID)
+	}
+
+	return s.attributeRepository.ListDefinitions(ctx, categoryID)
+}
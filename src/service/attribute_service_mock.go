@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type AttributeServiceMock struct {
+	mock.Mock
+}
+
+func (m *AttributeServiceMock) SetDefinition(ctx context.Context, definition *domain.AttributeDefinition) error {
+	args := m.Called(ctx, definition)
+	return args.Error(0)
+}
+
+func (m *AttributeServiceMock) DeleteDefinition(ctx context.Context, categoryID uuid.UUID, name string) error {
+	args := m.Called(ctx, categoryID, name)
+	return args.Error(0)
+}
+
+func (m *AttributeServiceMock) ListDefinitions(ctx context.Context, categoryID uuid.UUID) ([]domain.AttributeDefinition, error) {
+	args := m.Called(ctx, categoryID)
+	if definitions, ok := args.Get(0).([]domain.AttributeDefinition); ok {
+		return definitions, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
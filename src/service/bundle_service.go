@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+
+	"github.com/google/uuid"
+)
+
+type BundleService interface {
+	// SetComponents transforma o produto num kit com os componentes indicados; uma lista vazia volta a torná-lo simples.
+	SetComponents(ctx context.Context, bundleID uuid.UUID, components []domain.BundleComponent) ([]domain.BundleComponent, error)
+	// ListComponents só lê kits no estado status; vazio lê em qualquer estado.
+	ListComponents(ctx context.Context, bundleID uuid.UUID, status domain.ProductStatus) ([]domain.BundleComponent, error)
+}
+
+type bundleService struct {
+	bundleRepository repository.BundleRepository
+}
+
+func NewBundleService(bundleRepository repository.BundleRepository) BundleService {
+	return &bundleService{bundleRepository: bundleRepository}
+}
+
+func (s *bundleService) SetComponents(ctx context.Context, bundleID uuid.UUID, components []domain.BundleComponent) ([]domain.BundleComponent, error) {
+
+	if bundleID == uuid.Nil {
+		return nil, fmt.Errorf("Error when setting bundle components: %w", domain.ErrInvalidID)
+	}
+	if err := domain.ValidateBundleComponents(bundleID, components); err != nil {
+		return nil, fmt.Errorf("Error when setting bundle components: %w", err)
+	}
+
+	if err := s.bundleRepository.SetComponents(ctx, bundleID, components); err != nil {
+		return nil, err
+	}
+	return s.bundleRepository.ListComponents(ctx, bundleID, "")
+}
+
+func (s *bundleService) ListComponents(ctx context.Context, bundleID uuid.UUID, status domain.ProductStatus) ([]domain.BundleComponent, error) {
+
+	if bundleID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing bundle components: %w", domain.ErrInvalidID)
+	}
+
+	return s.bundleRepository.ListComponents(ctx, bundleID, status)
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type BundleServiceMock struct {
+	mock.Mock
+}
+
+func (m *BundleServiceMock) SetComponents(ctx context.Context, bundleID uuid.UUID, components []domain.BundleComponent) ([]domain.BundleComponent, error) {
+	args := m.Called(ctx, bundleID, components)
+	if saved, ok := args.Get(0).([]domain.BundleComponent); ok {
+		return saved, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *BundleServiceMock) ListComponents(ctx context.Context, bundleID uuid.UUID, status domain.ProductStatus) ([]domain.BundleComponent, error) {
+	args := m.Called(ctx, bundleID, status)
+	if components, ok := args.Get(0).([]domain.BundleComponent); ok {
+		return components, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
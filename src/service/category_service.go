@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CategoryService interface {
+	Create(ctx context.Context, category *domain.Category) error
+	GetCategoryByID(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	GetCategoryTree(ctx context.Context) ([]*domain.CategoryNode, error)
+	Update(ctx context.Context, category *domain.Category) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	SetProductCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error
+	// ListProductCategories só lê produtos no estado status; vazio lê em qualquer estado.
+	ListProductCategories(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Category, error)
+}
+
+type categoryService struct {
+	categoryRepository repository.CategoryRepository
+}
+
+func NewCategoryService(categoryRepository repository.CategoryRepository) CategoryService {
+	return &categoryService{categoryRepository: categoryRepository}
+}
+
+// normalizeCategory valida o nome e o pai e gera o slug a partir do nome quando não é indicado.
+func normalizeCategory(category *domain.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return domain.ErrParametersMissing
+	}
+	if category.ParentID != nil && *category.ParentID == uuid.Nil {
+		category.ParentID = nil
+	}
+
+	category.Slug = strings.TrimSpace(category.Slug)
+	if category.Slug == "" {
+		category.Slug = domain.Slugify(category.Name)
+	}
+	if !domain.ValidSlug(category.Slug) {
+		return domain.ErrInvalidSlug
+	}
+	return nil
+}
+
+func (s *categoryService) Create(ctx context.Context, category *domain.Category) error {
+
+	if err := normalizeCategory(category); err != nil {
+		return fmt.Errorf("Error creating category: %w", err)
+	}
+
+	category.ID = uuid.New()
+	category.CreatedAt = time.Now().UTC()
+	category.UpdatedAt = category.CreatedAt
+
+	return s.categoryRepository.Create(ctx, category)
+}
+
+func (s *categoryService) GetCategoryByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when searching for category by ID: %w", domain.ErrInvalidID)
+	}
+
+	return s.categoryRepository.GetCategoryByID(ctx, id)
+}
+
+func (s *categoryService) GetCategoryTree(ctx context.Context) ([]*domain.CategoryNode, error) {
+
+	categories, err := s.categoryRepository.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.BuildCategoryTree(categories), nil
+}
+
+func (s *categoryService) Update(ctx context.Context, category *domain.Category) error {
+
+	if category.ID == uuid.Nil {
+		return fmt.Errorf("Error updating category: %w", domain.ErrInvalidID)
+	}
+	if err := normalizeCategory(category); err != nil {
+		return fmt.Errorf("Error updating category: %w", err)
+	}
+	if category.ParentID != nil && *category.ParentID == category.ID {
+		return fmt.Errorf("Error updating category: %w", domain.ErrCategoryCycle)
+	}
+
+	category.UpdatedAt = time.Now().UTC()
+
+	return s.categoryRepository.Update(ctx, category)
+}
+
+func (s *categoryService) Delete(ctx context.Context, id uuid.UUID) error {
+
+	if id == uuid.Nil {
+		return fmt.Errorf("Error when deleting category: %w", domain.ErrInvalidID)
+	}
+
+	return s.categoryRepository.Delete(ctx, id)
+}
+
+func (s *categoryService) SetProductCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error {
+
+	if productID == uuid.Nil {
+		return fmt.Errorf("Error when assigning product categories: %w", domain.ErrInvalidID)
+	}
+	for _, categoryID := range categoryIDs {
+		if categoryID == uuid.Nil {
+			return fmt.Errorf("Error when assigning product categories: %w", domain.ErrInvalidID)
+		}
+	}
+
+	return s.categoryRepository.SetProductCategories(ctx, productID, categoryIDs)
+}
+
+func (s *categoryService) ListProductCategories(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Category, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing product categories: %w", domain.ErrInvalidID)
+	}
+
+	return s.categoryRepository.ListProductCategories(ctx, productID, status)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type CategoryServiceMock struct {
+	mock.Mock
+}
+
+func (m *CategoryServiceMock) Create(ctx context.Context, category *domain.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *CategoryServiceMock) GetCategoryByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	args := m.Called(ctx, id)
+	if category, ok := args.Get(0).(*domain.Category); ok {
+		return category, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *CategoryServiceMock) GetCategoryTree(ctx context.Context) ([]*domain.CategoryNode, error) {
+	args := m.Called(ctx)
+	if tree, ok := args.Get(0).([]*domain.CategoryNode); ok {
+		return tree, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *CategoryServiceMock) Update(ctx context.Context, category *domain.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *CategoryServiceMock) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *CategoryServiceMock) SetProductCategories(ctx context.Context, productID uuid.UUID, categoryIDs []uuid.UUID) error {
+	args := m.Called(ctx, productID, categoryIDs)
+	return args.Error(0)
+}
+
+func (m *CategoryServiceMock) ListProductCategories(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Category, error) {
+	args := m.Called(ctx, productID, status)
+	if categories, ok := args.Get(0).([]*domain.Category); ok {
+		return categories, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
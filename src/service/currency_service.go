@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type CurrencyService interface {
+	ListExchangeRates(ctx context.Context) ([]*domain.ExchangeRate, error)
+	SetExchangeRate(ctx context.Context, baseCurrency, currency, rate string) (*domain.ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, baseCurrency, currency string) error
+	ListProductPrices(ctx context.Context, productID uuid.UUID) ([]domain.Money, error)
+	SetProductPrice(ctx context.Context, productID uuid.UUID, price domain.Money) error
+	DeleteProductPrice(ctx context.Context, productID uuid.UUID, currency string) error
+}
+
+type currencyService struct {
+	currencyRepository repository.CurrencyRepository
+}
+
+func NewCurrencyService(currencyRepository repository.CurrencyRepository) CurrencyService {
+	return &currencyService{currencyRepository: currencyRepository}
+}
+
+// parseCurrencyPair valida as duas moedas de uma taxa de câmbio, que têm de ser diferentes.
+func parseCurrencyPair(baseCurrency, currency string) (string, string, error) {
+	baseCurrency, err := domain.ParseCurrency(baseCurrency)
+	if err != nil {
+		return "", "", err
+	}
+	if currency, err = domain.ParseCurrency(currency); err != nil {
+		return "", "", err
+	}
+	if baseCurrency == currency {
+		return "", "", fmt.Errorf("%w: base and quoted currencies must differ", domain.ErrInvalidExchangeRate)
+	}
+	return baseCurrency, currency, nil
+}
+
+func (s *currencyService) ListExchangeRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	return s.currencyRepository.ListExchangeRates(ctx)
+}
+
+func (s *currencyService) SetExchangeRate(ctx context.Context, baseCurrency, currency, rate string) (*domain.ExchangeRate, error) {
+
+	baseCurrency, currency, err := parseCurrencyPair(baseCurrency, currency)
+	if err != nil {
+		return nil, fmt.Errorf("Error setting exchange rate: %w", err)
+	}
+	rate = strings.TrimSpace(rate)
+	if _, err := domain.ParseExchangeRate(rate); err != nil {
+		return nil, fmt.Errorf("Error setting exchange rate: %w", err)
+	}
+
+	exchangeRate := &domain.ExchangeRate{BaseCurrency: baseCurrency, Currency: currency, Rate: rate}
+	if err := s.currencyRepository.SetExchangeRate(ctx, exchangeRate); err != nil {
+		return nil, err
+	}
+	return exchangeRate, nil
+}
+
+func (s *currencyService) DeleteExchangeRate(ctx context.Context, baseCurrency, currency string) error {
+
+	baseCurrency, currency, err := parseCurrencyPair(baseCurrency, currency)
+	if err != nil {
+		return fmt.Errorf("Error deleting exchange rate: %w", err)
+	}
+
+	return s.currencyRepository.DeleteExchangeRate(ctx, baseCurrency, currency)
+}
+
+func (s *currencyService) ListProductPrices(ctx context.Context, productID uuid.UUID) ([]domain.Money, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing product prices: %w", domain.ErrInvalidID)
+	}
+
+	return s.currencyRepository.ListProductPrices(ctx, productID)
+}
+
+func (s *currencyService) SetProductPrice(ctx context.Context, productID uuid.UUID, price domain.Money) error {
+
+	if productID == uuid.Nil {
+		return fmt.Errorf("Error setting product price: %w", domain.ErrInvalidID)
+	}
+	if err := validatePrice(price); err != nil {
+		return fmt.Errorf("Error setting product price: %w", err)
+	}
+
+	return s.currencyRepository.SetProductPrice(ctx, productID, price)
+}
+
+func (s *currencyService) DeleteProductPrice(ctx context.Context, productID uuid.UUID, currency string) error {
+
+	if productID == uuid.Nil {
+		return fmt.Errorf("Error deleting product price: %w", domain.ErrInvalidID)
+	}
+	currency, err := domain.ParseCurrency(currency)
+	if err != nil {
+		return fmt.Errorf("Error deleting product price: %w", err)
+	}
+
+	return s.currencyRepository.DeleteProductPrice(ctx, productID, currency)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type CurrencyServiceMock struct {
+	mock.Mock
+}
+
+func (m *CurrencyServiceMock) ListExchangeRates(ctx context.Context) ([]*domain.ExchangeRate, error) {
+	args := m.Called(ctx)
+	if rates, ok := args.Get(0).([]*domain.ExchangeRate); ok {
+		return rates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *CurrencyServiceMock) SetExchangeRate(ctx context.Context, baseCurrency, currency, rate string) (*domain.ExchangeRate, error) {
+	args := m.Called(ctx, baseCurrency, currency, rate)
+	if exchangeRate, ok := args.Get(0).(*domain.ExchangeRate); ok {
+		return exchangeRate, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *CurrencyServiceMock) DeleteExchangeRate(ctx context.Context, baseCurrency, currency string) error {
+	args := m.Called(ctx, baseCurrency, currency)
+	return args.Error(0)
+}
+
+func (m *CurrencyServiceMock) ListProductPrices(ctx context.Context, productID uuid.UUID) ([]domain.Money, error) {
+	args := m.Called(ctx, productID)
+	if prices, ok := args.Get(0).([]domain.Money); ok {
+		return prices, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *CurrencyServiceMock) SetProductPrice(ctx context.Context, productID uuid.UUID, price domain.Money) error {
+	args := m.Called(ctx, productID, price)
+	return args.Error(0)
+}
+
+func (m *CurrencyServiceMock) DeleteProductPrice(ctx context.Context, productID uuid.UUID, currency string) error {
+	args := m.Called(ctx, productID, currency)
+	return args.Error(0)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"time"
+)
+
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyService interface {
+	// Begin devolve nil quando o pedido deve ser processado, ou o registo
+	// concluído cuja resposta deve ser repetida. As chaves de principals
+	// diferentes são independentes.
+	Begin(ctx context.Context, principal, key, requestHash string) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, principal, key string, statusCode int, responseBody []byte) error
+	Abandon(ctx context.Context, principal, key string) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	idempotencyRepository repository.IdempotencyRepository
+	retention             time.Duration
+}
+
+func NewIdempotencyService(idempotencyRepository repository.IdempotencyRepository, retention time.Duration) IdempotencyService {
+	return &idempotencyService{idempotencyRepository: idempotencyRepository, retention: retention}
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, principal, key, requestHash string) (*domain.IdempotencyRecord, error) {
+
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, fmt.Errorf("Error when checking idempotency key: %w", domain.ErrInvalidIdempotencyKey)
+	}
+
+	record, created, err := s.idempotencyRepository.Claim(ctx, principal, key, requestHash)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		return nil, nil
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, fmt.Errorf("Error when checking idempotency key: %w", domain.ErrIdempotencyKeyReused)
+	}
+	if !record.Completed() {
+		return nil, fmt.Errorf("Error when checking idempotency key: %w", domain.ErrIdempotencyInProgress)
+	}
+	return record, nil
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, principal, key string, statusCode int, responseBody []byte) error {
+	return s.idempotencyRepository.Complete(ctx, principal, key, statusCode, responseBody)
+}
+
+// Abandon liberta a chave para que o pedido possa ser repetido, usado quando o
+// primeiro pedido falhou por um erro do servidor.
+func (s *idempotencyService) Abandon(ctx context.Context, principal, key string) error {
+	return s.idempotencyRepository.Delete(ctx, principal, key)
+}
+
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.idempotencyRepository.PurgeOlderThan(ctx, time.Now().UTC().Add(-s.retention))
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type IdempotencyServiceMock struct {
+	mock.Mock
+}
+
+func (m *IdempotencyServiceMock) Begin(ctx context.Context, principal, key, requestHash string) (*domain.IdempotencyRecord, error) {
+	args := m.Called(ctx, principal, key, requestHash)
+	if record, ok := args.Get(0).(*domain.IdempotencyRecord); ok {
+		return record, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *IdempotencyServiceMock) Complete(ctx context.Context, principal, key string, statusCode int, responseBody []byte) error {
+	args := m.Called(ctx, principal, key, statusCode, responseBody)
+	return args.Error(0)
+}
+
+func (m *IdempotencyServiceMock) Abandon(ctx context.Context, principal, key string) error {
+	args := m.Called(ctx, principal, key)
+	return args.Error(0)
+}
+
+func (m *IdempotencyServiceMock) PurgeExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"product-service/src/storage"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MediaService interface {
+	// Upload valida e guarda a imagem e acrescenta-a no fim da galeria do produto.
+	Upload(ctx context.Context, productID uuid.UUID, contentType string, content io.Reader, altText string) (*domain.ProductMedia, error)
+	UpdateAltText(ctx context.Context, productID, mediaID uuid.UUID, altText string) (*domain.ProductMedia, error)
+	// Reorder define a ordem da galeria e devolve-a.
+	Reorder(ctx context.Context, productID uuid.UUID, mediaIDs []uuid.UUID) ([]domain.ProductMedia, error)
+	Delete(ctx context.Context, productID, mediaID uuid.UUID) error
+	// Open devolve o conteúdo do ficheiro guardado na chave.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+type mediaService struct {
+	mediaRepository repository.MediaRepository
+	blobs           storage.BlobStorage
+	maxSize         int64
+}
+
+func NewMediaService(mediaRepository repository.MediaRepository, blobs storage.BlobStorage, maxSize int64) MediaService {
+	return &mediaService{mediaRepository: mediaRepository, blobs: blobs, maxSize: maxSize}
+}
+
+// withMediaURLs preenche o endereço público de cada imagem.
+func withMediaURLs(blobs storage.BlobStorage, media []domain.ProductMedia) []domain.ProductMedia {
+	for i := range media {
+		media[i].URL = blobs.URL(media[i].StorageKey)
+	}
+	return media
+}
+
+func (s *mediaService) Upload(ctx context.Context, productID uuid.UUID, contentType string, content io.Reader, altText string) (*domain.ProductMedia, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when uploading media: %w", domain.ErrInvalidID)
+	}
+	altText, err := domain.NormalizeAltText(altText)
+	if err != nil {
+		return nil, fmt.Errorf("Error when uploading media: %w", err)
+	}
+
+	// Lê um byte além do limite para distinguir um ficheiro no limite de um maior.
+	data, err := io.ReadAll(io.LimitReader(content, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("Error when uploading media: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("Error when uploading media: %w: the limit is %d bytes", domain.ErrMediaTooLarge, s.maxSize)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("Error when uploading media: %w: the file is empty", domain.ErrInvalidMedia)
+	}
+
+	// O tipo vem do conteúdo; o tipo declarado pelo cliente, quando existe, tem de coincidir.
+	detected := http.DetectContentType(data)
+	extension, err := domain.MediaExtension(detected)
+	if err != nil {
+		return nil, fmt.Errorf("Error when uploading media: %w", err)
+	}
+	if declared, _, err := mime.ParseMediaType(contentType); contentType != "" && (err != nil || declared != detected) {
+		return nil, fmt.Errorf("Error when uploading media: %w: declared %q but the content is %s", domain.ErrUnsupportedMediaType, contentType, detected)
+	}
+
+	media := &domain.ProductMedia{
+		ID:          uuid.New(),
+		ProductID:   productID,
+		ContentType: detected,
+		Size:        int64(len(data)),
+		AltText:     altText,
+		CreatedAt:   time.Now().UTC(),
+	}
+	media.StorageKey = "products/" + productID.String() + "/" + media.ID.String() + extension
+
+	if err := s.blobs.Put(ctx, media.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("Error when uploading media: %w", domain.ErrToSaveMedia)
+	}
+	if err := s.mediaRepository.Create(ctx, media); err != nil {
+		if deleteErr := s.blobs.Delete(ctx, media.StorageKey); deleteErr != nil {
+			log.Printf("ERROR: removing media file %s: %v", media.StorageKey, deleteErr)
+		}
+		return nil, err
+	}
+	media.URL = s.blobs.URL(media.StorageKey)
+	return media, nil
+}
+
+func (s *mediaService) UpdateAltText(ctx context.Context, productID, mediaID uuid.UUID, altText string) (*domain.ProductMedia, error) {
+
+	altText, err := domain.NormalizeAltText(altText)
+	if err != nil {
+		return nil, fmt.Errorf("Error when updating media: %w", err)
+	}
+
+	media, err := s.mediaRepository.UpdateAltText(ctx, productID, mediaID, altText)
+	if err != nil {
+		return nil, err
+	}
+	media.URL = s.blobs.URL(media.StorageKey)
+	return media, nil
+}
+
+func (s *mediaService) Reorder(ctx context.Context, productID uuid.UUID, mediaIDs []uuid.UUID) ([]domain.ProductMedia, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when reordering media: %w", domain.ErrInvalidID)
+	}
+
+	if err := s.mediaRepository.Reorder(ctx, productID, mediaIDs); err != nil {
+		return nil, err
+	}
+	media, err := s.mediaRepository.ListByProducts(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return nil, err
+	}
+	return withMediaURLs(s.blobs, media), nil
+}
+
+func (s *mediaService) Delete(ctx context.Context, productID, mediaID uuid.UUID) error {
+
+	key, err := s.mediaRepository.Delete(ctx, productID, mediaID)
+	if err != nil {
+		return err
+	}
+	// A imagem já saiu da galeria; um ficheiro que fique para trás só ocupa espaço.
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		log.Printf("ERROR: removing media file %s: %v", key, err)
+	}
+	return nil
+}
+
+func (s *mediaService) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.blobs.Open(ctx, key)
+}
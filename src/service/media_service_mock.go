@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"io"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MediaServiceMock struct {
+	mock.Mock
+}
+
+func (m *MediaServiceMock) Upload(ctx context.Context, productID uuid.UUID, contentType string, content io.Reader, altText string) (*domain.ProductMedia, error) {
+	args := m.Called(ctx, productID, contentType, content, altText)
+	if media, ok := args.Get(0).(*domain.ProductMedia); ok {
+		return media, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MediaServiceMock) UpdateAltText(ctx context.Context, productID, mediaID uuid.UUID, altText string) (*domain.ProductMedia, error) {
+	args := m.Called(ctx, productID, mediaID, altText)
+	if media, ok := args.Get(0).(*domain.ProductMedia); ok {
+		return media, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MediaServiceMock) Reorder(ctx context.Context, productID uuid.UUID, mediaIDs []uuid.UUID) ([]domain.ProductMedia, error) {
+	args := m.Called(ctx, productID, mediaIDs)
+	if media, ok := args.Get(0).([]domain.ProductMedia); ok {
+		return media, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MediaServiceMock) Delete(ctx context.Context, productID, mediaID uuid.UUID) error {
+	args := m.Called(ctx, productID, mediaID)
+	return args.Error(0)
+}
+
+func (m *MediaServiceMock) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(ctx, key)
+	if content, ok := args.Get(0).(io.ReadCloser); ok {
+		return content, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
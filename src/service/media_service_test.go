@@ -0,0 +1,109 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"product-service/src/storage"
+	"product-service/test_artefacts/seeder"
+	"product-service/test_artefacts/stubs"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// pngHeader basta para que o conteúdo seja reconhecido como PNG.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+var _ = Describe("MediaService", func() {
+	var mediaService MediaService
+	var productService ProductService
+	var productRepo repository.ProductRepository
+	var blobs storage.BlobStorage
+	var product *domain.Product
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		var err error
+		blobs, err = storage.NewLocal(GinkgoT().TempDir(), "/media")
+		Expect(err).NotTo(HaveOccurred())
+		mediaRepo := repository.NewMedia(db)
+		productRepo = repository.NewProduct(db, domain.AllocationPriority)
+		mediaService = NewMediaService(mediaRepo, blobs, 1024)
+		// Sem retenção, os produtos removidos são purgados na primeira execução.
+		productService = NewProductService(productRepo, mediaRepo, blobs, 0)
+
+		_, err = db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+		product = stubs.NewProductStub().Get()
+		Expect(seeder.NewTestSeeder(db).InsertProduct(ctx, product)).To(Succeed())
+	})
+
+	It("should include the gallery in order with public URLs when reading the product", func() {
+		first, err := mediaService.Upload(ctx, product.ID, "image/png", bytes.NewReader(pngHeader), " Frente ")
+		Expect(err).NotTo(HaveOccurred())
+		second, err := mediaService.Upload(ctx, product.ID, "", bytes.NewReader(pngHeader), "Verso")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = mediaService.Reorder(ctx, product.ID, []uuid.UUID{second.ID, first.ID})
+		Expect(err).NotTo(HaveOccurred())
+
+		found, err := productService.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Media).To(HaveLen(2))
+		Expect(found.Media[0].ID).To(Equal(second.ID))
+		Expect(found.Media[1].AltText).To(Equal("Frente"))
+		Expect(found.Media[1].URL).To(Equal("/media/products/" + product.ID.String() + "/" + first.ID.String() + ".png"))
+		Expect(found.Media[1].ContentType).To(Equal("image/png"))
+
+		// A leitura pelo SKU traz a mesma galeria
+		bySKU, err := productService.GetProductBySKU(ctx, product.SKU)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bySKU.Media).To(Equal(found.Media))
+	})
+
+	It("should reject content that does not match the declared type or is too large", func() {
+		_, err := mediaService.Upload(ctx, product.ID, "image/jpeg", bytes.NewReader(pngHeader), "")
+		Expect(errors.Is(err, domain.ErrUnsupportedMediaType)).To(BeTrue())
+
+		_, err = mediaService.Upload(ctx, product.ID, "text/plain", bytes.NewReader([]byte("não é uma imagem")), "")
+		Expect(errors.Is(err, domain.ErrUnsupportedMediaType)).To(BeTrue())
+
+		_, err = mediaService.Upload(ctx, product.ID, "image/png", bytes.NewReader(append(pngHeader, make([]byte, 1024)...)), "")
+		Expect(errors.Is(err, domain.ErrMediaTooLarge)).To(BeTrue())
+	})
+
+	It("should close the gap in the gallery and remove the file on delete", func() {
+		first, err := mediaService.Upload(ctx, product.ID, "image/png", bytes.NewReader(pngHeader), "")
+		Expect(err).NotTo(HaveOccurred())
+		second, err := mediaService.Upload(ctx, product.ID, "image/png", bytes.NewReader(pngHeader), "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mediaService.Delete(ctx, product.ID, first.ID)).To(Succeed())
+
+		found, err := productService.GetProductByID(ctx, product.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Media).To(HaveLen(1))
+		Expect(found.Media[0].ID).To(Equal(second.ID))
+		Expect(found.Media[0].Position).To(Equal(0))
+		_, err = blobs.Open(ctx, first.StorageKey)
+		Expect(errors.Is(err, domain.ErrMediaNotFound)).To(BeTrue())
+	})
+
+	It("should remove the files of purged products", func() {
+		media, err := mediaService.Upload(ctx, product.ID, "image/png", bytes.NewReader(pngHeader), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(productRepo.Delete(ctx, product.ID, 1)).To(Succeed())
+
+		purged, err := productService.PurgeDeleted(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(Equal(int64(1)))
+		_, err = blobs.Open(ctx, media.StorageKey)
+		Expect(errors.Is(err, domain.ErrMediaNotFound)).To(BeTrue())
+	})
+})
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PriceListService interface {
+	CreatePriceList(ctx context.Context, priceList *domain.PriceList) error
+	GetPriceList(ctx context.Context, id uuid.UUID) (*domain.PriceList, error)
+	ListPriceLists(ctx context.Context) ([]*domain.PriceList, error)
+	DeletePriceList(ctx context.Context, id uuid.UUID) error
+	SetProductTiers(ctx context.Context, priceListID, productID uuid.UUID, tiers []domain.PriceTier) ([]domain.PriceTier, error)
+	ListProductTiers(ctx context.Context, priceListID, productID uuid.UUID) ([]domain.PriceTier, error)
+	// Quote devolve o preço unitário e o total de uma quantidade do produto para o grupo de clientes.
+	Quote(ctx context.Context, productID uuid.UUID, customerGroup string, quantity int) (*domain.PriceQuote, error)
+}
+
+type priceListService struct {
+	priceListRepository repository.PriceListRepository
+	productRepository   repository.ProductRepository
+}
+
+func NewPriceListService(priceListRepository repository.PriceListRepository, productRepository repository.ProductRepository) PriceListService {
+	return &priceListService{priceListRepository: priceListRepository, productRepository: productRepository}
+}
+
+// normalizeCustomerGroup aceita um grupo de clientes no formato de slug (ex: "grossista").
+func normalizeCustomerGroup(group string) (string, error) {
+	group = strings.ToLower(strings.TrimSpace(group))
+	if !domain.ValidSlug(group) {
+		return "", fmt.Errorf("%w: %q", domain.ErrInvalidCustomerGroup, group)
+	}
+	return group, nil
+}
+
+// normalizeTiers ordena os escalões por quantidade mínima e exige quantidades
+// positivas e distintas, com preços positivos numa única moeda.
+func normalizeTiers(tiers []domain.PriceTier) ([]domain.PriceTier, error) {
+	sorted := append([]domain.PriceTier(nil), tiers...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinQuantity < sorted[j].MinQuantity })
+
+	for i, tier := range sorted {
+		if tier.MinQuantity < 1 {
+			return nil, fmt.Errorf("%w: min_quantity must be at least 1", domain.ErrInvalidPriceTier)
+		}
+		if i > 0 && tier.MinQuantity == sorted[i-1].MinQuantity {
+			return nil, fmt.Errorf("%w: min_quantity %d is repeated", domain.ErrInvalidPriceTier, tier.MinQuantity)
+		}
+		if err := validatePrice(tier.UnitPrice); err != nil {
+			return nil, err
+		}
+		if tier.UnitPrice.Currency != sorted[0].UnitPrice.Currency {
+			return nil, fmt.Errorf("%w: all tiers must use the same currency", domain.ErrCurrencyMismatch)
+		}
+	}
+	return sorted, nil
+}
+
+func (s *priceListService) CreatePriceList(ctx context.Context, priceList *domain.PriceList) error {
+
+	priceList.Name = strings.TrimSpace(priceList.Name)
+	if priceList.Name == "" {
+		return fmt.Errorf("Error creating price list: %w", domain.ErrParametersMissing)
+	}
+	group, err := normalizeCustomerGroup(priceList.CustomerGroup)
+	if err != nil {
+		return fmt.Errorf("Error creating price list: %w", err)
+	}
+
+	priceList.ID = uuid.New()
+	priceList.CustomerGroup = group
+	priceList.CreatedAt = time.Now().UTC()
+	priceList.UpdatedAt = priceList.CreatedAt
+
+	return s.priceListRepository.CreatePriceList(ctx, priceList)
+}
+
+func (s *priceListService) GetPriceList(ctx context.Context, id uuid.UUID) (*domain.PriceList, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when searching for price list by ID: %w", domain.ErrInvalidID)
+	}
+
+	return s.priceListRepository.GetPriceList(ctx, id)
+}
+
+func (s *priceListService) ListPriceLists(ctx context.Context) ([]*domain.PriceList, error) {
+	return s.priceListRepository.ListPriceLists(ctx)
+}
+
+func (s *priceListService) DeletePriceList(ctx context.Context, id uuid.UUID) error {
+
+	if id == uuid.Nil {
+		return fmt.Errorf("Error deleting price list: %w", domain.ErrInvalidID)
+	}
+
+	return s.priceListRepository.DeletePriceList(ctx, id)
+}
+
+func (s *priceListService) SetProductTiers(ctx context.Context, priceListID, productID uuid.UUID, tiers []domain.PriceTier) ([]domain.PriceTier, error) {
+
+	if priceListID == uuid.Nil || productID == uuid.Nil {
+		return nil, fmt.Errorf("Error setting price tiers: %w", domain.ErrInvalidID)
+	}
+	tiers, err := normalizeTiers(tiers)
+	if err != nil {
+		return nil, fmt.Errorf("Error setting price tiers: %w", err)
+	}
+
+	if err := s.priceListRepository.SetProductTiers(ctx, priceListID, productID, tiers); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+func (s *priceListService) ListProductTiers(ctx context.Context, priceListID, productID uuid.UUID) ([]domain.PriceTier, error) {
+
+	if priceListID == uuid.Nil || productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing price tiers: %w", domain.ErrInvalidID)
+	}
+
+	return s.priceListRepository.ListProductTiers(ctx, priceListID, productID)
+}
+
+// Quote parte do preço efetivo do produto (com a promoção em curso, se houver) e
+// aplica o escalão da tabela do grupo de clientes quando este é mais baixo.
+func (s *priceListService) Quote(ctx context.Context, productID uuid.UUID, customerGroup string, quantity int) (*domain.PriceQuote, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error quoting price: %w", domain.ErrInvalidID)
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("Error quoting price: %w", domain.ErrInvalidQuantity)
+	}
+	if customerGroup != "" {
+		group, err := normalizeCustomerGroup(customerGroup)
+		if err != nil {
+			return nil, fmt.Errorf("Error quoting price: %w", err)
+		}
+		customerGroup = group
+	}
+
+	product, err := s.productRepository.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	// Tal como nas leituras públicas, só se vendem produtos ativos.
+	if product.Status != domain.StatusActive {
+		return nil, fmt.Errorf("Error quoting price: %w", domain.ErrProductNotFound)
+	}
+
+	quote := &domain.PriceQuote{ProductID: productID, CustomerGroup: customerGroup, Quantity: quantity, UnitPrice: product.EffectivePrice, Source: domain.QuoteSourceRegular}
+	if product.OnSale {
+		quote.Source = domain.QuoteSourceSale
+	}
+
+	if customerGroup != "" {
+		priceList, tier, err := s.priceListRepository.FindTier(ctx, customerGroup, productID, quantity, product.EffectivePrice.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if tier != nil && tier.UnitPrice.Minor < quote.UnitPrice.Minor {
+			quote.UnitPrice = tier.UnitPrice
+			quote.Source = domain.QuoteSourcePriceList
+			quote.PriceListID = &priceList.ID
+			quote.MinQuantity = tier.MinQuantity
+		}
+	}
+
+	if quote.LinePrice, err = quote.UnitPrice.Multiply(quantity); err != nil {
+		return nil, fmt.Errorf("Error quoting price: %w", err)
+	}
+	return quote, nil
+}
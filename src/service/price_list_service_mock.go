@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type PriceListServiceMock struct {
+	mock.Mock
+}
+
+func (m *PriceListServiceMock) CreatePriceList(ctx context.Context, priceList *domain.PriceList) error {
+	args := m.Called(ctx, priceList)
+	return args.Error(0)
+}
+
+func (m *PriceListServiceMock) GetPriceList(ctx context.Context, id uuid.UUID) (*domain.PriceList, error) {
+	args := m.Called(ctx, id)
+	if priceList, ok := args.Get(0).(*domain.PriceList); ok {
+		return priceList, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *PriceListServiceMock) ListPriceLists(ctx context.Context) ([]*domain.PriceList, error) {
+	args := m.Called(ctx)
+	if priceLists, ok := args.Get(0).([]*domain.PriceList); ok {
+		return priceLists, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *PriceListServiceMock) DeletePriceList(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *PriceListServiceMock) SetProductTiers(ctx context.Context, priceListID, productID uuid.UUID, tiers []domain.PriceTier) ([]domain.PriceTier, error) {
+	args := m.Called(ctx, priceListID, productID, tiers)
+	if tiers, ok := args.Get(0).([]domain.PriceTier); ok {
+		return tiers, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *PriceListServiceMock) ListProductTiers(ctx context.Context, priceListID, productID uuid.UUID) ([]domain.PriceTier, error) {
+	args := m.Called(ctx, priceListID, productID)
+	if tiers, ok := args.Get(0).([]domain.PriceTier); ok {
+		return tiers, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *PriceListServiceMock) Quote(ctx context.Context, productID uuid.UUID, customerGroup string, quantity int) (*domain.PriceQuote, error) {
+	args := m.Called(ctx, productID, customerGroup, quantity)
+	if quote, ok := args.Get(0).(*domain.PriceQuote); ok {
+		return quote, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
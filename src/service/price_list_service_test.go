@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"product-service/test_artefacts/seeder"
+	"product-service/test_artefacts/stubs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PriceListService", func() {
+	var priceListService PriceListService
+	var testSeeder *seeder.TestSeeder
+	var ctx context.Context
+	var product *domain.Product
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		priceListService = NewPriceListService(repository.NewPriceList(db), repository.NewProduct(db, domain.AllocationPriority))
+		testSeeder = seeder.NewTestSeeder(db)
+
+		_, err := db.Exec(ctx, "TRUNCATE TABLE products, price_lists RESTART IDENTITY CASCADE")
+		Expect(err).NotTo(HaveOccurred())
+
+		product = stubs.NewProductStub().WithPrice("10.00").Get()
+		Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+
+		priceList := &domain.PriceList{Name: "Grossistas", CustomerGroup: "Grossista"}
+		Expect(priceListService.CreatePriceList(ctx, priceList)).To(Succeed())
+		_, err = priceListService.SetProductTiers(ctx, priceList.ID, product.ID, []domain.PriceTier{
+			{MinQuantity: 100, UnitPrice: domain.Money{Minor: 800, Currency: "BRL"}},
+			{MinQuantity: 10, UnitPrice: domain.Money{Minor: 900, Currency: "BRL"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should quote the tier price of the customer group for the quantity", func() {
+		// Act
+		quote, err := priceListService.Quote(ctx, product.ID, "grossista", 120)
+
+		// Assert: o escalão de 100 unidades aplica-se ao preço unitário e ao total
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quote.Source).To(Equal(domain.QuoteSourcePriceList))
+		Expect(quote.MinQuantity).To(Equal(100))
+		Expect(quote.UnitPrice.Amount()).To(Equal("8.00"))
+		Expect(quote.LinePrice.Amount()).To(Equal("960.00"))
+	})
+
+	It("should quote the product price without a customer group or below the first tier", func() {
+		quote, err := priceListService.Quote(ctx, product.ID, "", 120)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quote.Source).To(Equal(domain.QuoteSourceRegular))
+		Expect(quote.LinePrice.Amount()).To(Equal("1200.00"))
+
+		quote, err = priceListService.Quote(ctx, product.ID, "grossista", 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quote.Source).To(Equal(domain.QuoteSourceRegular))
+		Expect(quote.UnitPrice.Amount()).To(Equal("10.00"))
+	})
+})
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PriceService interface {
+	ListPriceHistory(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.PriceChangePage, error)
+	CreateSchedule(ctx context.Context, productID uuid.UUID, price domain.Money, effectiveFrom time.Time, effectiveTo *time.Time) (*domain.PriceSchedule, error)
+	ListSchedules(ctx context.Context, productID uuid.UUID) ([]*domain.PriceSchedule, error)
+	CancelSchedule(ctx context.Context, productID, scheduleID uuid.UUID) (*domain.PriceSchedule, error)
+	// ApplyDueSchedules é executado periodicamente para aplicar e repor os preços agendados.
+	ApplyDueSchedules(ctx context.Context) (int, error)
+}
+
+type priceService struct {
+	priceRepository repository.PriceRepository
+}
+
+func NewPriceService(priceRepository repository.PriceRepository) PriceService {
+	return &priceService{priceRepository: priceRepository}
+}
+
+func (s *priceService) ListPriceHistory(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.PriceChangePage, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing price history: %w", domain.ErrInvalidID)
+	}
+
+	return s.priceRepository.ListPriceHistory(ctx, productID, pageSize(limit), cursor)
+}
+
+func (s *priceService) CreateSchedule(ctx context.Context, productID uuid.UUID, price domain.Money, effectiveFrom time.Time, effectiveTo *time.Time) (*domain.PriceSchedule, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error creating price schedule: %w", domain.ErrInvalidID)
+	}
+	if err := validatePrice(price); err != nil {
+		return nil, fmt.Errorf("Error creating price schedule: %w", err)
+	}
+	if effectiveFrom.IsZero() {
+		return nil, fmt.Errorf("Error creating price schedule: %w: effective_from is required", domain.ErrInvalidPriceSchedule)
+	}
+	if effectiveTo != nil && !effectiveTo.After(effectiveFrom) {
+		return nil, fmt.Errorf("Error creating price schedule: %w: effective_to must be after effective_from", domain.ErrInvalidPriceSchedule)
+	}
+	now := time.Now().UTC()
+	if effectiveTo != nil && !effectiveTo.After(now) {
+		return nil, fmt.Errorf("Error creating price schedule: %w: effective_to is in the past", domain.ErrInvalidPriceSchedule)
+	}
+
+	schedule := &domain.PriceSchedule{
+		ID:            uuid.New(),
+		ProductID:     productID,
+		Price:         price,
+		EffectiveFrom: effectiveFrom.UTC(),
+		EffectiveTo:   effectiveTo,
+		Status:        domain.SchedulePending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.priceRepository.CreateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *priceService) ListSchedules(ctx context.Context, productID uuid.UUID) ([]*domain.PriceSchedule, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing price schedules: %w", domain.ErrInvalidID)
+	}
+
+	return s.priceRepository.ListSchedules(ctx, productID)
+}
+
+func (s *priceService) CancelSchedule(ctx context.Context, productID, scheduleID uuid.UUID) (*domain.PriceSchedule, error) {
+
+	if productID == uuid.Nil || scheduleID == uuid.Nil {
+		return nil, fmt.Errorf("Error cancelling price schedule: %w", domain.ErrInvalidID)
+	}
+
+	return s.priceRepository.CancelSchedule(ctx, productID, scheduleID)
+}
+
+func (s *priceService) ApplyDueSchedules(ctx context.Context) (int, error) {
+	return s.priceRepository.ApplyDueSchedules(ctx, time.Now().UTC())
+}
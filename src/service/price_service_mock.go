@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type PriceServiceMock struct {
+	mock.Mock
+}
+
+func (m *PriceServiceMock) ListPriceHistory(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.PriceChangePage, error) {
+	args := m.Called(ctx, productID, limit, cursor)
+	if page, ok := args.Get(0).(*domain.PriceChangePage); ok {
+		return page, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *PriceServiceMock) CreateSchedule(ctx context.Context, productID uuid.UUID, price domain.Money, effectiveFrom time.Time, effectiveTo *time.Time) (*domain.PriceSchedule, error) {
+	args := m.Called(ctx, productID, price, effectiveFrom, effectiveTo)
+	if schedule, ok := args.Get(0).(*domain.PriceSchedule); ok {
+		return schedule, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *PriceServiceMock) ListSchedules(ctx context.Context, productID uuid.UUID) ([]*domain.PriceSchedule, error) {
+	args := m.Called(ctx, productID)
+	if schedules, ok := args.Get(0).([]*domain.PriceSchedule); ok {
+		return schedules, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *PriceServiceMock) CancelSchedule(ctx context.Context, productID, scheduleID uuid.UUID) (*domain.PriceSchedule, error) {
+	args := m.Called(ctx, productID, scheduleID)
+	if schedule, ok := args.Get(0).(*domain.PriceSchedule); ok {
+		return schedule, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *PriceServiceMock) ApplyDueSchedules(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
@@ -3,37 +3,152 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 	"product-service/src/domain"
 	"product-service/src/repository"
+	"product-service/src/storage"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type ProductService interface {
-	Create(ctx context.Context, name, description string, price float64, stock int) error
+	// Create valida os atributos contra as definições das categorias indicadas, que são atribuídas ao produto.
+	Create(ctx context.Context, name, description, sku, barcode string, price domain.Money, stock int, attributes domain.Attributes, categoryIDs []uuid.UUID) error
+	// GetProductByID e ListProducts devolvem os produtos com a galeria de imagens.
 	GetProductByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
-	ListProducts(ctx context.Context) ([]*domain.Product, error)
-	ReduceStock(ctx context.Context, id uuid.UUID, quantity int) error
+	GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error)
+	GetProductByBarcode(ctx context.Context, barcode string) (*domain.Product, error)
+	ListProducts(ctx context.Context, query domain.ProductQuery) (*domain.ProductPage, error)
+	SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (*domain.ProductSearchPage, error)
+	ReduceStock(ctx context.Context, id, warehouseID, variantID uuid.UUID, quantity int) error
+	ReduceStockBatch(ctx context.Context, items []domain.StockItem) error
+	Restock(ctx context.Context, id, warehouseID uuid.UUID, quantity int, reference string) (int, error)
+	AdjustStock(ctx context.Context, id, warehouseID uuid.UUID, stock int, reason string) (int, error)
+	ListStockMovements(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.StockMovementPage, error)
+	SetStatus(ctx context.Context, id uuid.UUID, status domain.ProductStatus) (*domain.Product, error)
 	Update(ctx context.Context, product *domain.Product) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+	Restore(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+	// PurgeDeleted apaga definitivamente os produtos removidos há mais tempo do que a retenção,
+	// com as suas imagens.
+	PurgeDeleted(ctx context.Context) (int64, error)
+	ListRevisions(ctx context.Context, id uuid.UUID) ([]*domain.ProductRevision, error)
+	GetRevision(ctx context.Context, id uuid.UUID, revision int) (*domain.ProductRevision, error)
+	// DiffRevisions compara o produto nas revisões from e to, campo a campo.
+	DiffRevisions(ctx context.Context, id uuid.UUID, from, to int) (*domain.RevisionDiff, error)
+	RevertToRevision(ctx context.Context, id uuid.UUID, revision int, version int64) (*domain.Product, error)
+	// SetSale define a promoção do produto ou, com sale a nil, remove-a.
+	SetSale(ctx context.Context, id uuid.UUID, sale *domain.Sale) (*domain.Product, error)
+	// ConvertPrices mostra os preços dos produtos na moeda indicada.
+	ConvertPrices(ctx context.Context, currency string, products ...*domain.Product) error
+}
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	maxSearchLength = 200
+	// defaultPriceInterval é a largura, em unidades da moeda, das faixas de preço das facetas.
+	defaultPriceInterval = 50
+	// maxReferenceLength é o tamanho das colunas das referências de stock e das reservas.
+	maxReferenceLength = 255
+)
+
+// pageSize aplica o tamanho por omissão e o limite máximo de uma página.
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
 }
 
 type productService struct {
 	productRepository repository.ProductRepository
+	mediaRepository   repository.MediaRepository
+	blobs             storage.BlobStorage
+	deletedRetention  time.Duration
+}
+
+func NewProductService(productRepository repository.ProductRepository, mediaRepository repository.MediaRepository, blobs storage.BlobStorage,
+	deletedRetention time.Duration) ProductService {
+	return &productService{productRepository: productRepository, mediaRepository: mediaRepository, blobs: blobs, deletedRetention: deletedRetention}
+}
+
+// attachMedia carrega de uma só vez as galerias dos produtos.
+func (s *productService) attachMedia(ctx context.Context, products ...*domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	media, err := s.mediaRepository.ListByProducts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byProduct := make(map[uuid.UUID][]domain.ProductMedia, len(products))
+	for _, item := range withMediaURLs(s.blobs, media) {
+		byProduct[item.ProductID] = append(byProduct[item.ProductID], item)
+	}
+	for _, product := range products {
+		product.Media = byProduct[product.ID]
+	}
+	return nil
+}
+
+// validatePrice exige um valor positivo numa moeda suportada.
+func validatePrice(price domain.Money) error {
+	if _, err := domain.ParseCurrency(price.Currency); err != nil {
+		return err
+	}
+	if !price.IsPositive() {
+		return domain.ErrInvalidPrice
+	}
+	return nil
+}
+
+// validateSale exige um preço promocional positivo e uma janela de validade em
+// que o fim é posterior ao início. A comparação com o preço normal é feita pelo
+// repositório, com o preço bloqueado.
+func validateSale(sale *domain.Sale) error {
+	if !sale.Price.IsPositive() {
+		return fmt.Errorf("%w: sale price must be positive", domain.ErrInvalidSalePrice)
+	}
+	if sale.StartsAt != nil && sale.EndsAt != nil && !sale.EndsAt.After(*sale.StartsAt) {
+		return fmt.Errorf("%w: sale must end after it starts", domain.ErrInvalidSalePrice)
+	}
+	return nil
 }
 
-func NewProductService(productRepository repository.ProductRepository) ProductService {
-	return &productService{productRepository: productRepository}
+// normalizeIdentifiers exige um SKU válido e, quando indicado, um código de barras com dígito de controlo correto.
+func normalizeIdentifiers(product *domain.Product) error {
+	product.SKU = strings.TrimSpace(product.SKU)
+	if !domain.ValidSKU(product.SKU) {
+		return domain.ErrInvalidSKU
+	}
+	if product.Barcode = strings.TrimSpace(product.Barcode); product.Barcode == "" {
+		return nil
+	}
+	barcode, err := domain.NormalizeBarcode(product.Barcode)
+	if err != nil {
+		return err
+	}
+	product.Barcode = barcode
+	return nil
 }
 
-func (s *productService) Create(ctx context.Context, name, description string, price float64, stock int) error {
+func (s *productService) Create(ctx context.Context, name, description, sku, barcode string, price domain.Money, stock int, attributes domain.Attributes, categoryIDs []uuid.UUID) error {
 
 	if name == "" || description == "" {
 		return fmt.Errorf("Error creating product: %w", domain.ErrParametersMissing)
 	}
-	if price <= 0 {
-		return fmt.Errorf("Error creating product: %w", domain.ErrInvalidPrice)
+	if err := validatePrice(price); err != nil {
+		return fmt.Errorf("Error creating product: %w", err)
 	}
 	if stock < 0 {
 		return fmt.Errorf("Error creating product: %w", domain.ErrInvalidStock)
@@ -41,15 +156,23 @@ func (s *productService) Create(ctx context.Context, name, description string, p
 
 	product := &domain.Product{
 		ID:          uuid.New(),
+		SKU:         sku,
+		Barcode:     barcode,
 		Name:        name,
 		Description: description,
+		Status:      domain.StatusDraft,
+		Type:        domain.ProductSimple,
 		Price:       price,
 		Stock:       stock,
+		Attributes:  attributes,
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
 	}
+	if err := normalizeIdentifiers(product); err != nil {
+		return fmt.Errorf("Error creating product: %w", err)
+	}
 
-	return s.productRepository.Create(ctx, product)
+	return s.productRepository.Create(ctx, product, categoryIDs...)
 }
 
 func (s *productService) GetProductByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
@@ -58,14 +181,118 @@ func (s *productService) GetProductByID(ctx context.Context, id uuid.UUID) (*dom
 		return nil, fmt.Errorf("Error when searching for product by ID: %w", domain.ErrInvalidID)
 	}
 
-	return s.productRepository.GetProductByID(ctx, id)
+	product, err := s.productRepository.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachMedia(ctx, product); err != nil {
+		return nil, fmt.Errorf("Error when searching for product by ID: %w", err)
+	}
+	return product, nil
 }
 
-func (s *productService) ListProducts(ctx context.Context) ([]*domain.Product, error) {
-	return s.productRepository.ListProducts(ctx)
+func (s *productService) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+
+	sku = strings.TrimSpace(sku)
+	if !domain.ValidSKU(sku) {
+		return nil, fmt.Errorf("Error when searching for product by SKU: %w", domain.ErrInvalidSKU)
+	}
+
+	product, err := s.productRepository.GetProductBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachMedia(ctx, product); err != nil {
+		return nil, fmt.Errorf("Error when searching for product by SKU: %w", err)
+	}
+	return product, nil
 }
 
-func (s *productService) ReduceStock(ctx context.Context, id uuid.UUID, quantity int) error {
+func (s *productService) GetProductByBarcode(ctx context.Context, barcode string) (*domain.Product, error) {
+
+	barcode, err := domain.NormalizeBarcode(barcode)
+	if err != nil {
+		return nil, fmt.Errorf("Error when searching for product by barcode: %w", err)
+	}
+
+	product, err := s.productRepository.GetProductByBarcode(ctx, barcode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachMedia(ctx, product); err != nil {
+		return nil, fmt.Errorf("Error when searching for product by barcode: %w", err)
+	}
+	return product, nil
+}
+
+func (s *productService) ListProducts(ctx context.Context, query domain.ProductQuery) (*domain.ProductPage, error) {
+
+	sortBy, err := domain.ParseProductSortField(string(query.SortBy))
+	if err != nil {
+		return nil, fmt.Errorf("Error when listing products: %w", err)
+	}
+	if (query.MinPrice != nil && query.MinPrice.Minor < 0) || (query.MaxPrice != nil && query.MaxPrice.Minor < 0) {
+		return nil, fmt.Errorf("Error when listing products: %w: price range cannot be negative", domain.ErrInvalidFilter)
+	}
+	if query.MinPrice != nil && query.MaxPrice != nil {
+		order, err := query.MinPrice.Compare(*query.MaxPrice)
+		if err != nil {
+			return nil, fmt.Errorf("Error when listing products: %w", err)
+		}
+		if order > 0 {
+			return nil, fmt.Errorf("Error when listing products: %w: min_price is greater than max_price", domain.ErrInvalidFilter)
+		}
+	}
+
+	if query.Currency != "" {
+		if query.Currency, err = domain.ParseCurrency(query.Currency); err != nil {
+			return nil, fmt.Errorf("Error when listing products: %w", err)
+		}
+	}
+	if query.Attributes, err = domain.NormalizeAttributeFilters(query.Attributes); err != nil {
+		return nil, fmt.Errorf("Error when listing products: %w", err)
+	}
+	if query.PriceInterval < 0 {
+		return nil, fmt.Errorf("Error when listing products: %w: price_interval must be positive", domain.ErrInvalidFilter)
+	}
+	if query.PriceInterval == 0 {
+		query.PriceInterval = defaultPriceInterval
+	}
+
+	query.SortBy = sortBy
+	query.Limit = pageSize(query.Limit)
+	query.NameContains = strings.TrimSpace(query.NameContains)
+
+	page, err := s.productRepository.ListProducts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachMedia(ctx, page.Items...); err != nil {
+		return nil, fmt.Errorf("Error when listing products: %w", err)
+	}
+	return page, nil
+}
+
+func (s *productService) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (*domain.ProductSearchPage, error) {
+
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" || len(query.Text) > maxSearchLength {
+		return nil, fmt.Errorf("Error when searching products: %w: text must have between 1 and %d characters", domain.ErrInvalidSearchQuery, maxSearchLength)
+	}
+	language, err := domain.ParseSearchLanguage(string(query.Language))
+	if err != nil {
+		return nil, fmt.Errorf("Error when searching products: %w", err)
+	}
+
+	query.Language = language
+	query.Limit = pageSize(query.Limit)
+
+	return s.productRepository.SearchProducts(ctx, query)
+}
+
+// ReduceStock retira stock do armazém indicado ou, com uuid.Nil, segundo a
+// estratégia de alocação. Com variantID, o stock sai apenas da variante.
+func (s *productService) ReduceStock(ctx context.Context, id, warehouseID, variantID uuid.UUID, quantity int) error {
 
 	if id == uuid.Nil {
 		return fmt.Errorf("Error when reducing stock: %w", domain.ErrInvalidID)
@@ -74,8 +301,96 @@ func (s *productService) ReduceStock(ctx context.Context, id uuid.UUID, quantity
 	if quantity <= 0 {
 		return fmt.Errorf("Error when reducing stock: %w", domain.ErrInvalidQuantity)
 	}
+	if variantID != uuid.Nil && warehouseID != uuid.Nil {
+		return fmt.Errorf("Error when reducing stock: %w", domain.ErrVariantWarehouseStock)
+	}
 
-	return s.productRepository.ReduceStock(ctx, id, quantity)
+	return s.productRepository.ReduceStock(ctx, id, warehouseID, variantID, quantity)
+}
+
+func (s *productService) ReduceStockBatch(ctx context.Context, items []domain.StockItem) error {
+
+	if len(items) == 0 {
+		return fmt.Errorf("Error when reducing stock in batch: %w", domain.ErrEmptyStockBatch)
+	}
+
+	// Agrupa linhas repetidas do mesmo produto e armazém para que o stock seja validado pelo total pedido.
+	type lineKey struct{ productID, warehouseID uuid.UUID }
+	merged := make([]domain.StockItem, 0, len(items))
+	positions := make(map[lineKey]int, len(items))
+	for _, item := range items {
+		if item.ProductID == uuid.Nil {
+			return fmt.Errorf("Error when reducing stock in batch: %w", domain.ErrInvalidID)
+		}
+		if item.Quantity <= 0 {
+			return fmt.Errorf("Error when reducing stock in batch: %w", domain.ErrInvalidQuantity)
+		}
+		key := lineKey{item.ProductID, item.WarehouseID}
+		if i, ok := positions[key]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		positions[key] = len(merged)
+		merged = append(merged, item)
+	}
+
+	return s.productRepository.ReduceStockBatch(ctx, merged)
+}
+
+func (s *productService) Restock(ctx context.Context, id, warehouseID uuid.UUID, quantity int, reference string) (int, error) {
+
+	if id == uuid.Nil {
+		return 0, fmt.Errorf("Error when restocking product: %w", domain.ErrInvalidID)
+	}
+	if quantity <= 0 {
+		return 0, fmt.Errorf("Error when restocking product: %w", domain.ErrInvalidQuantity)
+	}
+
+	if len(reference) > maxReferenceLength {
+		return 0, fmt.Errorf("Error when restocking product: %w", domain.ErrReferenceTooLong)
+	}
+
+	return s.productRepository.IncreaseStock(ctx, id, warehouseID, quantity, reference)
+}
+
+func (s *productService) AdjustStock(ctx context.Context, id, warehouseID uuid.UUID, stock int, reason string) (int, error) {
+
+	if id == uuid.Nil {
+		return 0, fmt.Errorf("Error when adjusting stock: %w", domain.ErrInvalidID)
+	}
+	if stock < 0 {
+		return 0, fmt.Errorf("Error when adjusting stock: %w", domain.ErrInvalidStock)
+	}
+	if strings.TrimSpace(reason) == "" {
+		return 0, fmt.Errorf("Error when adjusting stock: %w", domain.ErrAdjustmentReason)
+	}
+	if len(reason) > maxReferenceLength {
+		return 0, fmt.Errorf("Error when adjusting stock: %w", domain.ErrReferenceTooLong)
+	}
+
+	return s.productRepository.SetStock(ctx, id, warehouseID, stock, reason)
+}
+
+func (s *productService) ListStockMovements(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.StockMovementPage, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing stock movements: %w", domain.ErrInvalidID)
+	}
+
+	return s.productRepository.ListStockMovements(ctx, productID, pageSize(limit), cursor)
+}
+
+func (s *productService) SetStatus(ctx context.Context, id uuid.UUID, status domain.ProductStatus) (*domain.Product, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when changing product status: %w", domain.ErrInvalidID)
+	}
+	status, err := domain.ParseProductStatus(string(status))
+	if err != nil {
+		return nil, fmt.Errorf("Error when changing product status: %w", err)
+	}
+
+	return s.productRepository.SetStatus(ctx, id, status)
 }
 
 func (s *productService) Update(ctx context.Context, product *domain.Product) error {
@@ -83,23 +398,135 @@ func (s *productService) Update(ctx context.Context, product *domain.Product) er
 	if product.Name == "" || product.Description == "" {
 		return fmt.Errorf("Error updating product: %w", domain.ErrParametersMissing)
 	}
-	if product.Price <= 0 {
-		return fmt.Errorf("Error updating product: %w", domain.ErrInvalidPrice)
+	if err := validatePrice(product.Price); err != nil {
+		return fmt.Errorf("Error updating product: %w", err)
 	}
 	if product.Stock < 0 {
 		return fmt.Errorf("Error updating product: %w", domain.ErrInvalidStock)
 	}
+	if product.Version <= 0 {
+		return fmt.Errorf("Error updating product: %w", domain.ErrInvalidVersion)
+	}
+	if err := normalizeIdentifiers(product); err != nil {
+		return fmt.Errorf("Error updating product: %w", err)
+	}
 
 	product.UpdatedAt = time.Now().UTC()
 
 	return s.productRepository.Update(ctx, product)
 }
 
-func (s *productService) Delete(ctx context.Context, id uuid.UUID) error {
+func (s *productService) Delete(ctx context.Context, id uuid.UUID, version int64) error {
 
 	if id == uuid.Nil {
 		return fmt.Errorf("Error when deleting product: %w", domain.ErrInvalidID)
 	}
+	if version <= 0 {
+		return fmt.Errorf("Error when deleting product: %w", domain.ErrInvalidVersion)
+	}
+
+	return s.productRepository.Delete(ctx, id, version)
+}
+
+func (s *productService) Restore(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when restoring product: %w", domain.ErrInvalidID)
+	}
+
+	return s.productRepository.Restore(ctx, id)
+}
+
+func (s *productService) PurgeDeleted(ctx context.Context) (int64, error) {
+	before := time.Now().UTC().Add(-s.deletedRetention)
+
+	purged, keys, err := s.productRepository.PurgeDeletedBefore(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	// Os ficheiros só são removidos depois do commit: se a purga falhar, as imagens continuam inteiras.
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			log.Printf("ERROR: removing media file %s: %v", key, err)
+		}
+	}
+	return purged, nil
+}
+
+func (s *productService) ListRevisions(ctx context.Context, id uuid.UUID) ([]*domain.ProductRevision, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing product revisions: %w", domain.ErrInvalidID)
+	}
+
+	return s.productRepository.ListRevisions(ctx, id)
+}
+
+func (s *productService) GetRevision(ctx context.Context, id uuid.UUID, revision int) (*domain.ProductRevision, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when searching for product revision: %w", domain.ErrInvalidID)
+	}
+	if revision <= 0 {
+		return nil, fmt.Errorf("Error when searching for product revision: %w", domain.ErrInvalidRevision)
+	}
+
+	return s.productRepository.GetRevision(ctx, id, revision)
+}
+
+func (s *productService) DiffRevisions(ctx context.Context, id uuid.UUID, from, to int) (*domain.RevisionDiff, error) {
+
+	before, err := s.GetRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.GetRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := domain.DiffProducts(&before.Snapshot, &after.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("Error when comparing product revisions: %w", err)
+	}
+	return &domain.RevisionDiff{ProductID: id, From: from, To: to, Changes: changes}, nil
+}
+
+func (s *productService) RevertToRevision(ctx context.Context, id uuid.UUID, revision int, version int64) (*domain.Product, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when reverting product: %w", domain.ErrInvalidID)
+	}
+	if revision <= 0 {
+		return nil, fmt.Errorf("Error when reverting product: %w", domain.ErrInvalidRevision)
+	}
+	if version <= 0 {
+		return nil, fmt.Errorf("Error when reverting product: %w", domain.ErrInvalidVersion)
+	}
+
+	return s.productRepository.RevertToRevision(ctx, id, revision, version)
+}
+
+func (s *productService) SetSale(ctx context.Context, id uuid.UUID, sale *domain.Sale) (*domain.Product, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when setting sale price: %w", domain.ErrInvalidID)
+	}
+	if sale != nil {
+		if err := validateSale(sale); err != nil {
+			return nil, fmt.Errorf("Error when setting sale price: %w", err)
+		}
+	}
+
+	return s.productRepository.SetSale(ctx, id, sale)
+}
+
+func (s *productService) ConvertPrices(ctx context.Context, currency string, products ...*domain.Product) error {
+
+	currency, err := domain.ParseCurrency(currency)
+	if err != nil {
+		return fmt.Errorf("Error converting prices: %w", err)
+	}
 
-	return s.productRepository.Delete(ctx, id)
+	return s.productRepository.ConvertPrices(ctx, currency, products...)
 }
@@ -12,8 +12,8 @@ type ProductServiceMock struct {
 	mock.Mock
 }
 
-func (m *ProductServiceMock) Create(ctx context.Context, name, description string, price float64, stock int) error {
-	args := m.Called(ctx, name, description, price, stock)
+func (m *ProductServiceMock) Create(ctx context.Context, name, description, sku, barcode string, price domain.Money, stock int, attributes domain.Attributes, categoryIDs []uuid.UUID) error {
+	args := m.Called(ctx, name, description, sku, barcode, price, stock, attributes, categoryIDs)
 	return args.Error(0)
 }
 
@@ -25,25 +25,138 @@ func (m *ProductServiceMock) GetProductByID(ctx context.Context, id uuid.UUID) (
 	return nil, args.Error(1)
 }
 
-func (m *ProductServiceMock) ListProducts(ctx context.Context) ([]*domain.Product, error) {
-	args := m.Called(ctx)
-	if products, ok := args.Get(0).([]*domain.Product); ok {
-		return products, args.Error(1)
+func (m *ProductServiceMock) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	args := m.Called(ctx, sku)
+	if product, ok := args.Get(0).(*domain.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) GetProductByBarcode(ctx context.Context, barcode string) (*domain.Product, error) {
+	args := m.Called(ctx, barcode)
+	if product, ok := args.Get(0).(*domain.Product); ok {
+		return product, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *ProductServiceMock) ReduceStock(ctx context.Context, id uuid.UUID, quantity int) error {
-	args := m.Called(ctx, id, quantity)
+func (m *ProductServiceMock) ListProducts(ctx context.Context, query domain.ProductQuery) (*domain.ProductPage, error) {
+	args := m.Called(ctx, query)
+	if page, ok := args.Get(0).(*domain.ProductPage); ok {
+		return page, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) SearchProducts(ctx context.Context, query domain.ProductSearchQuery) (*domain.ProductSearchPage, error) {
+	args := m.Called(ctx, query)
+	if page, ok := args.Get(0).(*domain.ProductSearchPage); ok {
+		return page, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) ReduceStock(ctx context.Context, id, warehouseID, variantID uuid.UUID, quantity int) error {
+	args := m.Called(ctx, id, warehouseID, variantID, quantity)
+	return args.Error(0)
+}
+
+func (m *ProductServiceMock) ReduceStockBatch(ctx context.Context, items []domain.StockItem) error {
+	args := m.Called(ctx, items)
 	return args.Error(0)
 }
 
+func (m *ProductServiceMock) Restock(ctx context.Context, id, warehouseID uuid.UUID, quantity int, reference string) (int, error) {
+	args := m.Called(ctx, id, warehouseID, quantity, reference)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ProductServiceMock) AdjustStock(ctx context.Context, id, warehouseID uuid.UUID, stock int, reason string) (int, error) {
+	args := m.Called(ctx, id, warehouseID, stock, reason)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ProductServiceMock) ListStockMovements(ctx context.Context, productID uuid.UUID, limit int, cursor string) (*domain.StockMovementPage, error) {
+	args := m.Called(ctx, productID, limit, cursor)
+	if page, ok := args.Get(0).(*domain.StockMovementPage); ok {
+		return page, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *ProductServiceMock) Update(ctx context.Context, product *domain.Product) error {
 	args := m.Called(ctx, product)
 	return args.Error(0)
 }
 
-func (m *ProductServiceMock) Delete(ctx context.Context, id uuid.UUID) error {
+func (m *ProductServiceMock) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *ProductServiceMock) SetStatus(ctx context.Context, id uuid.UUID, status domain.ProductStatus) (*domain.Product, error) {
+	args := m.Called(ctx, id, status)
+	if product, ok := args.Get(0).(*domain.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) Restore(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	args := m.Called(ctx, id)
+	if product, ok := args.Get(0).(*domain.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) PurgeDeleted(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ProductServiceMock) ListRevisions(ctx context.Context, id uuid.UUID) ([]*domain.ProductRevision, error) {
 	args := m.Called(ctx, id)
+	if revisions, ok := args.Get(0).([]*domain.ProductRevision); ok {
+		return revisions, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) GetRevision(ctx context.Context, id uuid.UUID, revision int) (*domain.ProductRevision, error) {
+	args := m.Called(ctx, id, revision)
+	if found, ok := args.Get(0).(*domain.ProductRevision); ok {
+		return found, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) DiffRevisions(ctx context.Context, id uuid.UUID, from, to int) (*domain.RevisionDiff, error) {
+	args := m.Called(ctx, id, from, to)
+	if diff, ok := args.Get(0).(*domain.RevisionDiff); ok {
+		return diff, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) RevertToRevision(ctx context.Context, id uuid.UUID, revision int, version int64) (*domain.Product, error) {
+	args := m.Called(ctx, id, revision, version)
+	if product, ok := args.Get(0).(*domain.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) SetSale(ctx context.Context, id uuid.UUID, sale *domain.Sale) (*domain.Product, error) {
+	args := m.Called(ctx, id, sale)
+	if product, ok := args.Get(0).(*domain.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ProductServiceMock) ConvertPrices(ctx context.Context, currency string, products ...*domain.Product) error {
+	args := m.Called(ctx, currency, products)
 	return args.Error(0)
 }
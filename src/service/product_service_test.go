@@ -2,12 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"product-service/src/domain"
 	"product-service/src/repository"
+	"product-service/src/storage"
 	"product-service/test_artefacts/seeder"
 	"product-service/test_artefacts/stubs"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -65,10 +70,14 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	migration, err := os.ReadFile("../../database/000001_create_products_table.up.sql")
-	Expect(err).NotTo(HaveOccurred())
-	_, err = db.Exec(context.Background(), string(migration))
+	migrations, err := filepath.Glob("../../database/*.up.sql")
 	Expect(err).NotTo(HaveOccurred())
+	for _, file := range migrations {
+		migration, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(context.Background(), string(migration))
+		Expect(err).NotTo(HaveOccurred())
+	}
 })
 
 var _ = AfterSuite(func() {
@@ -83,11 +92,13 @@ var _ = Describe("ProductService", func() {
 
 	BeforeEach(func() {
 		ctx = context.Background()
-		productRepo = repository.NewProduct(db)
-		productService = NewProductService(productRepo)
+		productRepo = repository.NewProduct(db, domain.AllocationPriority)
+		blobs, err := storage.NewLocal(GinkgoT().TempDir(), "/media")
+		Expect(err).NotTo(HaveOccurred())
+		productService = NewProductService(productRepo, repository.NewMedia(db), blobs, 30*24*time.Hour)
 		testSeeder = seeder.NewTestSeeder(db)
 
-		_, err := db.Exec(ctx, "TRUNCATE TABLE products RESTART IDENTITY")
+		_, err = db.Exec(ctx, "TRUNCATE TABLE products, categories RESTART IDENTITY CASCADE")
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -96,21 +107,91 @@ var _ = Describe("ProductService", func() {
 			// Arrange: Dados do novo produto
 			name := "Câmara Fantástica"
 			description := "Uma câmara com ótima resolução."
-			price := 1299.99
+			price := domain.Money{Minor: 129999, Currency: "BRL"}
 			stock := 15
 
 			// Act: Chama o método Create do serviço
-			err := productService.Create(ctx, name, description, price, stock)
+			err := productService.Create(ctx, name, description, "CAM-FANT-01", "7891234567895", price, stock, nil, nil)
 
 			// Assert: Verifica se não houve erros
 			Expect(err).NotTo(HaveOccurred())
 
 			// Verify: Verifica se o produto foi de fato criado no banco
-			products, err := productRepo.ListProducts(ctx)
+			page, err := productService.ListProducts(ctx, domain.ProductQuery{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Items).To(HaveLen(1))
+			Expect(page.Items[0].Name).To(Equal(name))
+			Expect(page.Items[0].ID).NotTo(Equal(uuid.Nil))
+			Expect(page.Items[0].SKU).To(Equal("CAM-FANT-01"))
+		})
+
+		It("should reject a barcode with a wrong check digit", func() {
+			err := productService.Create(ctx, "Caneca", "Caneca azul", "CAN-AZ", "7891234567890", domain.Money{Minor: 1990, Currency: "BRL"}, 1, nil, nil)
+
+			Expect(errors.Is(err, domain.ErrInvalidBarcode)).To(BeTrue())
+		})
+
+		It("should validate attributes against the schema of the product's categories", func() {
+			// Arrange: Eletrodomésticos exige a voltagem e Chaleiras, abaixo dele, define o material
+			now := time.Now().UTC()
+			appliances := &domain.Category{ID: uuid.New(), Name: "Eletrodomésticos", Slug: "eletrodomesticos", CreatedAt: now, UpdatedAt: now}
+			kettles := &domain.Category{ID: uuid.New(), ParentID: &appliances.ID, Name: "Chaleiras", Slug: "chaleiras", CreatedAt: now, UpdatedAt: now}
+			categoryRepo := repository.NewCategory(db)
+			Expect(categoryRepo.Create(ctx, appliances)).To(Succeed())
+			Expect(categoryRepo.Create(ctx, kettles)).To(Succeed())
+			attributeRepo := repository.NewAttribute(db)
+			Expect(attributeRepo.SetDefinition(ctx, &domain.AttributeDefinition{CategoryID: appliances.ID, Name: "voltage", Type: domain.AttributeNumber, Unit: "V", Required: true, UpdatedAt: now})).To(Succeed())
+			Expect(attributeRepo.SetDefinition(ctx, &domain.AttributeDefinition{CategoryID: kettles.ID, Name: "material", Type: domain.AttributeEnum, AllowedValues: []string{"inox", "vidro"}, UpdatedAt: now})).To(Succeed())
+			price := domain.Money{Minor: 8990, Currency: "BRL"}
+
+			// Act & Assert: a voltagem herdada é obrigatória e o material tem de ser um dos valores permitidos
+			err := productService.Create(ctx, "Chaleira", "Chaleira elétrica", "CHL-01", "", price, 5, domain.Attributes{"material": "inox"}, []uuid.UUID{kettles.ID})
+			Expect(errors.Is(err, domain.ErrInvalidAttributes)).To(BeTrue())
+			err = productService.Create(ctx, "Chaleira", "Chaleira elétrica", "CHL-01", "", price, 5, domain.Attributes{"voltage": 220, "material": "cobre"}, []uuid.UUID{kettles.ID})
+			Expect(errors.Is(err, domain.ErrInvalidAttributes)).To(BeTrue())
+
+			err = productService.Create(ctx, "Chaleira", "Chaleira elétrica", "CHL-01", "", price, 5, domain.Attributes{"voltage": 220, "material": "inox"}, []uuid.UUID{kettles.ID})
+			Expect(err).NotTo(HaveOccurred())
+			page, err := productService.ListProducts(ctx, domain.ProductQuery{CategoryID: &kettles.ID, Status: domain.StatusDraft})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(page.Items).To(HaveLen(1))
+			Expect(page.Items[0].Attributes).To(HaveKeyWithValue("voltage", float64(220)))
+		})
+
+		It("should reject attributes that no category of the product defines", func() {
+			err := productService.Create(ctx, "Caneca", "Caneca azul", "CAN-AZ", "", domain.Money{Minor: 1990, Currency: "BRL"}, 1, domain.Attributes{"color": "azul"}, nil)
+
+			Expect(errors.Is(err, domain.ErrInvalidAttributes)).To(BeTrue())
+		})
+	})
+
+	Describe("Setting a sale price", func() {
+		It("should return the sale price as the effective price while the sale is running", func() {
+			// Arrange: Cria um produto a 100.00
+			product := stubs.NewProductStub().WithPrice("100.00").Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+			startsAt := time.Now().UTC().Add(-time.Hour)
+
+			// Act: Põe o produto em promoção a partir de agora
+			updated, err := productService.SetSale(ctx, product.ID, &domain.Sale{Price: domain.Money{Minor: 7990, Currency: "BRL"}, StartsAt: &startsAt})
+
+			// Assert: O preço normal mantém-se e o preço efetivo é o promocional
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.OnSale).To(BeTrue())
+			Expect(updated.Price.Amount()).To(Equal("100.00"))
+			Expect(updated.EffectivePrice.Amount()).To(Equal("79.90"))
+			page, err := productService.ListProducts(ctx, domain.ProductQuery{OnSaleOnly: true})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(products).To(HaveLen(1))
-			Expect(products[0].Name).To(Equal(name))
-			Expect(products[0].ID).NotTo(Equal(uuid.Nil))
+			Expect(page.Items).To(HaveLen(1))
+		})
+
+		It("should reject a sale price that is not lower than the regular price", func() {
+			product := stubs.NewProductStub().WithPrice("100.00").Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+
+			_, err := productService.SetSale(ctx, product.ID, &domain.Sale{Price: domain.Money{Minor: 10000, Currency: "BRL"}})
+
+			Expect(errors.Is(err, domain.ErrInvalidSalePrice)).To(BeTrue())
 		})
 	})
 
@@ -122,10 +203,12 @@ var _ = Describe("ProductService", func() {
 
 			updatedProduct := &domain.Product{
 				ID:          productToUpdate.ID,
+				SKU:         productToUpdate.SKU,
 				Name:        "Produto Novo e Melhorado",
 				Description: "Nova descrição",
-				Price:       99.99,
+				Price:       domain.Money{Minor: 9999, Currency: "BRL"},
 				Stock:       10,
+				Version:     1,
 			}
 
 			// Act: Chama o método Update do service
@@ -140,4 +223,23 @@ var _ = Describe("ProductService", func() {
 			Expect(foundProduct.Name).To(Equal("Produto Novo e Melhorado"))
 		})
 	})
+	Describe("Changing stock", func() {
+		It("should reject a reference longer than the stock history can keep", func() {
+			// Arrange
+			product := stubs.NewProductStub().WithStock(10).Get()
+			Expect(testSeeder.InsertProduct(ctx, product)).To(Succeed())
+			reference := strings.Repeat("x", 256)
+
+			// Act
+			_, restockErr := productService.Restock(ctx, product.ID, uuid.Nil, 5, reference)
+			_, adjustErr := productService.AdjustStock(ctx, product.ID, uuid.Nil, 8, reference)
+
+			// Assert: Nenhum movimento é registado
+			Expect(errors.Is(restockErr, domain.ErrReferenceTooLong)).To(BeTrue())
+			Expect(errors.Is(adjustErr, domain.ErrReferenceTooLong)).To(BeTrue())
+			found, err := productRepo.GetProductByID(ctx, product.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Stock).To(Equal(10))
+		})
+	})
 })
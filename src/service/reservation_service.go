@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReservationService interface {
+	Reserve(ctx context.Context, productID uuid.UUID, orderReference string, quantity int, ttl time.Duration) (*domain.Reservation, error)
+	GetReservationByID(ctx context.Context, id uuid.UUID) (*domain.Reservation, error)
+	Commit(ctx context.Context, id uuid.UUID) error
+	Release(ctx context.Context, id uuid.UUID) error
+	ReleaseExpired(ctx context.Context) (int64, error)
+}
+
+type reservationService struct {
+	reservationRepository repository.ReservationRepository
+	defaultTTL            time.Duration
+}
+
+func NewReservationService(reservationRepository repository.ReservationRepository, defaultTTL time.Duration) ReservationService {
+	return &reservationService{reservationRepository: reservationRepository, defaultTTL: defaultTTL}
+}
+
+func (s *reservationService) Reserve(ctx context.Context, productID uuid.UUID, orderReference string, quantity int, ttl time.Duration) (*domain.Reservation, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when reserving stock: %w", domain.ErrInvalidID)
+	}
+	if orderReference == "" || len(orderReference) > maxReferenceLength {
+		return nil, fmt.Errorf("Error when reserving stock: %w", domain.ErrInvalidOrderReference)
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("Error when reserving stock: %w", domain.ErrInvalidQuantity)
+	}
+	if ttl <= 0 {
+		ttl = s.defaultTTL
+	}
+
+	now := time.Now().UTC()
+	reservation := &domain.Reservation{
+		ID:             uuid.New(),
+		ProductID:      productID,
+		OrderReference: orderReference,
+		Quantity:       quantity,
+		Status:         domain.ReservationActive,
+		ExpiresAt:      now.Add(ttl),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.reservationRepository.Reserve(ctx, reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (s *reservationService) GetReservationByID(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when searching for reservation by ID: %w", domain.ErrInvalidID)
+	}
+
+	return s.reservationRepository.GetReservationByID(ctx, id)
+}
+
+func (s *reservationService) Commit(ctx context.Context, id uuid.UUID) error {
+
+	if id == uuid.Nil {
+		return fmt.Errorf("Error when committing reservation: %w", domain.ErrInvalidID)
+	}
+
+	return s.reservationRepository.Commit(ctx, id)
+}
+
+func (s *reservationService) Release(ctx context.Context, id uuid.UUID) error {
+
+	if id == uuid.Nil {
+		return fmt.Errorf("Error when releasing reservation: %w", domain.ErrInvalidID)
+	}
+
+	return s.reservationRepository.Release(ctx, id)
+}
+
+// ReleaseExpired marca como expiradas as reservas cujo TTL terminou, devolvendo
+// as unidades ao stock disponível.
+func (s *reservationService) ReleaseExpired(ctx context.Context) (int64, error) {
+	return s.reservationRepository.ExpireReservations(ctx)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type ReservationServiceMock struct {
+	mock.Mock
+}
+
+func (m *ReservationServiceMock) Reserve(ctx context.Context, productID uuid.UUID, orderReference string, quantity int, ttl time.Duration) (*domain.Reservation, error) {
+	args := m.Called(ctx, productID, orderReference, quantity, ttl)
+	if reservation, ok := args.Get(0).(*domain.Reservation); ok {
+		return reservation, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ReservationServiceMock) GetReservationByID(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+	args := m.Called(ctx, id)
+	if reservation, ok := args.Get(0).(*domain.Reservation); ok {
+		return reservation, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ReservationServiceMock) Commit(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ReservationServiceMock) Release(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ReservationServiceMock) ReleaseExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunPeriodically executa task a cada interval até que ctx seja cancelado.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, task func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Printf("ERROR: %s: interval must be positive, got %s", name, interval)
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := task(ctx); err != nil {
+				log.Printf("ERROR: %s: %v", name, err)
+			}
+		}
+	}
+}
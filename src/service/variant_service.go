@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type VariantService interface {
+	SetProductOptions(ctx context.Context, productID uuid.UUID, options []domain.ProductOption) ([]domain.ProductOption, error)
+	// ListProductOptions e ListVariants só leem produtos no estado status; vazio lê em qualquer estado.
+	ListProductOptions(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]domain.ProductOption, error)
+	Create(ctx context.Context, variant *domain.Variant) error
+	ListVariants(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Variant, error)
+}
+
+type variantService struct {
+	variantRepository repository.VariantRepository
+}
+
+func NewVariantService(variantRepository repository.VariantRepository) VariantService {
+	return &variantService{variantRepository: variantRepository}
+}
+
+func (s *variantService) SetProductOptions(ctx context.Context, productID uuid.UUID, options []domain.ProductOption) ([]domain.ProductOption, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when setting product options: %w", domain.ErrInvalidID)
+	}
+	normalized, err := domain.NormalizeProductOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf("Error when setting product options: %w", err)
+	}
+
+	if err := s.variantRepository.SetProductOptions(ctx, productID, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func (s *variantService) ListProductOptions(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]domain.ProductOption, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing product options: %w", domain.ErrInvalidID)
+	}
+
+	return s.variantRepository.ListProductOptions(ctx, productID, status)
+}
+
+// Create valida o SKU, o preço próprio e o stock; a correspondência com as
+// opções do produto é confirmada pelo repositório na mesma transação da gravação.
+func (s *variantService) Create(ctx context.Context, variant *domain.Variant) error {
+
+	if variant.ProductID == uuid.Nil {
+		return fmt.Errorf("Error creating variant: %w", domain.ErrInvalidID)
+	}
+	variant.SKU = strings.TrimSpace(variant.SKU)
+	if !domain.ValidSKU(variant.SKU) {
+		return fmt.Errorf("Error creating variant: %w", domain.ErrInvalidSKU)
+	}
+	if variant.Price != nil {
+		if err := validatePrice(*variant.Price); err != nil {
+			return fmt.Errorf("Error creating variant: %w", err)
+		}
+	}
+	if variant.Stock < 0 {
+		return fmt.Errorf("Error creating variant: %w", domain.ErrInvalidStock)
+	}
+	for name, value := range variant.Options {
+		variant.Options[name] = strings.TrimSpace(value)
+	}
+
+	variant.ID = uuid.New()
+	variant.CreatedAt = time.Now().UTC()
+	variant.UpdatedAt = variant.CreatedAt
+
+	return s.variantRepository.Create(ctx, variant)
+}
+
+func (s *variantService) ListVariants(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Variant, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when listing variants: %w", domain.ErrInvalidID)
+	}
+
+	return s.variantRepository.ListVariants(ctx, productID, status)
+}
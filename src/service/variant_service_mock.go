@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type VariantServiceMock struct {
+	mock.Mock
+}
+
+func (m *VariantServiceMock) SetProductOptions(ctx context.Context, productID uuid.UUID, options []domain.ProductOption) ([]domain.ProductOption, error) {
+	args := m.Called(ctx, productID, options)
+	if saved, ok := args.Get(0).([]domain.ProductOption); ok {
+		return saved, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *VariantServiceMock) ListProductOptions(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]domain.ProductOption, error) {
+	args := m.Called(ctx, productID, status)
+	if options, ok := args.Get(0).([]domain.ProductOption); ok {
+		return options, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *VariantServiceMock) Create(ctx context.Context, variant *domain.Variant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func (m *VariantServiceMock) ListVariants(ctx context.Context, productID uuid.UUID, status domain.ProductStatus) ([]*domain.Variant, error) {
+	args := m.Called(ctx, productID, status)
+	if variants, ok := args.Get(0).([]*domain.Variant); ok {
+		return variants, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
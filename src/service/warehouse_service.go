@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/src/domain"
+	"product-service/src/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WarehouseService interface {
+	Create(ctx context.Context, code, name string, priority int) (*domain.Warehouse, error)
+	GetWarehouseByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	ListWarehouses(ctx context.Context) ([]*domain.Warehouse, error)
+	Update(ctx context.Context, warehouse *domain.Warehouse) error
+	Transfer(ctx context.Context, productID, fromWarehouseID, toWarehouseID uuid.UUID, quantity int) (*domain.StockTransfer, error)
+	GetProductInventory(ctx context.Context, productID uuid.UUID) (*domain.ProductInventory, error)
+}
+
+type warehouseService struct {
+	warehouseRepository repository.WarehouseRepository
+}
+
+func NewWarehouseService(warehouseRepository repository.WarehouseRepository) WarehouseService {
+	return &warehouseService{warehouseRepository: warehouseRepository}
+}
+
+func (s *warehouseService) Create(ctx context.Context, code, name string, priority int) (*domain.Warehouse, error) {
+
+	code = strings.TrimSpace(code)
+	if code == "" || strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("Error creating warehouse: %w", domain.ErrParametersMissing)
+	}
+
+	warehouse := &domain.Warehouse{
+		ID:        uuid.New(),
+		Code:      code,
+		Name:      name,
+		Priority:  priority,
+		Active:    true,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err := s.warehouseRepository.Create(ctx, warehouse); err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+func (s *warehouseService) GetWarehouseByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+
+	if id == uuid.Nil {
+		return nil, fmt.Errorf("Error when searching for warehouse by ID: %w", domain.ErrInvalidID)
+	}
+
+	return s.warehouseRepository.GetWarehouseByID(ctx, id)
+}
+
+func (s *warehouseService) ListWarehouses(ctx context.Context) ([]*domain.Warehouse, error) {
+	return s.warehouseRepository.ListWarehouses(ctx)
+}
+
+func (s *warehouseService) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+
+	if warehouse.ID == uuid.Nil {
+		return fmt.Errorf("Error updating warehouse: %w", domain.ErrInvalidID)
+	}
+	warehouse.Code = strings.TrimSpace(warehouse.Code)
+	if warehouse.Code == "" || strings.TrimSpace(warehouse.Name) == "" {
+		return fmt.Errorf("Error updating warehouse: %w", domain.ErrParametersMissing)
+	}
+
+	warehouse.UpdatedAt = time.Now().UTC()
+
+	return s.warehouseRepository.Update(ctx, warehouse)
+}
+
+func (s *warehouseService) Transfer(ctx context.Context, productID, fromWarehouseID, toWarehouseID uuid.UUID, quantity int) (*domain.StockTransfer, error) {
+
+	if productID == uuid.Nil || fromWarehouseID == uuid.Nil || toWarehouseID == uuid.Nil {
+		return nil, fmt.Errorf("Error when transferring stock: %w", domain.ErrInvalidID)
+	}
+	if fromWarehouseID == toWarehouseID {
+		return nil, fmt.Errorf("Error when transferring stock: %w", domain.ErrSameWarehouseTransfer)
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("Error when transferring stock: %w", domain.ErrInvalidQuantity)
+	}
+
+	transfer := &domain.StockTransfer{
+		ID:              uuid.New(),
+		ProductID:       productID,
+		FromWarehouseID: fromWarehouseID,
+		ToWarehouseID:   toWarehouseID,
+		Quantity:        quantity,
+		CreatedAt:       time.Now().UTC(),
+	}
+
+	if err := s.warehouseRepository.Transfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func (s *warehouseService) GetProductInventory(ctx context.Context, productID uuid.UUID) (*domain.ProductInventory, error) {
+
+	if productID == uuid.Nil {
+		return nil, fmt.Errorf("Error when searching for product inventory: %w", domain.ErrInvalidID)
+	}
+
+	return s.warehouseRepository.GetProductInventory(ctx, productID)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"product-service/src/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type WarehouseServiceMock struct {
+	mock.Mock
+}
+
+func (m *WarehouseServiceMock) Create(ctx context.Context, code, name string, priority int) (*domain.Warehouse, error) {
+	args := m.Called(ctx, code, name, priority)
+	if warehouse, ok := args.Get(0).(*domain.Warehouse); ok {
+		return warehouse, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *WarehouseServiceMock) GetWarehouseByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	args := m.Called(ctx, id)
+	if warehouse, ok := args.Get(0).(*domain.Warehouse); ok {
+		return warehouse, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *WarehouseServiceMock) ListWarehouses(ctx context.Context) ([]*domain.Warehouse, error) {
+	args := m.Called(ctx)
+	if warehouses, ok := args.Get(0).([]*domain.Warehouse); ok {
+		return warehouses, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *WarehouseServiceMock) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+	args := m.Called(ctx, warehouse)
+	return args.Error(0)
+}
+
+func (m *WarehouseServiceMock) Transfer(ctx context.Context, productID, fromWarehouseID, toWarehouseID uuid.UUID, quantity int) (*domain.StockTransfer, error) {
+	args := m.Called(ctx, productID, fromWarehouseID, toWarehouseID, quantity)
+	if transfer, ok := args.Get(0).(*domain.StockTransfer); ok {
+		return transfer, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *WarehouseServiceMock) GetProductInventory(ctx context.Context, productID uuid.UUID) (*domain.ProductInventory, error) {
+	args := m.Called(ctx, productID)
+	if inventory, ok := args.Get(0).(*domain.ProductInventory); ok {
+		return inventory, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStorage guarda ficheiros por chave (ex: "products/<id>/<media>.jpg").
+// As chaves usam "/" como separador e nunca começam por "/" nem contêm "..".
+type BlobStorage interface {
+	// Put grava o conteúdo na chave, substituindo o que lá estiver.
+	Put(ctx context.Context, key string, content io.Reader) error
+	// Open devolve o conteúdo da chave, ou domain.ErrMediaNotFound quando não existe.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete remove a chave; remover uma chave inexistente não é um erro.
+	Delete(ctx context.Context, key string) error
+	// URL devolve o endereço público da chave.
+	URL(key string) string
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"product-service/src/domain"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage_PutOpenDelete(t *testing.T) {
+	// Arrange: Cria o armazenamento num diretório temporário.
+	blobs, err := NewLocal(t.TempDir(), "https://cdn.example.com/media/")
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	// Act: Grava e lê um ficheiro.
+	assert.NoError(t, blobs.Put(ctx, "products/a/b.png", strings.NewReader("conteúdo")))
+	content, err := blobs.Open(ctx, "products/a/b.png")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	content.Close()
+
+	// Assert: Verifica o conteúdo, o endereço público e a remoção.
+	assert.Equal(t, "conteúdo", string(data))
+	assert.Equal(t, "https://cdn.example.com/media/products/a/b.png", blobs.URL("products/a/b.png"))
+	assert.NoError(t, blobs.Delete(ctx, "products/a/b.png"))
+	assert.NoError(t, blobs.Delete(ctx, "products/a/b.png"))
+	_, err = blobs.Open(ctx, "products/a/b.png")
+	assert.True(t, errors.Is(err, domain.ErrMediaNotFound))
+}
+
+func TestLocalStorage_RejectsKeysOutsideTheDirectory(t *testing.T) {
+	// Arrange: Cria o armazenamento num diretório temporário.
+	blobs, err := NewLocal(t.TempDir(), "/media")
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	// Act & Assert: Chaves que sairiam do diretório são recusadas.
+	for _, key := range []string{"../segredo", "/etc/passwd", "products/../../segredo", ""} {
+		assert.Error(t, blobs.Put(ctx, key, strings.NewReader("x")), key)
+		_, err := blobs.Open(ctx, key)
+		assert.True(t, errors.Is(err, domain.ErrMediaNotFound), key)
+	}
+
+	// Assert: Um diretório também não é um ficheiro.
+	assert.NoError(t, blobs.Put(ctx, "products/a.png", strings.NewReader("x")))
+	_, err = blobs.Open(ctx, "products")
+	assert.True(t, errors.Is(err, domain.ErrMediaNotFound))
+}
@@ -16,7 +16,8 @@ func NewTestSeeder(db *pgxpool.Pool) *TestSeeder {
 }
 
 func (s *TestSeeder) InsertProduct(ctx context.Context, product *domain.Product) error {
-	query := `INSERT INTO products (id, name, description, price, stock, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
-	_, err := s.db.Exec(ctx, query, product.ID, product.Name, product.Description, product.Price, product.Stock, product.CreatedAt, product.UpdatedAt)
+	query := `INSERT INTO products (id, sku, barcode, name, description, status, price, currency, stock, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7::text::numeric, $8, $9, $10, $11)`
+	_, err := s.db.Exec(ctx, query, product.ID, product.SKU, product.Barcode, product.Name, product.Description, product.Status, product.Price.Amount(), product.Price.Currency, product.Stock, product.CreatedAt, product.UpdatedAt)
 	return err
 }
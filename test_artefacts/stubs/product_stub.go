@@ -14,16 +14,20 @@ type ProductStub struct {
 
 func NewProductStub() *ProductStub {
 	f := faker.New()
+	id := uuid.New()
 
 	return &ProductStub{
 		product: &domain.Product{
-			ID:          uuid.New(),
+			ID:          id,
+			SKU:         "SKU-" + id.String()[:8],
 			Name:        f.Person().Name(),
 			Description: f.Lorem().Sentence(10),
-			Price:       f.Float64(2, 10, 1000),
+			Status:      domain.StatusActive,
+			Price:       domain.Money{Minor: int64(f.IntBetween(1000, 100000)), Currency: "BRL"},
 			Stock:       f.IntBetween(1, 100),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			Version:     1,
 		},
 	}
 }
@@ -38,11 +42,35 @@ func (s *ProductStub) WithName(name string) *ProductStub {
 	return s
 }
 
-func (s *ProductStub) WithPrice(price float64) *ProductStub {
+func (s *ProductStub) WithSKU(sku string) *ProductStub {
+	s.product.SKU = sku
+	return s
+}
+
+func (s *ProductStub) WithBarcode(barcode string) *ProductStub {
+	s.product.Barcode = barcode
+	return s
+}
+
+func (s *ProductStub) WithStatus(status domain.ProductStatus) *ProductStub {
+	s.product.Status = status
+	return s
+}
+
+func (s *ProductStub) WithPrice(amount string) *ProductStub {
+	price, err := domain.ParseMoney(amount, "BRL")
+	if err != nil {
+		panic(err)
+	}
 	s.product.Price = price
 	return s
 }
 
+func (s *ProductStub) WithStock(stock int) *ProductStub {
+	s.product.Stock = stock
+	return s
+}
+
 func (s *ProductStub) Get() *domain.Product {
 	return s.product
 }